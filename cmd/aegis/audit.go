@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aegis/aegis-cli/pkg/agentclient"
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// newAuditCommand создает группу команд аудита конфигурации контейнеров
+func newAuditCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: i18n.T("Аудит конфигурации контейнеров"),
+	}
+	cmd.AddCommand(
+		newAuditRunCommand(a),
+		newAuditListCommand(a),
+	)
+	return cmd
+}
+
+// newAuditRunCommand создает команду запуска аудита
+func newAuditRunCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: i18n.T("Запуск аудита конфигурации на хосте"),
+		Args:  cobra.NoArgs,
+	}
+	hostID := cmd.Flags().String("host", "", i18n.T("ID хоста для аудита"))
+	containerID := cmd.Flags().String("container", "", i18n.T("ID контейнера (по умолчанию все запущенные контейнеры)"))
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger := a.store, a.logger
+
+		if *hostID == "" {
+			i18n.Println("Ошибка: необходимо указать ID хоста")
+			i18n.Println("Использование: aegis audit run --host HOST_ID [--container CONTAINER_ID]")
+			return
+		}
+
+		host, err := store.GetHost(*hostID)
+		if err != nil {
+			logger.WithError(err).WithField("host_id", *hostID).Error("Хост не найден")
+			i18n.Fprintf(os.Stderr, "Ошибка: хост с ID=%s не найден\n", *hostID)
+			return
+		}
+
+		// Результаты аудита одного контейнера заменяют только его предыдущие результаты
+		if *containerID != "" {
+			container, err := store.GetContainer(*containerID)
+			if err != nil {
+				logger.WithError(err).WithField("container_id", *containerID).Error("Контейнер не найден")
+				i18n.Fprintf(os.Stderr, "Ошибка: контейнер с ID=%s не найден\n", *containerID)
+				return
+			}
+			*containerID = container.ID
+		}
+
+		result, err := agentclient.New(host).Audit(*containerID)
+		if err != nil {
+			logger.WithError(err).WithField("host_id", *hostID).Error("Ошибка аудита контейнеров")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		for i := range result.Findings {
+			result.Findings[i].HostID = host.ID
+		}
+
+		if err := store.SaveAuditFindings(host.ID, *containerID, result.Findings); err != nil {
+			logger.WithError(err).WithField("host_id", *hostID).Error("Ошибка сохранения результатов аудита")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		i18n.Printf("Проверено контейнеров: %d, нарушений: %d\n\n", result.Containers, len(result.Findings))
+		printAuditFindings(result.Findings)
+	}
+	return cmd
+}
+
+// newAuditListCommand создает команду вывода результатов аудита
+func newAuditListCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("Результаты аудита"),
+		Args:  cobra.NoArgs,
+	}
+	hostID := cmd.Flags().String("host", "", i18n.T("ID хоста для фильтрации"))
+	containerID := cmd.Flags().String("container", "", i18n.T("ID контейнера для фильтрации"))
+	severity := cmd.Flags().String("severity", "", i18n.T("Серьезность (CRITICAL, HIGH, MEDIUM, LOW)"))
+	groupBy := cmd.Flags().String("group-by", "", i18n.T("Группировка по Kubernetes: namespace или workload"))
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger, out := a.store, a.logger, a.out
+
+		if err := validateGroupBy(*groupBy); err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		findings, err := store.ListAuditFindings(*hostID, *containerID, strings.ToUpper(*severity))
+		if err != nil {
+			logger.WithError(err).Error("Ошибка получения результатов аудита")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		if *groupBy != "" {
+			entries := make([]severityEntry, 0, len(findings))
+			for _, f := range findings {
+				entries = append(entries, severityEntry{containerID: f.ContainerID, severity: f.Severity})
+			}
+			printWorkloadGroups(out, *groupBy, entries, store)
+			return
+		}
+
+		// В формате для человека нарушения группируются по контейнерам
+		if out.Human() {
+			if len(findings) == 0 {
+				i18n.Println("Нарушения не найдены")
+				return
+			}
+			printAuditFindings(findings)
+			return
+		}
+
+		table := &output.Table{
+			Columns: []output.Column{
+				{Key: "id", Header: "ID"},
+				{Key: "container_name", Header: i18n.T("Контейнер")},
+				{Key: "rule_id", Header: i18n.T("Правило")},
+				{Key: "severity", Header: i18n.T("Серьезность")},
+				{Key: "title", Header: i18n.T("Название")},
+				{Key: "details", Header: i18n.T("Описание")},
+			},
+		}
+		for _, f := range findings {
+			table.Row(f.ID, f.ID, f.ContainerName, f.RuleID, f.Severity, f.Title, f.Details)
+		}
+		renderOutput(out, findings, table)
+	}
+	return cmd
+}
+
+// printAuditFindings выводит нарушения правил аудита, сгруппированные по контейнерам
+func printAuditFindings(findings []models.AuditFinding) {
+	currentContainer := ""
+	for _, f := range findings {
+		if f.ContainerID != currentContainer {
+			currentContainer = f.ContainerID
+			shortID := f.ContainerID
+			if len(shortID) > 12 {
+				shortID = shortID[:12]
+			}
+			i18n.Printf("Контейнер: %s (%s)\n", f.ContainerName, shortID)
+			fmt.Println(strings.Repeat("-", 80))
+		}
+
+		fmt.Printf("[%s] %-6s %s\n", f.Severity, f.RuleID, f.Title)
+		fmt.Printf("    %s\n", f.Details)
+		i18n.Printf("    Исправление: %s\n", f.Remediation)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aegis/aegis-cli/pkg/config"
+	"github.com/aegis/aegis-cli/pkg/db"
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/sbom"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// severities - значения флагов --severity и --min-severity
+var severities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
+
+// newCompletionCommand создает команду вывода скрипта автодополнения для оболочки
+func newCompletionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion bash|zsh|fish",
+		Short: i18n.T("Скрипт автодополнения для bash, zsh или fish"),
+		Long: i18n.T(`Выводит скрипт автодополнения команд, флагов и идентификаторов хостов,
+контейнеров и сканирований из локальной БД.
+
+Подключение:
+  bash: source <(aegis completion bash)
+  zsh:  aegis completion zsh > "${fpath[1]}/_aegis"
+  fish: aegis completion fish > ~/.config/fish/completions/aegis.fish`),
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		// Скрипту автодополнения не нужны конфигурация и БД
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			default:
+				return i18n.Errorf("неизвестная оболочка: %s (допустимо: bash, zsh, fish)", args[0])
+			}
+		},
+	}
+}
+
+// registerCompletions подключает автодополнение значений к известным флагам команды
+func (a *app) registerCompletions(cmd *cobra.Command) {
+	completions := map[string]cobra.CompletionFunc{
+		"host":         a.completeHostIDs,
+		"container":    a.completeContainerIDs,
+		"scan":         a.completeScanIDs,
+		"severity":     cobra.FixedCompletions(severities, cobra.ShellCompDirectiveNoFileComp),
+		"min-severity": cobra.FixedCompletions(severities, cobra.ShellCompDirectiveNoFileComp),
+		"group-by":     cobra.FixedCompletions([]string{groupByNamespace, groupByWorkload}, cobra.ShellCompDirectiveNoFileComp),
+		"scanners":     cobra.FixedCompletions([]string{models.ScannerVuln, models.ScannerSecret, models.ScannerMisconfig}, cobra.ShellCompDirectiveNoFileComp),
+		"event":        cobra.FixedCompletions(models.HookEvents, cobra.ShellCompDirectiveNoFileComp),
+		"payload":      cobra.FixedCompletions([]string{models.HookPayloadFull, models.HookPayloadSummary}, cobra.ShellCompDirectiveNoFileComp),
+		"type":         cobra.FixedCompletions([]string{models.HookTypeScript, models.HookTypeWebhook}, cobra.ShellCompDirectiveNoFileComp),
+		"format":       cobra.FixedCompletions([]string{sbom.FormatCycloneDX, sbom.FormatSPDX}, cobra.ShellCompDirectiveNoFileComp),
+	}
+	for name, fn := range completions {
+		if cmd.Flags().Lookup(name) != nil {
+			cmd.RegisterFlagCompletionFunc(name, fn)
+		}
+	}
+}
+
+// firstArg ограничивает автодополнение первым позиционным аргументом команды
+func firstArg(fn cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return fn(cmd, args, toComplete)
+	}
+}
+
+// completionStore открывает БД для автодополнения. Баннер и журнал не выводятся,
+// чтобы не смешиваться с вариантами, которые читает оболочка
+func (a *app) completionStore() (*db.Store, error) {
+	cfg, err := config.LoadCliConfig(a.configPath)
+	if err != nil {
+		return nil, err
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return db.NewStore(cfg, logger)
+}
+
+// completeHostIDs дополняет ID хостов, в описании варианта - имя и адрес хоста
+func (a *app) completeHostIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := a.completionStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer store.Close()
+
+	hosts, err := store.ListHosts()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []string
+	for _, host := range hosts {
+		if strings.HasPrefix(host.ID, toComplete) {
+			completions = append(completions, fmt.Sprintf("%s\t%s (%s)", host.ID, host.Name, host.Address))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeContainerIDs дополняет ID контейнеров хоста из флага --host или всех хостов
+func (a *app) completeContainerIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := a.completionStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer store.Close()
+
+	var hostIDs []string
+	if flag := cmd.Flags().Lookup("host"); flag != nil && flag.Value.String() != "" {
+		hostIDs = append(hostIDs, flag.Value.String())
+	} else {
+		hosts, err := store.ListHosts()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		for _, host := range hosts {
+			hostIDs = append(hostIDs, host.ID)
+		}
+	}
+
+	var completions []string
+	for _, hostID := range hostIDs {
+		containers, err := store.ListContainers(hostID)
+		if err != nil {
+			continue
+		}
+		for _, container := range containers {
+			if strings.HasPrefix(container.ID, toComplete) {
+				completions = append(completions, fmt.Sprintf("%s\t%s (%s)", container.ID, container.Name, container.Image))
+			}
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeScanIDs дополняет ID сканирований с учетом флагов --host и --container
+func (a *app) completeScanIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := a.completionStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer store.Close()
+
+	var hostID, containerID string
+	if flag := cmd.Flags().Lookup("host"); flag != nil {
+		hostID = flag.Value.String()
+	}
+	if flag := cmd.Flags().Lookup("container"); flag != nil {
+		containerID = flag.Value.String()
+	}
+	scans, err := store.ListScans(hostID, containerID)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []string
+	for _, scan := range scans {
+		if strings.HasPrefix(scan.ID, toComplete) {
+			completions = append(completions, fmt.Sprintf("%s\t%s %s", scan.ID, scan.Status, scan.StartedAt.Format("2006-01-02 15:04")))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/db"
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/output"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newContainersCommand создает группу команд работы с контейнерами
+func newContainersCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "containers",
+		Short: i18n.T("Работа с контейнерами"),
+	}
+	cmd.AddCommand(newContainersListCommand(a))
+	return cmd
+}
+
+// newContainersListCommand создает команду получения списка контейнеров от агента
+func newContainersListCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("Список контейнеров хоста"),
+		Args:  cobra.NoArgs,
+	}
+	hostID := cmd.Flags().String("host", "", i18n.T("ID хоста"))
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		listContainers(*hostID, a.store, a.logger, a.out)
+	}
+	return cmd
+}
+
+// listContainers запрашивает контейнеры у агента, сохраняет их в БД и выводит список
+func listContainers(hostID string, store *db.Store, logger *logrus.Logger, out output.Options) {
+	// Проверка обязательных параметров
+	if hostID == "" {
+		i18n.Println("Ошибка: необходимо указать ID хоста")
+		i18n.Println("Использование: aegis containers list --host HOST_ID")
+		return
+	}
+
+	// Проверка существования хоста
+	host, err := store.GetHost(hostID)
+	if err != nil {
+		logger.WithError(err).WithField("host_id", hostID).Error("Хост не найден")
+		i18n.Fprintf(os.Stderr, "Ошибка: хост с ID=%s не найден\n", hostID)
+		return
+	}
+
+	// Формирование URL для запроса к агенту
+	url := fmt.Sprintf("http://%s:%d/containers", host.Address, host.Port)
+
+	// Выполнение HTTP запроса
+	logger.WithFields(logrus.Fields{
+		"host_id": hostID,
+		"url":     url,
+	}).Info("Запрос списка контейнеров от агента")
+
+	resp, err := http.Get(url)
+	if err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"host_id": hostID,
+			"url":     url,
+		}).Error("Ошибка запроса к агенту")
+		i18n.Fprintf(os.Stderr, "Ошибка подключения к агенту: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	// Проверка статуса ответа
+	if resp.StatusCode != http.StatusOK {
+		logger.WithFields(logrus.Fields{
+			"host_id":     hostID,
+			"url":         url,
+			"status_code": resp.StatusCode,
+		}).Error("Агент вернул ошибку")
+		i18n.Fprintf(os.Stderr, "Ошибка: агент вернул статус %d\n", resp.StatusCode)
+		return
+	}
+
+	// Декодирование ответа
+	var containerResponse models.ContainerListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&containerResponse); err != nil {
+		logger.WithError(err).WithField("host_id", hostID).Error("Ошибка декодирования ответа агента")
+		i18n.Fprintf(os.Stderr, "Ошибка декодирования ответа: %v\n", err)
+		return
+	}
+
+	// Обновление контейнеров в базе данных
+	for i := range containerResponse.Containers {
+		container := &containerResponse.Containers[i]
+
+		// Добавляем хост ID и время обновления
+		container.HostID = hostID
+		container.UpdatedAt = time.Now()
+
+		// Проверяем, существует ли контейнер в базе
+		existingContainer, err := store.GetContainer(container.ID)
+		if err == nil {
+			// Контейнер существует, обновляем статус
+			existingContainer.Status = container.Status
+			existingContainer.Runtime = container.Runtime
+			existingContainer.PodName = container.PodName
+			existingContainer.PodNamespace = container.PodNamespace
+			existingContainer.WorkloadKind = container.WorkloadKind
+			existingContainer.WorkloadName = container.WorkloadName
+			existingContainer.UpdatedAt = time.Now()
+			if err := store.UpdateContainer(existingContainer); err != nil {
+				logger.WithError(err).WithFields(logrus.Fields{
+					"host_id":      hostID,
+					"container_id": container.ID,
+				}).Error("Ошибка обновления контейнера в БД")
+			}
+		} else {
+			// Контейнер не существует, добавляем
+			container.CreatedAt = time.Now()
+			if err := store.AddContainer(container); err != nil {
+				logger.WithError(err).WithFields(logrus.Fields{
+					"host_id":      hostID,
+					"container_id": container.ID,
+				}).Error("Ошибка добавления контейнера в БД")
+			}
+		}
+	}
+
+	// Вывод списка контейнеров
+	table := &output.Table{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID", Width: 15, Prefix: 12},
+			{Key: "name", Header: i18n.T("Имя"), Width: 40, Max: 38},
+			{Key: "image", Header: i18n.T("Образ"), Width: 30, Max: 28},
+			{Key: "runtime", Header: i18n.T("Среда"), Width: 12},
+			{Key: "status", Header: i18n.T("Статус"), Width: 10},
+			{Key: "kubernetes", Header: "Kubernetes", Width: 30, Max: 28},
+			{Key: "workload", Header: i18n.T("Рабочая нагрузка"), Wide: true},
+		},
+		Empty: i18n.T("Контейнеры не найдены"),
+	}
+	for _, container := range containerResponse.Containers {
+		// Для контейнеров Kubernetes показываем namespace и рабочую нагрузку
+		kubernetes := "-"
+		if container.PodName != "" {
+			kubernetes = fmt.Sprintf("%s/%s", container.PodNamespace, container.WorkloadName)
+		}
+		table.Row(container.ID, container.ID, container.Name, container.Image, container.Runtime, container.Status, kubernetes, container.Workload())
+	}
+	renderOutput(out, containerResponse.Containers, table)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// newFindingsCommand создает группу команд secrets или misconfig для находок вида kind
+func newFindingsCommand(a *app, kind string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: i18n.T("Найденные секреты"),
+	}
+	if kind == models.FindingKindMisconfig {
+		cmd.Use = "misconfig"
+		cmd.Short = i18n.T("Найденные ошибки конфигурации")
+	}
+	cmd.AddCommand(newFindingsListCommand(a, kind))
+	return cmd
+}
+
+// newFindingsListCommand создает команду вывода списка находок вида kind
+func newFindingsListCommand(a *app, kind string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("Список найденных секретов"),
+		Args:  cobra.NoArgs,
+	}
+	if kind == models.FindingKindMisconfig {
+		cmd.Short = i18n.T("Список найденных ошибок конфигурации")
+	}
+	cmd.Long = cmd.Short + "\n\n" + i18n.T("С флагом --verbose под каждой находкой выводятся описание и рекомендации по исправлению.")
+	hostID := cmd.Flags().String("host", "", i18n.T("ID хоста для фильтрации"))
+	containerID := cmd.Flags().String("container", "", i18n.T("ID контейнера для фильтрации"))
+	scanID := cmd.Flags().String("scan", "", i18n.T("ID сканирования для фильтрации"))
+	severity := cmd.Flags().String("severity", "", i18n.T("Серьезность (CRITICAL, HIGH, MEDIUM, LOW)"))
+	// Глобальный флаг --verbose добавляет описание и рекомендации по исправлению
+	verbose := &a.verbose
+	groupBy := cmd.Flags().String("group-by", "", i18n.T("Группировка по Kubernetes: namespace или workload"))
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger, out := a.store, a.logger, a.out
+
+		title := i18n.T("секретов")
+		if kind == models.FindingKindMisconfig {
+			title = i18n.T("ошибок конфигурации")
+		}
+
+		if err := validateGroupBy(*groupBy); err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		findings, err := store.ListFindings(kind, *hostID, *containerID, *scanID, strings.ToUpper(*severity))
+		if err != nil {
+			logger.WithError(err).WithField("kind", kind).Error("Ошибка получения списка находок")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		if out.Human() && len(findings) > 0 {
+			i18n.Printf("Найдено %s: %d\n\n", title, len(findings))
+		}
+
+		if *groupBy != "" {
+			entries := make([]severityEntry, 0, len(findings))
+			for _, f := range findings {
+				entries = append(entries, severityEntry{containerID: f.ContainerID, severity: f.Severity})
+			}
+			printWorkloadGroups(out, *groupBy, entries, store)
+			return
+		}
+
+		// Описание и рекомендации выводятся под каждой строкой, поэтому --verbose
+		// печатает таблицу вручную
+		if out.Human() && *verbose {
+			printFindingsVerbose(findings)
+			return
+		}
+
+		table := &output.Table{
+			Columns: []output.Column{
+				{Key: "id", Header: "ID", Width: 15, Prefix: 12},
+				{Key: "rule_id", Header: i18n.T("Правило"), Width: 25, Max: 23},
+				{Key: "severity", Header: i18n.T("Серьезность"), Width: 10},
+				{Key: "target", Header: i18n.T("Объект"), Width: 35},
+				{Key: "title", Header: i18n.T("Название"), Width: 30},
+				{Key: "location", Header: i18n.T("Расположение"), Wide: true},
+			},
+			Empty: i18n.T("Ничего не найдено"),
+		}
+		for _, f := range findings {
+			target := f.Target
+			if out.Format == output.FormatTable {
+				target = shortenTarget(target)
+			}
+			table.Row(f.ID, f.ID, f.RuleID, f.Severity, target, f.Title, f.LocationText())
+		}
+		renderOutput(out, findings, table)
+	}
+	return cmd
+}
+
+// shortenTarget сокращает путь к объекту находки, сохраняя его окончание
+func shortenTarget(target string) string {
+	if len(target) > 33 {
+		return "..." + target[len(target)-30:]
+	}
+	return target
+}
+
+// printFindingsVerbose выводит находки с описанием и рекомендациями по исправлению
+func printFindingsVerbose(findings []models.Finding) {
+	if len(findings) == 0 {
+		i18n.Println("Ничего не найдено")
+		return
+	}
+
+	fmt.Printf("%-15s %-25s %-10s %-35s %-30s\n", "ID", i18n.T("Правило"), i18n.T("Серьезность"), i18n.T("Объект"), i18n.T("Название"))
+	fmt.Println(strings.Repeat("-", 119))
+
+	for _, f := range findings {
+		shortID := f.ID
+		if len(shortID) > 12 {
+			shortID = shortID[:12]
+		}
+
+		rule := f.RuleID
+		if len(rule) > 23 {
+			rule = rule[:20] + "..."
+		}
+
+		fmt.Printf("%-15s %-25s %-10s %-35s %-30s\n", shortID, rule, f.Severity, shortenTarget(f.Target), f.Title)
+
+		if location := f.LocationText(); location != "" {
+			i18n.Printf("    Расположение: %s\n", location)
+		}
+		if f.Description != "" {
+			i18n.Printf("    Описание: %s\n", f.Description)
+		}
+		if f.Resolution != "" {
+			i18n.Printf("    Исправление: %s\n", f.Resolution)
+		}
+	}
+}
@@ -0,0 +1,925 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/agentclient"
+	"github.com/aegis/aegis-cli/pkg/db"
+	"github.com/aegis/aegis-cli/pkg/hooks"
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/output"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newHookCommand создает группу команд управления хуками
+func newHookCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook",
+		Short: i18n.T("Управление хуками"),
+	}
+	cmd.AddCommand(
+		newHookListCommand(a),
+		newHookAddCommand(a),
+		newHookRemoveCommand(a),
+		newHookUpdateCommand(a),
+		newHookPushCommand(a),
+		newHookHistoryCommand(a),
+		newHookDeadLettersCommand(a),
+	)
+	return cmd
+}
+
+// newHookListCommand создает команду вывода списка хуков
+func newHookListCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("Список хуков"),
+		Args:  cobra.NoArgs,
+	}
+	hostID := cmd.Flags().String("host", "", i18n.T("ID хоста: показать хуки, которые выполняет агент"))
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger, out := a.store, a.logger, a.out
+
+		// Получение списка хуков из БД CLI или с агента
+		var hooks []models.Hook
+		var err error
+		if *hostID != "" {
+			host, hostErr := store.GetHost(*hostID)
+			if hostErr != nil {
+				logger.WithError(hostErr).WithField("host_id", *hostID).Error("Хост не найден")
+				i18n.Fprintf(os.Stderr, "Ошибка: хост с ID=%s не найден\n", *hostID)
+				return
+			}
+			hooks, err = agentclient.New(host).ListHooks()
+		} else {
+			hooks, err = store.ListHooks()
+		}
+		if err != nil {
+			logger.WithError(err).Error("Ошибка получения списка хуков")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		// Секрет подписи и значения заголовков не выводятся
+		for i := range hooks {
+			hooks[i] = maskHookSecrets(hooks[i])
+		}
+
+		// Фильтры и источник выводятся под строкой хука только в обычной таблице
+		if out.Format != output.FormatTable || !out.Human() {
+			renderHooks(out, hooks)
+			return
+		}
+
+		// Вывод информации о хуках
+		if len(hooks) == 0 {
+			i18n.Println("Хуки не найдены")
+			return
+		}
+
+		fmt.Printf("%-36s %-20s %-16s %-8s %-40s %-10s %-10s\n", "ID", i18n.T("Имя"), i18n.T("Событие"), i18n.T("Тип"), i18n.T("Скрипт / URL"), i18n.T("Таймаут"), i18n.T("Активен"))
+		fmt.Println(strings.Repeat("-", 150))
+		for _, hook := range hooks {
+			enabled := i18n.T("Нет")
+			if hook.Enabled {
+				enabled = i18n.T("Да")
+			}
+
+			hookType := models.HookTypeScript
+			target := hook.ScriptPath
+			if hook.IsWebhook() {
+				hookType = models.HookTypeWebhook
+				target = hook.URL
+			}
+
+			// Сокращаем путь к скрипту или URL, если он слишком длинный
+			if len(target) > 38 {
+				target = "..." + target[len(target)-35:]
+			}
+
+			fmt.Printf("%-36s %-20s %-16s %-8s %-40s %-10d %-10s\n",
+				hook.ID, hook.Name, hook.Event, hookType, target, hook.TimeoutSeconds, enabled)
+			if filters := hookFilters(hook); filters != "" {
+				i18n.Printf("  Фильтры: %s\n", filters)
+			}
+			switch hook.Source {
+			case models.HookSourceConfig:
+				i18n.Println("  Источник: конфигурация агента")
+			case models.HookSourceAPI:
+				i18n.Println("  Источник: aegis hook push")
+			}
+		}
+	}
+	return cmd
+}
+
+// newHookAddCommand создает команду добавления хука
+func newHookAddCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: i18n.T("Добавление хука"),
+		Args:  cobra.NoArgs,
+	}
+	name := cmd.Flags().String("name", "", i18n.T("Имя хука"))
+	event := cmd.Flags().String("event", "", i18n.Sprintf("Событие (%s)", strings.Join(models.HookEvents, ", ")))
+	hookType := cmd.Flags().String("type", models.HookTypeScript, i18n.T("Тип хука: script или webhook"))
+	scriptPath := cmd.Flags().String("script", "", i18n.T("Путь к скрипту"))
+	hookURL := cmd.Flags().String("url", "", i18n.T("URL для отправки события (для webhook)"))
+	headers := hookHeaders{}
+	cmd.Flags().Var(headers, "header", i18n.T("HTTP-заголовок webhook в формате Имя=Значение (можно указать несколько раз)"))
+	secret := cmd.Flags().String("secret", "", i18n.T("Секрет для подписи HMAC-SHA256 (для webhook)"))
+	templatePath := cmd.Flags().String("payload-template", "", i18n.T("Файл шаблона тела запроса (для webhook)"))
+	timeout := cmd.Flags().Int("timeout", 30, i18n.T("Таймаут выполнения в секундах"))
+	payload := cmd.Flags().String("payload", models.HookPayloadFull, i18n.T("Данные события: full или summary"))
+	hostFilter := cmd.Flags().String("host", "", i18n.T("Выполнять только для хоста с указанным именем или ID"))
+	imagePattern := cmd.Flags().String("image", "", i18n.T("Выполнять только для образов по шаблону, например registry.local/*"))
+	minSeverity := cmd.Flags().String("min-severity", "", i18n.T("Минимальная серьезность находок (CRITICAL, HIGH, MEDIUM, LOW)"))
+	threshold := cmd.Flags().Int("threshold", 0, i18n.T("Минимальное количество находок не ниже --min-severity (по умолчанию 1)"))
+	runAsUser := cmd.Flags().String("user", "", i18n.T("Пользователь, от имени которого выполняется скрипт"))
+	runAsGroup := cmd.Flags().String("group", "", i18n.T("Группа, от имени которой выполняется скрипт"))
+	workDir := cmd.Flags().String("workdir", "", i18n.T("Рабочий каталог скрипта"))
+	envAllowlist := cmd.Flags().String("env", "", i18n.T("Переменные окружения агента, доступные скрипту, через запятую (PREFIX* - по префиксу)"))
+	maxOutput := cmd.Flags().Int("max-output", 0, i18n.T("Максимальный размер вывода скрипта в байтах (по умолчанию 65536)"))
+	cpuLimit := cmd.Flags().Int("cpu-limit", 0, i18n.T("Лимит процессорного времени скрипта в секундах"))
+	memoryLimit := cmd.Flags().Int("memory-limit", 0, i18n.T("Лимит памяти скрипта в МиБ"))
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger := a.store, a.logger
+
+		// Проверка обязательных параметров
+		target := *scriptPath
+		if *hookType == models.HookTypeWebhook {
+			target = *hookURL
+		}
+		if *name == "" || *event == "" || target == "" {
+			i18n.Println("Ошибка: необходимо указать имя, событие и путь к скрипту (или URL для webhook)")
+			i18n.Println("Использование: aegis hook add --name ИМЯ --event СОБЫТИЕ --script ПУТЬ [--timeout СЕКУНДЫ] [--payload full|summary]")
+			i18n.Println("               aegis hook add --name ИМЯ --event СОБЫТИЕ --type webhook --url URL [--header Имя=Значение] [--secret СЕКРЕТ] [--payload-template ФАЙЛ]")
+			i18n.Println("Фильтры: [--host ХОСТ] [--image ШАБЛОН] [--min-severity СЕРЬЕЗНОСТЬ] [--threshold N]")
+			i18n.Println("Ограничения скрипта: [--user ПОЛЬЗОВАТЕЛЬ] [--group ГРУППА] [--workdir КАТАЛОГ] [--env ПЕРЕМЕННЫЕ] [--max-output БАЙТ] [--cpu-limit СЕКУНДЫ] [--memory-limit МИБ]")
+			i18n.Println("Доступные события:", strings.Join(models.HookEvents, ", "))
+			return
+		}
+
+		// Проверка корректности указанного события
+		if !models.IsValidHookEvent(*event) {
+			i18n.Println("Ошибка: некорректное событие")
+			i18n.Println("Доступные события:", strings.Join(models.HookEvents, ", "))
+			return
+		}
+
+		if err := validateHookPayload(*payload); err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		// Создание новой записи хука
+		hook := &models.Hook{
+			ID:              uuid.New().String(),
+			Name:            *name,
+			Event:           *event,
+			Type:            *hookType,
+			ScriptPath:      *scriptPath,
+			URL:             *hookURL,
+			Headers:         models.HookHeaders(headers),
+			Secret:          *secret,
+			TimeoutSeconds:  *timeout,
+			Enabled:         true,
+			Payload:         *payload,
+			HostFilter:      *hostFilter,
+			ImagePattern:    *imagePattern,
+			MinSeverity:     strings.ToUpper(*minSeverity),
+			Threshold:       *threshold,
+			RunAsUser:       *runAsUser,
+			RunAsGroup:      *runAsGroup,
+			WorkDir:         *workDir,
+			EnvAllowlist:    models.ParseHookEnvList(*envAllowlist),
+			MaxOutputBytes:  *maxOutput,
+			CPULimitSeconds: *cpuLimit,
+			MemoryLimitMB:   *memoryLimit,
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+
+		if err := hooks.ValidateFilters(hook); err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+		if err := hooks.ValidateSandbox(hook); err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		if *templatePath != "" {
+			tmpl, err := os.ReadFile(*templatePath)
+			if err != nil {
+				i18n.Fprintf(os.Stderr, "Ошибка чтения шаблона: %v\n", err)
+				return
+			}
+			hook.PayloadTemplate = string(tmpl)
+		}
+
+		if err := validateHookTarget(hook); err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		// Сохранение хука в БД
+		if err := store.AddHook(hook); err != nil {
+			logger.WithError(err).Error("Ошибка добавления хука")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		i18n.Printf("Хук добавлен: ID=%s, Имя=%s, Событие=%s\n", hook.ID, hook.Name, hook.Event)
+		if !models.IsCLIHookEvent(hook.Event) {
+			i18n.Println("Событие формирует агент: чтобы хук выполнялся, выполните aegis hook push --host HOST_ID")
+		}
+	}
+	return cmd
+}
+
+// newHookRemoveCommand создает команду удаления хука
+func newHookRemoveCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove HOOK_ID",
+		Short: i18n.T("Удаление хука"),
+		Args:  cobra.MaximumNArgs(1),
+	}
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger := a.store, a.logger
+
+		// Проверка наличия ID хука
+		if len(args) < 1 {
+			i18n.Println("Ошибка: необходимо указать ID хука")
+			i18n.Println("Использование: aegis hook remove HOOK_ID")
+			return
+		}
+
+		hookID := args[0]
+
+		// Проверка существования хука
+		_, err := store.GetHook(hookID)
+		if err != nil {
+			logger.WithError(err).WithField("hook_id", hookID).Error("Хук не найден")
+			i18n.Fprintf(os.Stderr, "Ошибка: хук с ID=%s не найден\n", hookID)
+			return
+		}
+
+		// Удаление хука
+		if err := store.DeleteHook(hookID); err != nil {
+			logger.WithError(err).WithField("hook_id", hookID).Error("Ошибка удаления хука")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		i18n.Printf("Хук с ID=%s успешно удален\n", hookID)
+	}
+	return cmd
+}
+
+// newHookUpdateCommand создает команду изменения хука
+func newHookUpdateCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update HOOK_ID",
+		Short: i18n.T("Изменение параметров хука"),
+		Args:  cobra.MaximumNArgs(1),
+	}
+	name := cmd.Flags().String("name", "", i18n.T("Имя хука"))
+	event := cmd.Flags().String("event", "", i18n.Sprintf("Событие (%s)", strings.Join(models.HookEvents, ", ")))
+	hookType := cmd.Flags().String("type", "", i18n.T("Тип хука: script или webhook"))
+	scriptPath := cmd.Flags().String("script", "", i18n.T("Путь к скрипту"))
+	hookURL := cmd.Flags().String("url", "", i18n.T("URL для отправки события (для webhook)"))
+	headers := hookHeaders{}
+	cmd.Flags().Var(headers, "header", i18n.T("HTTP-заголовок webhook в формате Имя=Значение, заменяет текущие (можно указать несколько раз)"))
+	secret := cmd.Flags().String("secret", "", i18n.T("Секрет для подписи HMAC-SHA256 (для webhook)"))
+	templatePath := cmd.Flags().String("payload-template", "", i18n.T("Файл шаблона тела запроса (для webhook)"))
+	timeout := cmd.Flags().Int("timeout", 0, i18n.T("Таймаут выполнения в секундах"))
+	enabled := cmd.Flags().Bool("enabled", false, i18n.T("Статус активации (true/false)"))
+	payload := cmd.Flags().String("payload", "", i18n.T("Данные события: full или summary"))
+	hostFilter := cmd.Flags().String("host", "", i18n.T("Выполнять только для хоста с указанным именем или ID (пусто - для всех)"))
+	imagePattern := cmd.Flags().String("image", "", i18n.T("Выполнять только для образов по шаблону (пусто - для всех)"))
+	minSeverity := cmd.Flags().String("min-severity", "", i18n.T("Минимальная серьезность находок (CRITICAL, HIGH, MEDIUM, LOW)"))
+	threshold := cmd.Flags().Int("threshold", 0, i18n.T("Минимальное количество находок не ниже --min-severity"))
+	runAsUser := cmd.Flags().String("user", "", i18n.T("Пользователь, от имени которого выполняется скрипт (пусто - пользователь агента)"))
+	runAsGroup := cmd.Flags().String("group", "", i18n.T("Группа, от имени которой выполняется скрипт"))
+	workDir := cmd.Flags().String("workdir", "", i18n.T("Рабочий каталог скрипта"))
+	envAllowlist := cmd.Flags().String("env", "", i18n.T("Переменные окружения агента, доступные скрипту, через запятую"))
+	maxOutput := cmd.Flags().Int("max-output", 0, i18n.T("Максимальный размер вывода скрипта в байтах"))
+	cpuLimit := cmd.Flags().Int("cpu-limit", 0, i18n.T("Лимит процессорного времени скрипта в секундах (0 - без ограничения)"))
+	memoryLimit := cmd.Flags().Int("memory-limit", 0, i18n.T("Лимит памяти скрипта в МиБ (0 - без ограничения)"))
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger := a.store, a.logger
+
+		// Проверка наличия ID хука
+		if len(args) < 1 {
+			i18n.Println("Ошибка: необходимо указать ID хука")
+			i18n.Println("Использование: aegis hook update HOOK_ID [--name ИМЯ] [--event СОБЫТИЕ] [--type script|webhook] [--script ПУТЬ] [--url URL] [--header Имя=Значение] [--secret СЕКРЕТ] [--payload-template ФАЙЛ] [--timeout СЕКУНДЫ] [--enabled true|false] [--payload full|summary] [--host ХОСТ] [--image ШАБЛОН] [--min-severity СЕРЬЕЗНОСТЬ] [--threshold N] [--user ПОЛЬЗОВАТЕЛЬ] [--group ГРУППА] [--workdir КАТАЛОГ] [--env ПЕРЕМЕННЫЕ] [--max-output БАЙТ] [--cpu-limit СЕКУНДЫ] [--memory-limit МИБ]")
+			return
+		}
+
+		hookID := args[0]
+
+		// Получение текущей информации о хуке
+		hook, err := store.GetHook(hookID)
+		if err != nil {
+			logger.WithError(err).WithField("hook_id", hookID).Error("Хук не найден")
+			i18n.Fprintf(os.Stderr, "Ошибка: хук с ID=%s не найден\n", hookID)
+			return
+		}
+
+		if err := validateHookPayload(*payload); err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		// Проверка корректности указанного события
+		flags := cmd.Flags()
+		if flags.Changed("event") && !models.IsValidHookEvent(*event) {
+			i18n.Println("Ошибка: некорректное событие")
+			i18n.Println("Доступные события:", strings.Join(models.HookEvents, ", "))
+			return
+		}
+
+		// Проверка скрипта нужна, только если он был изменен
+		scriptChanged := (flags.Changed("script") && *scriptPath != hook.ScriptPath) ||
+			(flags.Changed("type") && *hookType != hook.Type)
+
+		// Изменяются только указанные параметры
+		if flags.Changed("name") {
+			hook.Name = *name
+		}
+		if flags.Changed("event") {
+			hook.Event = *event
+		}
+		if flags.Changed("type") {
+			hook.Type = *hookType
+		}
+		if flags.Changed("script") {
+			hook.ScriptPath = *scriptPath
+		}
+		if flags.Changed("url") {
+			hook.URL = *hookURL
+		}
+		if flags.Changed("secret") {
+			hook.Secret = *secret
+		}
+		if flags.Changed("timeout") {
+			hook.TimeoutSeconds = *timeout
+		}
+		if flags.Changed("enabled") {
+			hook.Enabled = *enabled
+		}
+		if flags.Changed("payload") {
+			hook.Payload = *payload
+		}
+		if flags.Changed("host") {
+			hook.HostFilter = *hostFilter
+		}
+		if flags.Changed("image") {
+			hook.ImagePattern = *imagePattern
+		}
+		if flags.Changed("min-severity") {
+			hook.MinSeverity = strings.ToUpper(*minSeverity)
+		}
+		if flags.Changed("threshold") {
+			hook.Threshold = *threshold
+		}
+		if flags.Changed("user") {
+			hook.RunAsUser = *runAsUser
+		}
+		if flags.Changed("group") {
+			hook.RunAsGroup = *runAsGroup
+		}
+		if flags.Changed("workdir") {
+			hook.WorkDir = *workDir
+		}
+		if flags.Changed("env") {
+			hook.EnvAllowlist = models.ParseHookEnvList(*envAllowlist)
+		}
+		if flags.Changed("max-output") {
+			hook.MaxOutputBytes = *maxOutput
+		}
+		if flags.Changed("cpu-limit") {
+			hook.CPULimitSeconds = *cpuLimit
+		}
+		if flags.Changed("memory-limit") {
+			hook.MemoryLimitMB = *memoryLimit
+		}
+		hook.UpdatedAt = time.Now()
+
+		if err := hooks.ValidateFilters(hook); err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+		if err := hooks.ValidateSandbox(hook); err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		if len(headers) > 0 {
+			hook.Headers = models.HookHeaders(headers)
+		}
+		if *templatePath != "" {
+			tmpl, err := os.ReadFile(*templatePath)
+			if err != nil {
+				i18n.Fprintf(os.Stderr, "Ошибка чтения шаблона: %v\n", err)
+				return
+			}
+			hook.PayloadTemplate = string(tmpl)
+		}
+
+		if hook.IsWebhook() || scriptChanged {
+			if err := validateHookTarget(hook); err != nil {
+				i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+				return
+			}
+		}
+
+		// Сохранение обновленной информации
+		if err := store.UpdateHook(hook); err != nil {
+			logger.WithError(err).WithField("hook_id", hookID).Error("Ошибка обновления хука")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		enabled_str := i18n.T("неактивен")
+		if hook.Enabled {
+			enabled_str = i18n.T("активен")
+		}
+
+		i18n.Printf("Хук обновлен: ID=%s, Имя=%s, Событие=%s, Статус=%s\n",
+			hook.ID, hook.Name, hook.Event, enabled_str)
+	}
+	return cmd
+}
+
+// newHookPushCommand создает команду передачи хуков на агент
+func newHookPushCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "push",
+		Short: i18n.T("Передача хуков на агент"),
+		Args:  cobra.NoArgs,
+	}
+	hostID := cmd.Flags().String("host", "", i18n.T("ID хоста с агентом"))
+	hookID := cmd.Flags().String("hook", "", i18n.T("Передать только хук с указанным ID"))
+	prune := cmd.Flags().Bool("prune", false, i18n.T("Удалить с агента хуки, ранее переданные из CLI и удаленные из БД CLI"))
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger := a.store, a.logger
+
+		if *hostID == "" {
+			i18n.Println("Ошибка: необходимо указать ID хоста")
+			i18n.Println("Использование: aegis hook push --host HOST_ID [--hook HOOK_ID] [--prune]")
+			return
+		}
+
+		host, err := store.GetHost(*hostID)
+		if err != nil {
+			logger.WithError(err).WithField("host_id", *hostID).Error("Хост не найден")
+			i18n.Fprintf(os.Stderr, "Ошибка: хост с ID=%s не найден\n", *hostID)
+			return
+		}
+
+		localHooks, err := store.ListHooks()
+		if err != nil {
+			logger.WithError(err).Error("Ошибка получения списка хуков")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		pushHooks(agentclient.New(host).WithToken(a.cfg.AgentHooksToken), host, localHooks, *hookID, *prune, logger)
+	}
+	return cmd
+}
+
+// newHookHistoryCommand создает команду вывода истории выполнения хуков
+func newHookHistoryCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: i18n.T("История выполнения хуков"),
+		Long:  i18n.T("История выполнения хуков.\n\nС флагом --verbose выводится вывод скриптов."),
+		Args:  cobra.NoArgs,
+	}
+	hookID := cmd.Flags().String("hook", "", i18n.T("ID хука для фильтрации"))
+	scanID := cmd.Flags().String("scan", "", i18n.T("ID сканирования для фильтрации"))
+	hostID := cmd.Flags().String("host", "", i18n.T("ID хоста: загрузить историю с агента и показать только ее"))
+	failed := cmd.Flags().Bool("failed", false, i18n.T("Показать только неудачные выполнения"))
+	// Глобальный флаг --verbose добавляет вывод скриптов
+	verbose := &a.verbose
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger := a.store, a.logger
+
+		// Загрузка новых записей истории с агента
+		if *hostID != "" {
+			host, err := store.GetHost(*hostID)
+			if err != nil {
+				logger.WithError(err).WithField("host_id", *hostID).Error("Хост не найден")
+				i18n.Fprintf(os.Stderr, "Ошибка: хост с ID=%s не найден\n", *hostID)
+				return
+			}
+			if _, err := syncHookExecutions(host, *hookID, *scanID, store); err != nil {
+				logger.WithError(err).WithField("host_id", host.ID).Error("Ошибка загрузки истории хуков с агента")
+				i18n.Fprintf(os.Stderr, "Предупреждение: %v\n", err)
+			}
+		}
+
+		status := ""
+		if *failed {
+			status = models.HookStatusFailure
+		}
+
+		executions, err := store.FilterHookExecutions(*hookID, *scanID, *hostID, status)
+		if err != nil {
+			logger.WithError(err).Error("Ошибка получения истории хуков")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		renderHookExecutions(a.out, executions, *verbose)
+	}
+	return cmd
+}
+
+// newHookDeadLettersCommand создает команду вывода недоставленных событий webhook
+func newHookDeadLettersCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dead-letters",
+		Short: i18n.T("Недоставленные события webhook"),
+		Long:  i18n.T("Недоставленные события webhook.\n\nС флагом --verbose выводится тело недоставленного запроса."),
+		Args:  cobra.NoArgs,
+	}
+	hostID := cmd.Flags().String("host", "", i18n.T("ID хоста с агентом"))
+	hookID := cmd.Flags().String("hook", "", i18n.T("ID хука для фильтрации"))
+	// Глобальный флаг --verbose добавляет тело недоставленного запроса
+	verbose := &a.verbose
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger := a.store, a.logger
+
+		if *hostID == "" {
+			i18n.Println("Ошибка: необходимо указать ID хоста")
+			i18n.Println("Использование: aegis hook dead-letters --host HOST_ID [--hook HOOK_ID] [--verbose]")
+			return
+		}
+
+		host, err := store.GetHost(*hostID)
+		if err != nil {
+			logger.WithError(err).WithField("host_id", *hostID).Error("Хост не найден")
+			i18n.Fprintf(os.Stderr, "Ошибка: хост с ID=%s не найден\n", *hostID)
+			return
+		}
+
+		letters, err := agentclient.New(host).ListHookDeadLetters(*hookID)
+		if err != nil {
+			logger.WithError(err).WithField("host_id", host.ID).Error("Ошибка получения недоставленных событий")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		renderHookDeadLetters(a.out, letters, *verbose)
+	}
+	return cmd
+}
+
+// pushHooks передает хуки из БД CLI на агент через client. Хуки на события CLI пропускаются:
+// их выполняет CLI. При prune с агента удаляются хуки, добавленные через API и отсутствующие в БД CLI
+func pushHooks(client *agentclient.Client, host *models.Host, localHooks []models.Hook, hookID string, prune bool, logger *logrus.Logger) {
+	local := make(map[string]bool, len(localHooks))
+	var pushed, failed int
+	for i := range localHooks {
+		hook := &localHooks[i]
+		local[hook.ID] = true
+		if hookID != "" && hook.ID != hookID {
+			continue
+		}
+
+		if models.IsCLIHookEvent(hook.Event) {
+			i18n.Printf("Пропущен %s (%s): событие %s выполняется CLI\n", hook.Name, hook.ID, hook.Event)
+			continue
+		}
+
+		if err := client.SaveHook(hook); err != nil {
+			failed++
+			logger.WithError(err).WithFields(logrus.Fields{
+				"host_id": host.ID,
+				"hook_id": hook.ID,
+			}).Error("Ошибка передачи хука на агент")
+			i18n.Fprintf(os.Stderr, "Ошибка: %s (%s): %v\n", hook.Name, hook.ID, err)
+			continue
+		}
+
+		pushed++
+		i18n.Printf("Передан %s (%s)\n", hook.Name, hook.ID)
+	}
+
+	if hookID != "" && !local[hookID] {
+		i18n.Fprintf(os.Stderr, "Ошибка: хук с ID=%s не найден\n", hookID)
+		return
+	}
+
+	var removed int
+	if prune {
+		remoteHooks, err := client.ListHooks()
+		if err != nil {
+			logger.WithError(err).WithField("host_id", host.ID).Error("Ошибка получения хуков агента")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+		}
+		for _, hook := range remoteHooks {
+			if hook.Source != models.HookSourceAPI || local[hook.ID] {
+				continue
+			}
+			if err := client.DeleteHook(hook.ID); err != nil {
+				failed++
+				i18n.Fprintf(os.Stderr, "Ошибка удаления %s (%s): %v\n", hook.Name, hook.ID, err)
+				continue
+			}
+			removed++
+			i18n.Printf("Удален с агента %s (%s)\n", hook.Name, hook.ID)
+		}
+	}
+
+	i18n.Printf("Хост %s: передано %d, удалено %d, ошибок %d\n", host.Name, pushed, removed, failed)
+}
+
+// maskHookSecrets заменяет секрет подписи и значения HTTP-заголовков хука на "***"
+func maskHookSecrets(hook models.Hook) models.Hook {
+	if hook.Secret != "" {
+		hook.Secret = "***"
+	}
+	if len(hook.Headers) > 0 {
+		headers := make(models.HookHeaders, len(hook.Headers))
+		for name := range hook.Headers {
+			headers[name] = "***"
+		}
+		hook.Headers = headers
+	}
+	return hook
+}
+
+// renderHooks выводит хуки в формате, выбранном флагами вывода
+func renderHooks(out output.Options, hooks []models.Hook) {
+	table := &output.Table{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID", Width: 36},
+			{Key: "name", Header: i18n.T("Имя"), Width: 20},
+			{Key: "event", Header: i18n.T("Событие"), Width: 16},
+			{Key: "type", Header: i18n.T("Тип"), Width: 8},
+			{Key: "target", Header: i18n.T("Скрипт / URL"), Width: 40},
+			{Key: "timeout_seconds", Header: i18n.T("Таймаут"), Width: 10},
+			{Key: "enabled", Header: i18n.T("Активен"), Width: 10},
+			{Key: "filters", Header: i18n.T("Фильтры"), Wide: true},
+			{Key: "source", Header: i18n.T("Источник"), Wide: true},
+		},
+		Empty: i18n.T("Хуки не найдены"),
+	}
+	for _, hook := range hooks {
+		hookType := models.HookTypeScript
+		target := hook.ScriptPath
+		if hook.IsWebhook() {
+			hookType = models.HookTypeWebhook
+			target = hook.URL
+		}
+		// В csv логические значения не переводятся
+		enabled := strconv.FormatBool(hook.Enabled)
+		if out.Format != output.FormatCSV {
+			enabled = i18n.T("Нет")
+			if hook.Enabled {
+				enabled = i18n.T("Да")
+			}
+		}
+		table.Row(hook.ID, hook.ID, hook.Name, hook.Event, hookType, target,
+			strconv.Itoa(hook.TimeoutSeconds), enabled, hookFilters(hook), hook.Source)
+	}
+	renderOutput(out, hooks, table)
+}
+
+// hookFilters возвращает описание фильтров событий хука
+func hookFilters(hook models.Hook) string {
+	var filters []string
+	if hook.HostFilter != "" {
+		filters = append(filters, i18n.T("хост=")+hook.HostFilter)
+	}
+	if hook.ImagePattern != "" {
+		filters = append(filters, i18n.T("образ=")+hook.ImagePattern)
+	}
+	if hook.MinSeverity != "" {
+		filters = append(filters, i18n.T("серьезность>=")+hook.MinSeverity)
+	}
+	if hook.Threshold > 0 {
+		filters = append(filters, i18n.Sprintf("порог=%d", hook.Threshold))
+	}
+	return strings.Join(filters, ", ")
+}
+
+// validateHookPayload проверяет значение флага --payload
+func validateHookPayload(payload string) error {
+	switch payload {
+	case "", models.HookPayloadFull, models.HookPayloadSummary:
+		return nil
+	default:
+		return i18n.Errorf("недопустимое значение --payload: %s (допустимо: full, summary)", payload)
+	}
+}
+
+// hookHeaders накапливает значения повторяемого флага --header
+type hookHeaders map[string]string
+
+func (h hookHeaders) String() string {
+	pairs := make([]string, 0, len(h))
+	for name, value := range h {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (h hookHeaders) Set(value string) error {
+	name, val, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(name) == "" {
+		return i18n.Errorf("ожидается формат Имя=Значение: %s", value)
+	}
+	h[strings.TrimSpace(name)] = val
+	return nil
+}
+
+// Type возвращает тип значения для справки по флагу
+func (h hookHeaders) Type() string {
+	return "name=value"
+}
+
+// validateHookTarget проверяет скрипт или параметры webhook в зависимости от типа хука
+func validateHookTarget(hook *models.Hook) error {
+	switch hook.Type {
+	case "", models.HookTypeScript:
+		if hook.ScriptPath == "" {
+			return i18n.Errorf("необходимо указать путь к скрипту")
+		}
+		if _, err := os.Stat(hook.ScriptPath); os.IsNotExist(err) {
+			return i18n.Errorf("файл скрипта не существует: %s", hook.ScriptPath)
+		}
+		if err := checkExecutable(hook.ScriptPath); err != nil {
+			return i18n.Errorf("файл скрипта не является исполняемым: %s", hook.ScriptPath)
+		}
+		return nil
+	case models.HookTypeWebhook:
+		return hooks.ValidateWebhook(hook)
+	default:
+		return i18n.Errorf("недопустимый тип хука: %s (допустимо: script, webhook)", hook.Type)
+	}
+}
+
+// renderHookDeadLetters выводит недоставленные события webhook в формате, выбранном флагами вывода.
+// Для человека при verbose под строкой события выводятся URL и тело запроса
+func renderHookDeadLetters(out output.Options, letters []models.HookDeadLetter, verbose bool) {
+	if out.Human() {
+		if len(letters) == 0 {
+			i18n.Println("Недоставленные события не найдены")
+			return
+		}
+		printHookDeadLetters(letters, verbose)
+		return
+	}
+
+	table := &output.Table{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID"},
+			{Key: "created_at", Header: i18n.T("Время")},
+			{Key: "hook_id", Header: i18n.T("Хук")},
+			{Key: "event", Header: i18n.T("Событие")},
+			{Key: "scan_id", Header: i18n.T("Сканирование")},
+			{Key: "url", Header: "URL"},
+			{Key: "attempts", Header: i18n.T("Попыток")},
+			{Key: "status_code", Header: i18n.T("Статус")},
+			{Key: "last_error", Header: i18n.T("Ошибка")},
+		},
+	}
+	for _, letter := range letters {
+		table.Row(letter.ID, letter.ID, letter.CreatedAt.Format("2006-01-02 15:04:05"), letter.HookID, letter.Event,
+			letter.ScanID, letter.URL, strconv.Itoa(letter.Attempts), strconv.Itoa(letter.StatusCode), letter.LastError)
+	}
+	renderOutput(out, letters, table)
+}
+
+// printHookDeadLetters выводит таблицу недоставленных событий webhook
+func printHookDeadLetters(letters []models.HookDeadLetter, verbose bool) {
+	fmt.Printf("%-20s %-20s %-16s %-36s %-8s %-8s %s\n", i18n.T("Время"), i18n.T("Хук"), i18n.T("Событие"), i18n.T("Сканирование"), i18n.T("Попыток"), i18n.T("Статус"), i18n.T("Ошибка"))
+	fmt.Println(strings.Repeat("-", 150))
+	for _, letter := range letters {
+		status := "-"
+		if letter.StatusCode > 0 {
+			status = fmt.Sprintf("%d", letter.StatusCode)
+		}
+
+		fmt.Printf("%-20s %-20s %-16s %-36s %-8d %-8s %s\n",
+			letter.CreatedAt.Format("2006-01-02 15:04:05"), letter.HookName, letter.Event,
+			letter.ScanID, letter.Attempts, status, letter.LastError)
+
+		if verbose {
+			fmt.Printf("  URL: %s\n", letter.URL)
+			i18n.Printf("  Тело: %s\n", letter.Payload)
+		}
+	}
+}
+
+// syncHookExecutions загружает с агента историю выполнения хуков и сохраняет новые записи в БД
+func syncHookExecutions(host *models.Host, hookID, scanID string, store *db.Store) (int, error) {
+	executions, err := agentclient.New(host).ListHookExecutions(hookID, scanID)
+	if err != nil {
+		return 0, i18n.Errorf("ошибка получения истории хуков с агента %s: %w", host.Name, err)
+	}
+	return store.ImportHookExecutions(host.ID, executions)
+}
+
+// renderHookExecutions выводит историю выполнения хуков в формате, выбранном флагами вывода.
+// Для человека под строкой выполнения выводятся ошибка и при verbose вывод скрипта
+func renderHookExecutions(out output.Options, executions []models.HookExecution, verbose bool) {
+	if out.Human() {
+		if len(executions) == 0 {
+			i18n.Println("Выполнения хуков не найдены")
+			return
+		}
+		printHookExecutions(executions, verbose)
+		return
+	}
+
+	table := &output.Table{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID"},
+			{Key: "started_at", Header: i18n.T("Начало")},
+			{Key: "hook_id", Header: i18n.T("Хук")},
+			{Key: "event", Header: i18n.T("Событие")},
+			{Key: "host_id", Header: i18n.T("Хост")},
+			{Key: "scan_id", Header: i18n.T("Сканирование")},
+			{Key: "status", Header: i18n.T("Статус")},
+			{Key: "exit_code", Header: i18n.T("Код")},
+			{Key: "duration_ms", Header: i18n.T("Время")},
+			{Key: "error_msg", Header: i18n.T("Ошибка")},
+		},
+	}
+	for _, e := range executions {
+		table.Row(e.ID, e.ID, e.StartedAt.Format("2006-01-02 15:04:05"), e.HookID, e.Event, e.HostID, e.ScanID,
+			e.Status, strconv.Itoa(e.ExitCode), strconv.FormatInt(e.DurationMs, 10), e.ErrorMsg)
+	}
+	renderOutput(out, executions, table)
+}
+
+// printHookExecutions выводит историю выполнения хуков
+func printHookExecutions(executions []models.HookExecution, verbose bool) {
+	fmt.Printf("%-20s %-20s %-16s %-15s %-10s %-6s %-10s\n", i18n.T("Начало"), i18n.T("Хук"), i18n.T("Событие"), i18n.T("Сканирование"), i18n.T("Статус"), i18n.T("Код"), i18n.T("Время"))
+	fmt.Println(strings.Repeat("-", 105))
+
+	for _, e := range executions {
+		name := e.HookName
+		if name == "" {
+			name = e.HookID
+		}
+		if len(name) > 18 {
+			name = name[:15] + "..."
+		}
+
+		shortScanID := e.ScanID
+		if len(shortScanID) > 12 {
+			shortScanID = shortScanID[:12]
+		}
+
+		duration := (time.Duration(e.DurationMs) * time.Millisecond).String()
+		fmt.Printf("%-20s %-20s %-16s %-15s %-10s %-6d %-10s\n",
+			e.StartedAt.Format("2006-01-02 15:04:05"), name, e.Event, shortScanID, e.Status, e.ExitCode, duration)
+
+		if e.ErrorMsg != "" {
+			i18n.Printf("    Ошибка: %s\n", e.ErrorMsg)
+		}
+		if verbose && strings.TrimSpace(e.Output) != "" {
+			for _, line := range strings.Split(strings.TrimRight(e.Output, "\n"), "\n") {
+				fmt.Printf("    | %s\n", line)
+			}
+		}
+	}
+}
+
+// checkExecutable проверяет, является ли файл исполняемым
+func checkExecutable(path string) error {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	// Проверка разрешения на исполнение (для Unix-подобных ОС)
+	if fileInfo.Mode()&0111 == 0 {
+		return i18n.Errorf("файл не имеет разрешения на исполнение")
+	}
+
+	return nil
+}
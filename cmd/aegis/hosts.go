@@ -0,0 +1,694 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/agentclient"
+	"github.com/aegis/aegis-cli/pkg/db"
+	"github.com/aegis/aegis-cli/pkg/hooks"
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/output"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newHostsCommand создает группу команд управления хостами
+func newHostsCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hosts",
+		Short: i18n.T("Управление агентами"),
+	}
+	cmd.AddCommand(
+		newHostsListCommand(a),
+		newHostsAddCommand(a),
+		newHostsRemoveCommand(a),
+		newHostsUpdateCommand(a),
+		newHostsPostureCommand(a),
+		newHostsCheckCommand(a),
+	)
+	return cmd
+}
+
+// newHostsListCommand создает команду вывода списка хостов
+func newHostsListCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("Список хостов"),
+		Args:  cobra.NoArgs,
+	}
+	refresh := cmd.Flags().Bool("refresh", false, i18n.T("Запросить сведения у агентов вместо сохраненных при последней проверке"))
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger, out := a.store, a.logger, a.out
+
+		// Получение списка хостов
+		hosts, err := store.ListHosts()
+		if err != nil {
+			logger.WithError(err).Error("Ошибка получения списка хостов")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		// Вывод информации о хостах
+		table := &output.Table{
+			Columns: []output.Column{
+				{Key: "id", Header: "ID", Width: 36},
+				{Key: "name", Header: i18n.T("Имя"), Width: 20},
+				{Key: "address", Header: i18n.T("Адрес"), Width: 15},
+				{Key: "port", Header: i18n.T("Порт"), Width: 5},
+				{Key: "status", Header: i18n.T("Статус"), Width: 10},
+				{Key: "agent_version", Header: i18n.T("Агент"), Width: 8},
+				{Key: "trivy_db_age_hours", Header: i18n.T("БД trivy"), Width: 9},
+				{Key: "last_seen", Header: i18n.T("Последняя активность"), Width: 20},
+				{Key: "runtime", Header: i18n.T("Среда"), Wide: true},
+				{Key: "trivy_version", Header: "Trivy", Wide: true},
+				{Key: "description", Header: i18n.T("Описание"), Wide: true},
+			},
+			Empty: i18n.T("Хосты не найдены"),
+		}
+		now := time.Now()
+		var stale []string
+		items := make([]hostListItem, 0, len(hosts))
+		for i := range hosts {
+			host := &hosts[i]
+			if *refresh {
+				refreshAgentInfo(host, store, logger)
+			}
+			item := hostListItem{Host: *host, Posture: hostPosture(host, logger)}
+
+			var lastSeen string
+			switch {
+			case !host.LastSeen.IsZero():
+				lastSeen = host.LastSeen.Format("2006-01-02 15:04:05")
+			case out.Format != output.FormatCSV:
+				lastSeen = i18n.T("Нет данных")
+			}
+
+			var dbAge, runtimeName, trivyVersion string
+			if info := hostAgentInfo(host, logger); info != nil {
+				item.AgentInfo = info
+				item.AgentVersion, trivyVersion = info.Version, info.TrivyVersion
+				runtimeName = info.Runtime
+				if info.RuntimeVersion != "" {
+					runtimeName += " " + info.RuntimeVersion
+				}
+				if age, ok := info.TrivyDBAge(now); ok {
+					seconds := int64(age.Seconds())
+					item.TrivyDBAgeSeconds = &seconds
+					item.TrivyDBStale = a.cfg.TrivyDBMaxAge > 0 && age > time.Duration(a.cfg.TrivyDBMaxAge)*time.Hour
+
+					// В csv возраст выводится в часах без отметки об устаревании
+					switch {
+					case out.Format == output.FormatCSV:
+						dbAge = strconv.Itoa(int(age.Hours()))
+					case item.TrivyDBStale:
+						dbAge = formatAge(age) + " !"
+					default:
+						dbAge = formatAge(age)
+					}
+					if item.TrivyDBStale {
+						stale = append(stale, fmt.Sprintf("%s (%s)", host.Name, formatAge(age)))
+					}
+				}
+			}
+			items = append(items, item)
+			table.Row(host.ID, host.ID, host.Name, host.Address, strconv.Itoa(host.Port), host.Status,
+				item.AgentVersion, dbAge, lastSeen, runtimeName, trivyVersion, host.Description)
+		}
+		renderOutput(out, items, table)
+
+		if len(stale) > 0 {
+			i18n.Fprintf(os.Stderr, "Предупреждение: БД уязвимостей trivy старше %d ч на хостах: %s\n",
+				a.cfg.TrivyDBMaxAge, strings.Join(stale, ", "))
+		}
+	}
+	return cmd
+}
+
+// hostListItem - хост в машиночитаемом выводе hosts list: сохраненные в JSON снимок
+// состояния и сведения об агенте разобраны, возраст БД trivy вычислен на момент вывода
+type hostListItem struct {
+	models.Host
+	Posture           *models.HostPosture `json:"posture,omitempty"`
+	AgentInfo         *models.AgentInfo   `json:"agent_info,omitempty"`
+	AgentVersion      string              `json:"agent_version,omitempty"`
+	TrivyDBAgeSeconds *int64              `json:"trivy_db_age_seconds,omitempty"` // nil - время обновления БД неизвестно
+	TrivyDBStale      bool                `json:"trivy_db_stale"`                 // БД старше trivy_db_max_age_hours
+}
+
+// refreshAgentInfo запрашивает сведения у агента хоста и сохраняет их. Недоступный агент
+// или агент без GET /info не считается ошибкой: остаются сведения последней проверки
+func refreshAgentInfo(host *models.Host, store *db.Store, logger *logrus.Logger) {
+	info, err := agentclient.New(host).GetInfo()
+	if err != nil {
+		logger.WithError(err).WithField("host_id", host.ID).Debug("Не удалось получить сведения об агенте")
+		return
+	}
+	if err := store.UpdateHostAgentInfo(host.ID, info); err != nil {
+		logger.WithError(err).WithField("host_id", host.ID).Error("Ошибка сохранения сведений об агенте")
+		return
+	}
+	data, _ := json.Marshal(info)
+	host.AgentInfo = string(data)
+}
+
+// hostAgentInfo разбирает сохраненные сведения об агенте хоста; nil - сведений нет
+func hostAgentInfo(host *models.Host, logger *logrus.Logger) *models.AgentInfo {
+	if host.AgentInfo == "" {
+		return nil
+	}
+	info := &models.AgentInfo{}
+	if err := json.Unmarshal([]byte(host.AgentInfo), info); err != nil {
+		logger.WithError(err).WithField("host_id", host.ID).Warn("Ошибка разбора сведений об агенте")
+		return nil
+	}
+	return info
+}
+
+// hostPosture разбирает сохраненный снимок состояния хоста; nil - снимка нет
+func hostPosture(host *models.Host, logger *logrus.Logger) *models.HostPosture {
+	if host.Posture == "" {
+		return nil
+	}
+	posture := &models.HostPosture{}
+	if err := json.Unmarshal([]byte(host.Posture), posture); err != nil {
+		logger.WithError(err).WithField("host_id", host.ID).Warn("Ошибка разбора сохраненного состояния хоста")
+		return nil
+	}
+	return posture
+}
+
+// formatAge выводит возраст в часах, а начиная с двух суток - в днях
+func formatAge(age time.Duration) string {
+	hours := int(age.Hours())
+	if hours < 48 {
+		return i18n.Sprintf("%d ч", hours)
+	}
+	return i18n.Sprintf("%d д", hours/24)
+}
+
+// newHostsAddCommand создает команду добавления хоста
+func newHostsAddCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: i18n.T("Добавление хоста и установка агента"),
+		Args:  cobra.NoArgs,
+	}
+	name := cmd.Flags().String("name", "", i18n.T("Имя хоста"))
+	address := cmd.Flags().String("address", "", i18n.T("Адрес хоста"))
+	port := cmd.Flags().Int("port", 0, i18n.T("Порт агента (по умолчанию default_agent_port из конфигурации)"))
+	description := cmd.Flags().String("description", "", i18n.T("Описание хоста"))
+	// Новые параметры для установки агента
+	installAgent := cmd.Flags().Bool("install-agent", false, i18n.T("Установить агент на удаленный хост"))
+	sshUser := cmd.Flags().String("ssh-user", "root", i18n.T("SSH пользователь для подключения"))
+	sshKey := cmd.Flags().String("ssh-key", "", i18n.T("Путь к SSH ключу"))
+	sshPort := cmd.Flags().Int("ssh-port", 22, i18n.T("SSH порт"))
+	sshPassword := cmd.Flags().Bool("ssh-password", false, i18n.T("Запросить SSH пароль"))
+	sudoPassword := cmd.Flags().Bool("sudo-password", false, i18n.T("Запросить sudo пароль"))
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger := a.store, a.logger
+
+		// Порт по умолчанию берется из конфигурации, загруженной перед выполнением команды
+		if !cmd.Flags().Changed("port") {
+			*port = a.cfg.DefaultAgentPort
+		}
+
+		// Проверка обязательных параметров
+		if *name == "" || *address == "" {
+			i18n.Println("Ошибка: необходимо указать имя и адрес хоста")
+			i18n.Println("Использование: aegis hosts add --name ИМЯ --address АДРЕС [--port ПОРТ] [--description ОПИСАНИЕ] [--install-agent] [--ssh-user ПОЛЬЗОВАТЕЛЬ] [--ssh-key ПУТЬ] [--ssh-port ПОРТ] [--ssh-password] [--sudo-password]")
+			return
+		}
+
+		// Создание новой записи хоста
+		host := &models.Host{
+			ID:          uuid.New().String(),
+			Name:        *name,
+			Address:     *address,
+			Port:        *port,
+			Status:      "offline", // По умолчанию считаем хост оффлайн до первой проверки
+			CreatedAt:   time.Now(),
+			Description: *description,
+		}
+
+		// Сохранение хоста в БД
+		if err := store.AddHost(host); err != nil {
+			logger.WithError(err).Error("Ошибка добавления хоста")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		i18n.Printf("Хост добавлен: ID=%s, Имя=%s, Адрес=%s:%d\n",
+			host.ID, host.Name, host.Address, host.Port)
+
+		// Установка агента если указан флаг --install-agent
+		if *installAgent {
+			i18n.Println("Начало установки агента на удаленный хост...")
+
+			// Формирование команды для Ansible
+			inventoryFile := fmt.Sprintf("%s,", host.Address)
+			playbookPath := "deploy/ansible/install-agent.yml"
+
+			// Базовая команда ansible-playbook
+			ansibleCmd := []string{
+				"ansible-playbook",
+				"-i", inventoryFile,
+			}
+
+			// Добавление SSH пользователя
+			if *sshUser != "" {
+				ansibleCmd = append(ansibleCmd, "--user", *sshUser)
+			}
+
+			// Добавление SSH ключа
+			if *sshKey != "" {
+				ansibleCmd = append(ansibleCmd, "--private-key", *sshKey)
+			}
+
+			// Добавление SSH порта
+			if *sshPort != 22 {
+				ansibleCmd = append(ansibleCmd, "--port", fmt.Sprintf("%d", *sshPort))
+			}
+
+			// Добавление флагов для запроса паролей
+			if *sshPassword {
+				ansibleCmd = append(ansibleCmd, "--ask-pass")
+			}
+
+			if *sudoPassword {
+				ansibleCmd = append(ansibleCmd, "--become", "--ask-become-pass")
+			} else {
+				// Если sudo пароль не запрашивается, все равно добавляем --become для привилегированного доступа
+				ansibleCmd = append(ansibleCmd, "--become")
+			}
+
+			// Добавление переменных для настройки агента
+			ansibleCmd = append(ansibleCmd,
+				"-e", fmt.Sprintf("agent_port=%d", *port),
+				playbookPath,
+			)
+
+			// Выполнение команды Ansible
+			ansible := exec.Command(ansibleCmd[0], ansibleCmd[1:]...)
+			ansible.Stdout = os.Stdout
+			ansible.Stderr = os.Stderr
+			ansible.Stdin = os.Stdin
+
+			logger.WithField("command", strings.Join(ansibleCmd, " ")).Info("Запуск установки агента")
+
+			if err := ansible.Run(); err != nil {
+				logger.WithError(err).Error("Ошибка установки агента")
+				i18n.Fprintf(os.Stderr, "Ошибка установки агента: %v\n", err)
+				return
+			}
+
+			i18n.Println("Агент успешно установлен на удаленный хост")
+
+			// Обновление статуса хоста
+			host.Status = "online"
+			if err := store.UpdateHost(host); err != nil {
+				logger.WithError(err).Error("Ошибка обновления статуса хоста")
+			}
+		}
+	}
+	return cmd
+}
+
+// newHostsRemoveCommand создает команду удаления хоста
+func newHostsRemoveCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "remove HOST_ID",
+		Short:             i18n.T("Удаление хоста"),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: firstArg(a.completeHostIDs),
+	}
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger := a.store, a.logger
+
+		// Проверка наличия ID хоста
+		if len(args) < 1 {
+			i18n.Println("Ошибка: необходимо указать ID хоста")
+			i18n.Println("Использование: aegis hosts remove HOST_ID")
+			return
+		}
+
+		hostID := args[0]
+
+		// Проверка существования хоста
+		_, err := store.GetHost(hostID)
+		if err != nil {
+			logger.WithError(err).WithField("host_id", hostID).Error("Хост не найден")
+			i18n.Fprintf(os.Stderr, "Ошибка: хост с ID=%s не найден\n", hostID)
+			return
+		}
+
+		// Удаление хоста
+		if err := store.DeleteHost(hostID); err != nil {
+			logger.WithError(err).WithField("host_id", hostID).Error("Ошибка удаления хоста")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		i18n.Printf("Хост с ID=%s успешно удален\n", hostID)
+	}
+	return cmd
+}
+
+// newHostsUpdateCommand создает команду изменения хоста
+func newHostsUpdateCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "update HOST_ID",
+		Short:             i18n.T("Изменение параметров хоста"),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: firstArg(a.completeHostIDs),
+	}
+	name := cmd.Flags().String("name", "", i18n.T("Имя хоста"))
+	address := cmd.Flags().String("address", "", i18n.T("Адрес хоста"))
+	port := cmd.Flags().Int("port", 0, i18n.T("Порт агента"))
+	description := cmd.Flags().String("description", "", i18n.T("Описание хоста"))
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger := a.store, a.logger
+
+		// Проверка наличия ID хоста
+		if len(args) < 1 {
+			i18n.Println("Ошибка: необходимо указать ID хоста")
+			i18n.Println("Использование: aegis hosts update HOST_ID [--name ИМЯ] [--address АДРЕС] [--port ПОРТ] [--description ОПИСАНИЕ]")
+			return
+		}
+
+		hostID := args[0]
+
+		// Получение текущей информации о хосте
+		host, err := store.GetHost(hostID)
+		if err != nil {
+			logger.WithError(err).WithField("host_id", hostID).Error("Хост не найден")
+			i18n.Fprintf(os.Stderr, "Ошибка: хост с ID=%s не найден\n", hostID)
+			return
+		}
+
+		// Обновление указанных параметров хоста
+		if cmd.Flags().Changed("name") {
+			host.Name = *name
+		}
+		if cmd.Flags().Changed("address") {
+			host.Address = *address
+		}
+		if cmd.Flags().Changed("port") {
+			host.Port = *port
+		}
+		if cmd.Flags().Changed("description") {
+			host.Description = *description
+		}
+		host.UpdatedAt = time.Now()
+
+		// Сохранение обновленной информации
+		if err := store.UpdateHost(host); err != nil {
+			logger.WithError(err).WithField("host_id", hostID).Error("Ошибка обновления хоста")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		i18n.Printf("Хост обновлен: ID=%s, Имя=%s, Адрес=%s:%d\n",
+			host.ID, host.Name, host.Address, host.Port)
+	}
+	return cmd
+}
+
+// newHostsPostureCommand создает команду проверки безопасности хоста
+func newHostsPostureCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "posture HOST_ID",
+		Short:             i18n.T("Проверка настроек безопасности хоста"),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: firstArg(a.completeHostIDs),
+	}
+	cached := cmd.Flags().Bool("cached", false, i18n.T("Показать последний сохраненный снимок без обращения к агенту"))
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger, out := a.store, a.logger, a.out
+
+		if len(args) < 1 {
+			i18n.Println("Ошибка: необходимо указать ID хоста")
+			i18n.Println("Использование: aegis hosts posture HOST_ID [--cached]")
+			return
+		}
+
+		hostID := args[0]
+
+		host, err := store.GetHost(hostID)
+		if err != nil {
+			logger.WithError(err).WithField("host_id", hostID).Error("Хост не найден")
+			i18n.Fprintf(os.Stderr, "Ошибка: хост с ID=%s не найден\n", hostID)
+			return
+		}
+
+		var posture *models.HostPosture
+		if !*cached {
+			posture, err = agentclient.New(host).GetHostPosture()
+			if err != nil {
+				logger.WithError(err).WithField("host_id", hostID).Error("Ошибка проверки хоста")
+				i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			} else if err := store.UpdateHostPosture(host.ID, posture); err != nil {
+				logger.WithError(err).WithField("host_id", hostID).Error("Ошибка сохранения состояния хоста")
+			}
+		}
+
+		// Если агент недоступен, показываем последний сохраненный снимок
+		fromCache := posture == nil
+		if fromCache {
+			if host.Posture == "" {
+				i18n.Fprintf(os.Stderr, "Нет сохраненных данных о состоянии хоста\n")
+				return
+			}
+			posture = &models.HostPosture{}
+			if err := json.Unmarshal([]byte(host.Posture), posture); err != nil {
+				i18n.Fprintf(os.Stderr, "Ошибка разбора сохраненного состояния хоста: %v\n", err)
+				return
+			}
+		}
+
+		renderHostPosture(out, host, posture, fromCache)
+	}
+	return cmd
+}
+
+// newHostsCheckCommand создает команду проверки доступности агентов
+func newHostsCheckCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "check [HOST_ID]",
+		Short:             i18n.T("Проверка доступности агентов"),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: firstArg(a.completeHostIDs),
+	}
+	interval := cmd.Flags().Int("interval", 0, i18n.T("Повторять проверку с интервалом в секундах (0 - однократно)"))
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger, out := a.store, a.logger, a.out
+
+		hostID := ""
+		if len(args) > 0 {
+			hostID = args[0]
+		}
+
+		for {
+			checkHosts(out, hostID, store, logger)
+			if *interval <= 0 {
+				return
+			}
+			time.Sleep(time.Duration(*interval) * time.Second)
+		}
+	}
+	return cmd
+}
+
+// checkHosts проверяет доступность агентов, обновляет статус хостов и при его изменении
+// выполняет хуки on_host_offline и on_host_online. Если hostID пуст, проверяются все хосты
+func checkHosts(out output.Options, hostID string, store *db.Store, logger *logrus.Logger) {
+	var hostList []models.Host
+	if hostID != "" {
+		host, err := store.GetHost(hostID)
+		if err != nil {
+			logger.WithError(err).WithField("host_id", hostID).Error("Хост не найден")
+			i18n.Fprintf(os.Stderr, "Ошибка: хост с ID=%s не найден\n", hostID)
+			return
+		}
+		hostList = append(hostList, *host)
+	} else {
+		var err error
+		hostList, err = store.ListHosts()
+		if err != nil {
+			logger.WithError(err).Error("Ошибка получения списка хостов")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+	}
+
+	table := &output.Table{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID", Width: 36},
+			{Key: "name", Header: i18n.T("Имя"), Width: 20},
+			{Key: "status", Header: i18n.T("Статус"), Width: 10},
+			{Key: "previous_status", Header: i18n.T("Изменение"), Width: 20},
+		},
+		Empty: i18n.T("Хосты не найдены"),
+	}
+	results := make([]hostCheckResult, 0, len(hostList))
+	var events []*models.HookEvent
+	for i := range hostList {
+		host := &hostList[i]
+
+		status := "online"
+		if err := agentclient.New(host).Health(); err != nil {
+			status = "offline"
+			logger.WithError(err).WithField("host_id", host.ID).Warn("Агент недоступен")
+		}
+
+		result := hostCheckResult{ID: host.ID, Name: host.Name, Address: host.Address, Status: status}
+		// В csv столбец содержит только прежний статус
+		change := "-"
+		if out.Format == output.FormatCSV {
+			change = ""
+		}
+		if status != host.Status {
+			result.PreviousStatus, result.Changed = host.Status, true
+			change = fmt.Sprintf("%s -> %s", host.Status, status)
+			if out.Format == output.FormatCSV {
+				change = host.Status
+			}
+			event := models.HookEventHostOnline
+			if status == "offline" {
+				event = models.HookEventHostOffline
+			}
+			events = append(events, hostEvent(event, host))
+		}
+
+		host.Status = status
+		if status == "online" {
+			host.LastSeen = time.Now()
+		}
+		if err := store.UpdateHost(host); err != nil {
+			logger.WithError(err).WithField("host_id", host.ID).Error("Ошибка обновления статуса хоста")
+		}
+		if status == "online" {
+			refreshAgentInfo(host, store, logger)
+		}
+
+		results = append(results, result)
+		table.Row(host.ID, host.ID, host.Name, status, change)
+	}
+	renderOutput(out, results, table)
+
+	if len(events) > 0 {
+		executeLocalHooks(events, store, logger)
+	}
+}
+
+// hostCheckResult - результат проверки доступности агента хоста
+type hostCheckResult struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Address        string `json:"address"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status,omitempty"` // Статус до проверки, если он изменился
+	Changed        bool   `json:"changed"`
+}
+
+// hostEvent формирует событие хука, которое относится к хосту, а не к сканированию
+func hostEvent(event string, host *models.Host) *models.HookEvent {
+	return &models.HookEvent{
+		Version:   models.HookEventVersion,
+		Event:     event,
+		Timestamp: time.Now(),
+		Host: models.HookEventHost{
+			ID:       host.ID,
+			Hostname: host.Name,
+			Address:  host.Address,
+		},
+	}
+}
+
+// executeLocalHooks выполняет хуки из БД CLI на события, которые формирует сам CLI
+// (доступность хостов, применение исправлений), и ждет их завершения
+func executeLocalHooks(events []*models.HookEvent, store *db.Store, logger *logrus.Logger) {
+	localHooks, err := store.ListHooks()
+	if err != nil {
+		logger.WithError(err).Error("Ошибка загрузки хуков")
+		return
+	}
+
+	manager := hooks.NewManager(localHooks, store)
+	manager.SetLogger(logger)
+	for _, event := range events {
+		manager.ExecuteHooks(event)
+	}
+	manager.Wait()
+}
+
+// hostPostureReport - отчет о проверке настроек Docker на хосте для машиночитаемых форматов
+type hostPostureReport struct {
+	HostID      string `json:"host_id"`
+	HostName    string `json:"host_name"`
+	HostAddress string `json:"host_address"`
+	Cached      bool   `json:"cached"` // Сохраненный снимок: агент не опрашивался или недоступен
+	models.HostPosture
+}
+
+// renderHostPosture выводит отчет о проверке настроек Docker в формате, выбранном флагами вывода.
+// Для человека проверки дополняются сведениями о daemon и итогом
+func renderHostPosture(out output.Options, host *models.Host, posture *models.HostPosture, cached bool) {
+	if out.Human() {
+		if cached {
+			i18n.Println("Показан сохраненный снимок")
+		}
+		printHostPosture(host, posture)
+		return
+	}
+
+	table := &output.Table{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID"},
+			{Key: "status", Header: i18n.T("Статус")},
+			{Key: "title", Header: i18n.T("Название")},
+			{Key: "details", Header: i18n.T("Описание")},
+		},
+	}
+	for _, check := range posture.Checks {
+		table.Row(check.ID, check.ID, check.Status, check.Title, check.Details)
+	}
+	renderOutput(out, hostPostureReport{
+		HostID:      host.ID,
+		HostName:    host.Name,
+		HostAddress: host.Address,
+		Cached:      cached,
+		HostPosture: *posture,
+	}, table)
+}
+
+// printHostPosture выводит отчет о проверке настроек Docker на хосте
+func printHostPosture(host *models.Host, posture *models.HostPosture) {
+	i18n.Printf("Хост: %s (%s)\n", host.Name, host.Address)
+	fmt.Printf("Docker: %s (API %s)\n", posture.DockerVersion, posture.APIVersion)
+	i18n.Printf("ОС: %s, ядро %s\n", posture.OS, posture.KernelVersion)
+	i18n.Printf("Проверено: %s\n\n", posture.CheckedAt.Format("2006-01-02 15:04:05"))
+
+	var passed, warnings, failed int
+	for _, check := range posture.Checks {
+		switch check.Status {
+		case models.PostureStatusPass:
+			passed++
+		case models.PostureStatusWarn:
+			warnings++
+		case models.PostureStatusFail:
+			failed++
+		}
+
+		fmt.Printf("[%-4s] %-30s %s\n", strings.ToUpper(check.Status), check.Title, check.Details)
+	}
+
+	i18n.Printf("\nИтого: pass %d, warn %d, fail %d\n", passed, warnings, failed)
+}
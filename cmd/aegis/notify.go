@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// newNotifyCommand создает группу команд уведомлений
+func newNotifyCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: i18n.T("Проверка и отправка уведомлений"),
+	}
+	cmd.AddCommand(
+		newNotifyTestCommand(a),
+		newNotifyFlushCommand(a),
+		newNotifyDigestCommand(a),
+		newNotifyServeCommand(a),
+	)
+	return cmd
+}
+
+// newNotifyTestCommand создает команду проверки маршрутизации события
+func newNotifyTestCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: i18n.T("Проверка маршрутизации события из файла JSON"),
+		Args:  cobra.NoArgs,
+	}
+	eventPath := cmd.Flags().String("event", "", i18n.T("Файл уведомления в формате JSON"))
+	preview := cmd.Flags().Bool("preview", false, i18n.T("Показать сообщения каналов, сформированные по шаблонам"))
+	send := cmd.Flags().Bool("send", false, i18n.T("Отправить уведомление в каналы, которые его пропускают"))
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		logger, notificationManager := a.logger, a.notificationManager
+
+		if *eventPath == "" {
+			i18n.Println("Ошибка: необходимо указать файл события")
+			i18n.Println("Использование: aegis notify test --event ФАЙЛ [--preview] [--send]")
+			return
+		}
+
+		data, err := os.ReadFile(*eventPath)
+		if err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка чтения события: %v\n", err)
+			return
+		}
+
+		var notification utils.Notification
+		if err := json.Unmarshal(data, &notification); err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка разбора события: %v\n", err)
+			return
+		}
+		if notification.Event == "" {
+			notification.Event = utils.NotificationScanCompleted
+		}
+		if notification.Title == "" {
+			notification.Title = i18n.N("Aegis: Проверка уведомлений")
+		}
+
+		decisions := notificationManager.Route(&notification)
+		if len(decisions) == 0 {
+			i18n.Println("Каналы уведомлений не настроены")
+			return
+		}
+
+		i18n.Printf("Событие: %s, хост: %s, образ: %s, время: %s\n\n", notification.Event, notification.Host,
+			notification.Image, notification.Timestamp.Local().Format("2006-01-02 15:04:05"))
+		fmt.Printf("%-20s %-12s %s\n", i18n.T("Канал"), i18n.T("Решение"), i18n.T("Причина"))
+		fmt.Println(strings.Repeat("-", 80))
+		for _, decision := range decisions {
+			verdict := i18n.T("отправить")
+			if !decision.Deliver {
+				verdict = i18n.T("пропустить")
+			}
+			fmt.Printf("%-20s %-12s %s\n", decision.Channel, verdict, decision.Reason)
+		}
+
+		if *preview {
+			for _, p := range notificationManager.Preview(&notification) {
+				fmt.Printf("\n--- %s ---\n", p.Channel)
+				if p.Err != nil {
+					i18n.Printf("Ошибка: %v\n", p.Err)
+					continue
+				}
+				fmt.Println(p.Message)
+			}
+		}
+
+		if *send {
+			if err := notificationManager.Dispatch(&notification); err != nil {
+				logger.WithError(err).Error("Ошибка отправки тестового уведомления")
+				i18n.Fprintf(os.Stderr, "\nОшибка отправки: %v\n", err)
+				return
+			}
+			i18n.Println("\nУведомление отправлено или поставлено в очередь каналов")
+		}
+	}
+	return cmd
+}
+
+// newNotifyFlushCommand создает команду отправки сводок из очередей
+func newNotifyFlushCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "flush",
+		Short: i18n.T("Отправка сводок из очередей и дайджеста по расписанию"),
+		Args:  cobra.NoArgs,
+	}
+	now := cmd.Flags().Bool("now", false, i18n.T("Отправить очереди, не дожидаясь окончания окна группировки"))
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		logger, notificationManager := a.logger, a.notificationManager
+
+		statuses, err := notificationManager.Flush(*now)
+		if err != nil {
+			logger.WithError(err).Error("Ошибка отправки очереди уведомлений")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		if len(statuses) == 0 {
+			i18n.Println("Очереди уведомлений пусты")
+		} else {
+			fmt.Printf("%-20s %-12s %-12s %s\n", i18n.T("Канал"), i18n.T("Отправлено"), i18n.T("В очереди"), i18n.T("Состояние"))
+			fmt.Println(strings.Repeat("-", 80))
+			for _, status := range statuses {
+				state := status.Reason
+				if status.Err != nil {
+					state = i18n.T("ошибка: ") + status.Err.Error()
+				}
+				fmt.Printf("%-20s %-12d %-12d %s\n", status.Channel, status.Sent, status.Pending, state)
+			}
+		}
+
+		digest, err := notificationManager.SendDigest(false)
+		if err != nil {
+			logger.WithError(err).Error("Ошибка отправки дайджеста")
+			i18n.Fprintf(os.Stderr, "Ошибка отправки дайджеста: %v\n", err)
+			return
+		}
+		if digest != nil {
+			i18n.Printf("\nДайджест отправлен: сканирований %d, хостов %d\n", digest.Events, len(digest.Hosts))
+		}
+	}
+	return cmd
+}
+
+// newNotifyDigestCommand создает команду отправки дайджеста
+func newNotifyDigestCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: i18n.T("Отправка дайджеста вне расписания"),
+		Args:  cobra.NoArgs,
+	}
+	preview := cmd.Flags().Bool("preview", false, i18n.T("Показать сообщения каналов без отправки"))
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		logger, notificationManager := a.logger, a.notificationManager
+
+		status, err := notificationManager.DigestStatus()
+		if err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+		if status.Enabled {
+			last := i18n.T("не отправлялся")
+			if !status.LastSent.IsZero() {
+				last = status.LastSent.Local().Format("2006-01-02 15:04")
+			}
+			i18n.Printf("Последний дайджест: %s, следующий по расписанию: %s\n\n", last, status.Next.Format("2006-01-02 15:04"))
+		}
+
+		if *preview {
+			digest, err := notificationManager.BuildDigest(time.Time{})
+			if err != nil {
+				i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+				return
+			}
+			for _, p := range notificationManager.Preview(digest) {
+				fmt.Printf("--- %s ---\n", p.Channel)
+				if p.Err != nil {
+					i18n.Printf("Ошибка: %v\n\n", p.Err)
+					continue
+				}
+				fmt.Printf("%s\n\n", p.Message)
+			}
+			return
+		}
+
+		digest, err := notificationManager.SendDigest(true)
+		if err != nil {
+			logger.WithError(err).Error("Ошибка отправки дайджеста")
+			i18n.Fprintf(os.Stderr, "Ошибка отправки дайджеста: %v\n", err)
+			return
+		}
+		i18n.Printf("Дайджест отправлен: сканирований %d, хостов %d\n", digest.Events, len(digest.Hosts))
+	}
+	return cmd
+}
+
+// newNotifyServeCommand создает команду периодической отправки уведомлений
+func newNotifyServeCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: i18n.T("Периодическая отправка сводок и дайджестов"),
+		Args:  cobra.NoArgs,
+	}
+	interval := cmd.Flags().Duration("interval", time.Minute, i18n.T("Интервал проверки очередей и расписания дайджеста"))
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		logger, notificationManager := a.logger, a.notificationManager
+
+		if *interval <= 0 {
+			i18n.Println("Ошибка: интервал должен быть больше нуля")
+			return
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		i18n.Printf("Отправка сводок и дайджестов каждые %s\n", *interval)
+		i18n.Println("Для остановки нажмите Ctrl+C")
+
+		ticker := time.NewTicker(*interval)
+		defer ticker.Stop()
+		for {
+			statuses, err := notificationManager.Flush(false)
+			if err != nil {
+				logger.WithError(err).Error("Ошибка отправки очереди уведомлений")
+			}
+			for _, status := range statuses {
+				if status.Sent > 0 {
+					i18n.Printf("%s %s: отправлено событий %d\n", time.Now().Format("15:04:05"), status.Channel, status.Sent)
+				}
+			}
+
+			if digest, err := notificationManager.SendDigest(false); err != nil {
+				logger.WithError(err).Error("Ошибка отправки дайджеста")
+			} else if digest != nil {
+				i18n.Printf("%s дайджест отправлен: сканирований %d\n", time.Now().Format("15:04:05"), digest.Events)
+			}
+
+			select {
+			case <-ctx.Done():
+				i18n.Println("Отправка уведомлений остановлена")
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+	return cmd
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/output"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newRemediationCommand создает группу команд стратегий исправления
+func newRemediationCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remediation",
+		Short: i18n.T("Стратегии исправления уязвимостей"),
+	}
+	cmd.AddCommand(
+		newRemediationListCommand(a),
+		newRemediationApplyCommand(a),
+	)
+	return cmd
+}
+
+// newRemediationListCommand создает команду вывода стратегий исправления
+func newRemediationListCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("Список стратегий исправления"),
+		Args:  cobra.NoArgs,
+	}
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger, out := a.store, a.logger, a.out
+
+		strategies, err := store.ListRemediationStrategies()
+		if err != nil {
+			logger.WithError(err).Error("Ошибка получения стратегий исправления")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		table := &output.Table{
+			Columns: []output.Column{
+				{Key: "id", Header: "ID", Width: 12},
+				{Key: "name", Header: i18n.T("Имя"), Width: 25},
+				{Key: "type", Header: i18n.T("Тип"), Width: 16},
+				{Key: "estimated_downtime", Header: i18n.T("Простой"), Width: 20},
+				{Key: "command", Header: i18n.T("Команда")},
+				{Key: "description", Header: i18n.T("Описание"), Wide: true},
+			},
+			Empty: i18n.T("Стратегии исправления не найдены"),
+		}
+		for _, strategy := range strategies {
+			table.Row(strategy.ID, strategy.ID, strategy.Name, strategy.Type, strategy.EstimatedDowntime, strategy.Command, strategy.Description)
+		}
+		renderOutput(out, strategies, table)
+	}
+	return cmd
+}
+
+// newRemediationApplyCommand создает команду применения стратегии исправления
+func newRemediationApplyCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply STRATEGY_ID",
+		Short: i18n.T("Применение стратегии исправления к контейнеру"),
+		Args:  cobra.MaximumNArgs(1),
+	}
+	containerID := cmd.Flags().String("container", "", i18n.T("ID контейнера, к которому применяется исправление"))
+	dryRun := cmd.Flags().Bool("dry-run", false, i18n.T("Только показать команду, не выполняя ее"))
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger := a.store, a.logger
+
+		if len(args) < 1 {
+			i18n.Println("Ошибка: необходимо указать ID стратегии")
+			i18n.Println("Использование: aegis remediation apply STRATEGY_ID --container CONTAINER_ID [--dry-run]")
+			return
+		}
+
+		strategyID := args[0]
+
+		if *containerID == "" {
+			i18n.Println("Ошибка: необходимо указать ID контейнера")
+			i18n.Println("Использование: aegis remediation apply STRATEGY_ID --container CONTAINER_ID [--dry-run]")
+			return
+		}
+
+		strategy, err := store.GetRemediationStrategy(strategyID)
+		if err != nil {
+			logger.WithError(err).WithField("strategy_id", strategyID).Error("Стратегия не найдена")
+			i18n.Fprintf(os.Stderr, "Ошибка: стратегия с ID=%s не найдена\n", strategyID)
+			return
+		}
+
+		container, err := store.GetContainer(*containerID)
+		if err != nil {
+			logger.WithError(err).WithField("container_id", *containerID).Error("Контейнер не найден")
+			i18n.Fprintf(os.Stderr, "Ошибка: контейнер с ID=%s не найден\n", *containerID)
+			return
+		}
+
+		argv, err := strategy.RenderArgs(container)
+		if err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+		command := strings.Join(argv, " ")
+
+		if *dryRun {
+			fmt.Println(command)
+			return
+		}
+
+		// Команда выполняется на машине с CLI без оболочки: kubectl и docker используют
+		// ее настройки доступа, а значения параметров передаются отдельными аргументами
+		i18n.Printf("Выполнение: %s\n", command)
+		output, err := exec.Command(argv[0], argv[1:]...).CombinedOutput()
+		fmt.Print(string(output))
+		if err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"strategy_id":  strategy.ID,
+				"container_id": container.ID,
+			}).Error("Ошибка применения исправления")
+			i18n.Fprintf(os.Stderr, "Ошибка применения исправления: %v\n", err)
+			return
+		}
+
+		i18n.Printf("Исправление \"%s\" применено к контейнеру %s\n", strategy.Name, container.Name)
+
+		host := &models.Host{ID: container.HostID}
+		if h, err := store.GetHost(container.HostID); err == nil {
+			host = h
+		}
+		event := hostEvent(models.HookEventRemediationApplied, host)
+		event.Container = container
+		event.Remediation = &models.HookEventRemediation{
+			StrategyID: strategy.ID,
+			Name:       strategy.Name,
+			Type:       strategy.Type,
+			Command:    command,
+			Output:     string(output),
+		}
+		executeLocalHooks([]*models.HookEvent{event}, store, logger)
+	}
+	return cmd
+}
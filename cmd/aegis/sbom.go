@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/output"
+	"github.com/aegis/aegis-cli/pkg/sbom"
+	"github.com/spf13/cobra"
+)
+
+// newSBOMCommand создает группу команд работы с SBOM образов
+func newSBOMCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sbom",
+		Short: i18n.T("SBOM образов"),
+	}
+	cmd.AddCommand(
+		newSBOMListCommand(a),
+		newSBOMExportCommand(a),
+		newSBOMDiffCommand(a),
+		newSBOMSearchCommand(a),
+	)
+	return cmd
+}
+
+// newSBOMListCommand создает команду вывода списка SBOM
+func newSBOMListCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("Список сохраненных SBOM"),
+		Args:  cobra.NoArgs,
+	}
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger, out := a.store, a.logger, a.out
+
+		// Получение списка SBOM
+		sboms, err := store.ListSBOMs()
+		if err != nil {
+			logger.WithError(err).Error("Ошибка получения списка SBOM")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		table := &output.Table{
+			Columns: []output.Column{
+				{Key: "image_digest", Header: i18n.T("Дайджест"), Width: 20},
+				{Key: "image", Header: i18n.T("Образ"), Width: 40, Max: 38},
+				{Key: "format", Header: i18n.T("Формат"), Width: 10},
+				{Key: "created_at", Header: i18n.T("Создан"), Width: 20},
+				{Key: "scan_id", Header: i18n.T("Сканирование"), Wide: true},
+			},
+			Empty: i18n.T("SBOM не найдены"),
+		}
+		for _, s := range sboms {
+			digest := s.ImageDigest
+			if out.Format == output.FormatTable {
+				digest = shortDigest(digest)
+			}
+			table.Row(s.ImageDigest, digest, s.Image, s.Format, s.CreatedAt.Format("2006-01-02 15:04:05"), s.ScanID)
+		}
+		renderOutput(out, sboms, table)
+	}
+	return cmd
+}
+
+// newSBOMExportCommand создает команду выгрузки SBOM образа
+func newSBOMExportCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export IMAGE_DIGEST",
+		Short: i18n.T("Выгрузка SBOM образа"),
+		Args:  cobra.MaximumNArgs(1),
+	}
+	format := cmd.Flags().String("format", sbom.FormatCycloneDX, i18n.T("Формат SBOM (cyclonedx, spdx)"))
+	file := cmd.Flags().String("file", "", i18n.T("Файл для сохранения (по умолчанию вывод в консоль)"))
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger := a.store, a.logger
+
+		// Проверка наличия дайджеста образа
+		if len(args) < 1 {
+			i18n.Println("Ошибка: необходимо указать дайджест образа")
+			i18n.Println("Использование: aegis sbom export IMAGE_DIGEST [--format cyclonedx|spdx] [--file ФАЙЛ]")
+			return
+		}
+
+		digest := args[0]
+
+		if !sbom.IsValidFormat(*format) {
+			i18n.Fprintf(os.Stderr, "Ошибка: неподдерживаемый формат SBOM: %s\n", *format)
+			return
+		}
+
+		record, err := store.GetSBOM(digest, *format)
+		if err != nil {
+			logger.WithError(err).WithField("image_digest", digest).Error("SBOM не найден")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		if *file == "" {
+			fmt.Println(record.Content)
+			return
+		}
+
+		if err := os.WriteFile(*file, []byte(record.Content), 0644); err != nil {
+			logger.WithError(err).WithField("file", *file).Error("Ошибка записи SBOM")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		i18n.Printf("SBOM образа %s (%s) сохранен в %s\n", record.Image, record.Format, *file)
+	}
+	return cmd
+}
+
+// newSBOMDiffCommand создает команду сравнения SBOM двух образов
+func newSBOMDiffCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff IMAGE_DIGEST_OLD IMAGE_DIGEST_NEW",
+		Short: i18n.T("Сравнение пакетов двух образов"),
+		Args:  cobra.MaximumNArgs(2),
+	}
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger, out := a.store, a.logger, a.out
+
+		// Проверка наличия дайджестов образов
+		if len(args) < 2 {
+			i18n.Println("Ошибка: необходимо указать дайджесты двух образов")
+			i18n.Println("Использование: aegis sbom diff IMAGE_DIGEST_OLD IMAGE_DIGEST_NEW")
+			return
+		}
+
+		var packageSets [2][]models.SBOMPackage
+		for i, prefix := range args[:2] {
+			digest, err := store.ResolveImageDigest(prefix)
+			if err != nil {
+				i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+				return
+			}
+
+			packageSets[i], err = store.ListSBOMPackages(digest)
+			if err != nil {
+				logger.WithError(err).WithField("image_digest", digest).Error("Ошибка получения пакетов образа")
+				i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+				return
+			}
+		}
+
+		diff := sbom.Compare(packageSets[0], packageSets[1])
+
+		// Пустые списки выводятся в json как [], а не null
+		if diff.Added == nil {
+			diff.Added = []models.SBOMPackage{}
+		}
+		if diff.Removed == nil {
+			diff.Removed = []models.SBOMPackage{}
+		}
+		if diff.Changed == nil {
+			diff.Changed = []sbom.PackageChange{}
+		}
+
+		table := &output.Table{
+			Columns: []output.Column{
+				{Key: "change", Header: "", Width: 1},
+				{Key: "name", Header: i18n.T("Пакет"), Width: 40},
+				{Key: "type", Header: i18n.T("Тип"), Width: 10},
+				{Key: "old_version", Header: i18n.T("Было"), Width: 20},
+				{Key: "new_version", Header: i18n.T("Стало")},
+			},
+			Empty: i18n.T("Состав образов совпадает"),
+		}
+		for _, p := range diff.Added {
+			table.Row(p.Name, "+", p.Name, p.Type, "", p.Version)
+		}
+		for _, p := range diff.Removed {
+			table.Row(p.Name, "-", p.Name, p.Type, p.Version, "")
+		}
+		for _, c := range diff.Changed {
+			table.Row(c.Name, "~", c.Name, c.Type, c.OldVersion, c.NewVersion)
+		}
+
+		if out.Human() && len(table.Rows) > 0 {
+			i18n.Printf("Добавлено: %d, удалено: %d, изменено: %d\n\n", len(diff.Added), len(diff.Removed), len(diff.Changed))
+		}
+		renderOutput(out, diff, table)
+	}
+	return cmd
+}
+
+// newSBOMSearchCommand создает команду поиска образов по пакету
+func newSBOMSearchCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search",
+		Short: i18n.T("Поиск образов, содержащих пакет"),
+		Args:  cobra.NoArgs,
+	}
+	pkgName := cmd.Flags().String("package", "", i18n.T("Имя пакета"))
+	version := cmd.Flags().String("version", "", i18n.T("Версия пакета"))
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger, out := a.store, a.logger, a.out
+
+		if *pkgName == "" {
+			i18n.Println("Ошибка: необходимо указать имя пакета")
+			i18n.Println("Использование: aegis sbom search --package ИМЯ [--version ВЕРСИЯ]")
+			return
+		}
+
+		packages, err := store.FindSBOMPackages(*pkgName, *version)
+		if err != nil {
+			logger.WithError(err).Error("Ошибка поиска пакетов")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		// Имена образов для найденных дайджестов
+		images := make(map[string]string)
+		if sboms, err := store.ListSBOMs(); err == nil {
+			for _, s := range sboms {
+				images[s.ImageDigest] = s.Image
+			}
+		}
+
+		table := &output.Table{
+			Columns: []output.Column{
+				{Key: "image_digest", Header: i18n.T("Дайджест"), Width: 20},
+				{Key: "image", Header: i18n.T("Образ"), Width: 40, Max: 38},
+				{Key: "version", Header: i18n.T("Версия"), Width: 20},
+				{Key: "type", Header: i18n.T("Тип"), Width: 10},
+				{Key: "name", Header: i18n.T("Пакет"), Wide: true},
+				{Key: "purl", Header: "PURL", Wide: true},
+			},
+			Empty: i18n.T("Образы с указанным пакетом не найдены"),
+		}
+		results := make([]sbomSearchResult, 0, len(packages))
+		for _, p := range packages {
+			results = append(results, sbomSearchResult{SBOMPackage: p, Image: images[p.ImageDigest]})
+			digest := p.ImageDigest
+			if out.Format == output.FormatTable {
+				digest = shortDigest(digest)
+			}
+			table.Row(p.ImageDigest, digest, images[p.ImageDigest], p.Version, p.Type, p.Name, p.PURL)
+		}
+		renderOutput(out, results, table)
+	}
+	return cmd
+}
+
+// sbomSearchResult - пакет, найденный в SBOM образа, с именем образа
+type sbomSearchResult struct {
+	models.SBOMPackage
+	Image string `json:"image"`
+}
+
+// shortDigest сокращает дайджест образа для отображения в таблицах
+func shortDigest(digest string) string {
+	short := strings.TrimPrefix(digest, "sha256:")
+	if len(short) > 12 {
+		short = short[:12]
+	}
+	return short
+}
@@ -0,0 +1,754 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/agentclient"
+	"github.com/aegis/aegis-cli/pkg/db"
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/output"
+	"github.com/aegis/aegis-cli/pkg/sbom"
+	"github.com/aegis/aegis-cli/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newScanCommand создает группу команд сканирования
+func newScanCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: i18n.T("Сканирование контейнеров"),
+	}
+	cmd.AddCommand(
+		newScanRunCommand(a),
+		newScanStatusCommand(a),
+	)
+	return cmd
+}
+
+// newScanRunCommand создает команду запуска сканирования
+func newScanRunCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: i18n.T("Запуск сканирования контейнеров"),
+		Args:  cobra.NoArgs,
+	}
+	hostID := cmd.Flags().String("host", "", i18n.T("ID хоста для сканирования"))
+	containerID := cmd.Flags().String("container", "", i18n.T("ID контейнера для сканирования"))
+	allContainers := cmd.Flags().Bool("all", false, i18n.T("Сканировать все контейнеры хоста"))
+	scannersFlag := cmd.Flags().String("scanners", models.ScannerVuln, i18n.T("Типы сканеров через запятую (vuln, secret, misconfig)"))
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger := a.store, a.logger
+
+		scanners, err := parseScanners(*scannersFlag)
+		if err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		// Проверка обязательных параметров
+		if *hostID == "" {
+			i18n.Println("Ошибка: необходимо указать ID хоста")
+			i18n.Println("Использование: aegis scan run --host HOST_ID [--container CONTAINER_ID|--all] [--scanners vuln,secret,misconfig]")
+			return
+		}
+
+		// Проверка существования хоста
+		host, err := store.GetHost(*hostID)
+		if err != nil {
+			logger.WithError(err).WithField("host_id", *hostID).Error("Хост не найден")
+			i18n.Fprintf(os.Stderr, "Ошибка: хост с ID=%s не найден\n", *hostID)
+			return
+		}
+
+		// Проверка параметров --container и --all
+		if *containerID == "" && !*allContainers {
+			i18n.Println("Ошибка: необходимо указать ID контейнера (--container) или флаг --all")
+			i18n.Println("Использование: aegis scan run --host HOST_ID [--container CONTAINER_ID|--all] [--scanners vuln,secret,misconfig]")
+			return
+		}
+
+		if *containerID != "" && *allContainers {
+			i18n.Println("Ошибка: нельзя одновременно указывать ID контейнера и флаг --all")
+			i18n.Println("Использование: aegis scan run --host HOST_ID [--container CONTAINER_ID|--all] [--scanners vuln,secret,misconfig]")
+			return
+		}
+
+		// Запуск сканирования одного контейнера
+		if *containerID != "" {
+			// Проверка существования контейнера
+			_, err := store.GetContainer(*containerID)
+			if err != nil {
+				logger.WithError(err).WithFields(logrus.Fields{
+					"host_id":      *hostID,
+					"container_id": *containerID,
+				}).Error("Контейнер не найден")
+				i18n.Fprintf(os.Stderr, "Ошибка: контейнер с ID=%s не найден\n", *containerID)
+				return
+			}
+
+			// Формирование URL для запроса к агенту
+			url := fmt.Sprintf("http://%s:%d/scan", host.Address, host.Port)
+
+			// Подготовка запроса на сканирование
+			scanReq := models.ScanRequest{
+				ContainerID: *containerID,
+				Scanners:    scanners,
+			}
+
+			jsonData, err := json.Marshal(scanReq)
+			if err != nil {
+				logger.WithError(err).Error("Ошибка сериализации запроса")
+				i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+				return
+			}
+
+			// Выполнение POST запроса к агенту
+			resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
+			if err != nil {
+				logger.WithError(err).WithFields(logrus.Fields{
+					"host_id":      *hostID,
+					"container_id": *containerID,
+					"url":          url,
+				}).Error("Ошибка запроса к агенту")
+				i18n.Fprintf(os.Stderr, "Ошибка подключения к агенту: %v\n", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			// Проверка статуса ответа
+			if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+				logger.WithFields(logrus.Fields{
+					"host_id":      *hostID,
+					"container_id": *containerID,
+					"url":          url,
+					"status_code":  resp.StatusCode,
+				}).Error("Агент вернул ошибку")
+				i18n.Fprintf(os.Stderr, "Ошибка: агент вернул статус %d\n", resp.StatusCode)
+				return
+			}
+
+			// Декодирование ответа
+			var scanResp models.ScanResponse
+			if err := json.NewDecoder(resp.Body).Decode(&scanResp); err != nil {
+				logger.WithError(err).WithFields(logrus.Fields{
+					"host_id":      *hostID,
+					"container_id": *containerID,
+				}).Error("Ошибка декодирования ответа агента")
+				i18n.Fprintf(os.Stderr, "Ошибка декодирования ответа: %v\n", err)
+				return
+			}
+
+			// Сохранение информации о сканировании в БД
+			scan := &models.Scan{
+				ID:          scanResp.ScanID,
+				HostID:      *hostID,
+				ContainerID: *containerID,
+				Status:      "pending",
+				StartedAt:   time.Now(),
+			}
+
+			if err := store.AddScan(scan); err != nil {
+				logger.WithError(err).WithFields(logrus.Fields{
+					"host_id":      *hostID,
+					"container_id": *containerID,
+					"scan_id":      scanResp.ScanID,
+				}).Error("Ошибка сохранения информации о сканировании")
+				i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+				return
+			}
+
+			i18n.Printf("Сканирование запущено: ID=%s\n", scanResp.ScanID)
+			i18n.Println("Используйте команду 'aegis scan status SCAN_ID' для проверки статуса")
+
+		} else if *allContainers {
+			// Запрос списка контейнеров от агента
+			containersURL := fmt.Sprintf("http://%s:%d/containers", host.Address, host.Port)
+
+			resp, err := http.Get(containersURL)
+			if err != nil {
+				logger.WithError(err).WithFields(logrus.Fields{
+					"host_id": *hostID,
+					"url":     containersURL,
+				}).Error("Ошибка запроса к агенту")
+				i18n.Fprintf(os.Stderr, "Ошибка подключения к агенту: %v\n", err)
+				return
+			}
+
+			// Декодирование ответа
+			var containerResp models.ContainerListResponse
+			if err := json.NewDecoder(resp.Body).Decode(&containerResp); err != nil {
+				logger.WithError(err).WithField("host_id", *hostID).Error("Ошибка декодирования ответа агента")
+				i18n.Fprintf(os.Stderr, "Ошибка декодирования ответа: %v\n", err)
+				resp.Body.Close()
+				return
+			}
+			resp.Body.Close()
+
+			// Проверка наличия контейнеров
+			if len(containerResp.Containers) == 0 {
+				i18n.Println("Контейнеры не найдены")
+				return
+			}
+
+			// Запуск сканирования для каждого контейнера
+			scanURL := fmt.Sprintf("http://%s:%d/scan", host.Address, host.Port)
+			var successCount, failCount int
+
+			for _, container := range containerResp.Containers {
+				// Подготовка запроса на сканирование
+				scanReq := models.ScanRequest{
+					ContainerID: container.ID,
+					Scanners:    scanners,
+				}
+
+				jsonData, err := json.Marshal(scanReq)
+				if err != nil {
+					logger.WithError(err).Error("Ошибка сериализации запроса")
+					failCount++
+					continue
+				}
+
+				// Выполнение POST запроса к агенту
+				scanResp, err := http.Post(scanURL, "application/json", bytes.NewBuffer(jsonData))
+				if err != nil {
+					logger.WithError(err).WithFields(logrus.Fields{
+						"host_id":      *hostID,
+						"container_id": container.ID,
+						"url":          scanURL,
+					}).Error("Ошибка запроса к агенту")
+					failCount++
+					continue
+				}
+
+				// Проверка статуса ответа
+				if scanResp.StatusCode != http.StatusOK && scanResp.StatusCode != http.StatusAccepted {
+					logger.WithFields(logrus.Fields{
+						"host_id":      *hostID,
+						"container_id": container.ID,
+						"url":          scanURL,
+						"status_code":  scanResp.StatusCode,
+					}).Error("Агент вернул ошибку")
+					scanResp.Body.Close()
+					failCount++
+					continue
+				}
+
+				// Декодирование ответа
+				var scanRespObj models.ScanResponse
+				if err := json.NewDecoder(scanResp.Body).Decode(&scanRespObj); err != nil {
+					logger.WithError(err).WithFields(logrus.Fields{
+						"host_id":      *hostID,
+						"container_id": container.ID,
+					}).Error("Ошибка декодирования ответа агента")
+					scanResp.Body.Close()
+					failCount++
+					continue
+				}
+				scanResp.Body.Close()
+
+				// Сохранение информации о сканировании в БД
+				scan := &models.Scan{
+					ID:          scanRespObj.ScanID,
+					HostID:      *hostID,
+					ContainerID: container.ID,
+					Status:      "pending",
+					StartedAt:   time.Now(),
+				}
+
+				if err := store.AddScan(scan); err != nil {
+					logger.WithError(err).WithFields(logrus.Fields{
+						"host_id":      *hostID,
+						"container_id": container.ID,
+						"scan_id":      scanRespObj.ScanID,
+					}).Error("Ошибка сохранения информации о сканировании")
+					failCount++
+					continue
+				}
+
+				successCount++
+				i18n.Printf("Сканирование запущено для контейнера %s: ID=%s\n", container.Name, scanRespObj.ScanID)
+			}
+
+			i18n.Printf("\nСканирование запущено для %d контейнеров, не удалось запустить для %d контейнеров\n",
+				successCount, failCount)
+			i18n.Println("Используйте команду 'aegis vulnerabilities list' для просмотра результатов")
+		}
+	}
+	return cmd
+}
+
+// newScanStatusCommand создает команду проверки статуса сканирования
+func newScanStatusCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "status SCAN_ID",
+		Short:             i18n.T("Статус и результаты сканирования"),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: firstArg(a.completeScanIDs),
+	}
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger, notificationManager, out := a.store, a.logger, a.notificationManager, a.out
+
+		// Проверка указания ID сканирования
+		if len(args) < 1 {
+			i18n.Println("Ошибка: необходимо указать ID сканирования")
+			i18n.Println("Использование: aegis scan status SCAN_ID [--output ФОРМАТ]")
+			return
+		}
+
+		scanID := args[0]
+
+		// Получение информации о сканировании из БД
+		scan, err := store.GetScan(scanID)
+		if err != nil {
+			logger.WithError(err).WithField("scan_id", scanID).Error("Сканирование не найдено")
+			i18n.Fprintf(os.Stderr, "Ошибка: сканирование с ID=%s не найдено\n", scanID)
+			return
+		}
+
+		// Получение информации о хосте
+		host, err := store.GetHost(scan.HostID)
+		if err != nil {
+			logger.WithError(err).WithField("host_id", scan.HostID).Error("Хост не найден")
+			i18n.Fprintf(os.Stderr, "Ошибка: хост не найден\n")
+			return
+		}
+
+		// Если сканирование уже завершено, просто выводим информацию из БД
+		if scan.Status == "completed" || scan.Status == "failed" {
+			container, _ := store.GetContainer(scan.ContainerID)
+			containerName := scan.ContainerID
+			if container != nil {
+				containerName = container.Name
+			}
+
+			if !out.Human() {
+				var vulnerabilities []models.Vulnerability
+				var findings []models.Finding
+				if scan.Status == "completed" {
+					vulnerabilities, _ = store.ListVulnerabilities("", "", scanID, "")
+					findings, _ = store.ListFindings("", "", "", scanID, "")
+				}
+				renderScanStatus(out, scan, host, containerName, vulnerabilities, findings)
+				return
+			}
+
+			i18n.Printf("Сканирование: %s\n", scanID)
+			i18n.Printf("Хост: %s (%s)\n", host.Name, host.Address)
+			i18n.Printf("Контейнер: %s\n", containerName)
+			i18n.Printf("Статус: %s\n", scan.Status)
+			i18n.Printf("Начало: %s\n", scan.StartedAt.Format("2006-01-02 15:04:05"))
+
+			if !scan.FinishedAt.IsZero() {
+				i18n.Printf("Завершение: %s\n", scan.FinishedAt.Format("2006-01-02 15:04:05"))
+				duration := scan.FinishedAt.Sub(scan.StartedAt)
+				i18n.Printf("Длительность: %s\n", duration.String())
+			}
+
+			if scan.Status == "failed" && scan.ErrorMsg != "" {
+				i18n.Printf("Ошибка: %s\n", scan.ErrorMsg)
+			}
+
+			if scan.Status == "completed" {
+				// Получение количества найденных уязвимостей
+				vulnerabilities, err := store.ListVulnerabilities("", "", scanID, "")
+				if err == nil {
+					// Группировка уязвимостей по серьезности
+					var criticalCount, highCount, mediumCount, lowCount int
+					for _, vuln := range vulnerabilities {
+						switch strings.ToUpper(vuln.Severity) {
+						case "CRITICAL":
+							criticalCount++
+						case "HIGH":
+							highCount++
+						case "MEDIUM":
+							mediumCount++
+						case "LOW":
+							lowCount++
+						}
+					}
+
+					i18n.Println("\nРезультаты сканирования:")
+					i18n.Printf("- Критических: %d\n", criticalCount)
+					i18n.Printf("- Высоких: %d\n", highCount)
+					i18n.Printf("- Средних: %d\n", mediumCount)
+					i18n.Printf("- Низких: %d\n", lowCount)
+
+					i18n.Println("\nДля просмотра подробной информации используйте:")
+					fmt.Printf("aegis vulnerabilities list --scan %s\n", scanID)
+				}
+
+				if findings, err := store.ListFindings("", "", "", scanID, ""); err == nil {
+					printFindingsSummary(scanID, findings)
+				}
+			}
+
+			return
+		}
+
+		// Формирование URL для запроса статуса к агенту
+		url := fmt.Sprintf("http://%s:%d/scan/%s", host.Address, host.Port, scanID)
+
+		// Выполнение HTTP запроса
+		resp, err := http.Get(url)
+		if err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"host_id": scan.HostID,
+				"scan_id": scanID,
+				"url":     url,
+			}).Error("Ошибка запроса к агенту")
+			i18n.Fprintf(os.Stderr, "Ошибка подключения к агенту: %v\n", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		// Проверка статуса ответа
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+			logger.WithFields(logrus.Fields{
+				"host_id":     scan.HostID,
+				"scan_id":     scanID,
+				"url":         url,
+				"status_code": resp.StatusCode,
+			}).Error("Агент вернул ошибку")
+			i18n.Fprintf(os.Stderr, "Ошибка: агент вернул статус %d\n", resp.StatusCode)
+			return
+		}
+
+		// Декодирование ответа
+		var scanStatusResp models.ScanStatusResponse
+		if err := json.NewDecoder(resp.Body).Decode(&scanStatusResp); err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"host_id": scan.HostID,
+				"scan_id": scanID,
+			}).Error("Ошибка декодирования ответа агента")
+			i18n.Fprintf(os.Stderr, "Ошибка декодирования ответа: %v\n", err)
+			return
+		}
+
+		// Обновление статуса сканирования в БД
+		scan.Status = scanStatusResp.Status
+		if scanStatusResp.FinishedAt != nil {
+			scan.FinishedAt = *scanStatusResp.FinishedAt
+		}
+		if scanStatusResp.ErrorMsg != "" {
+			scan.ErrorMsg = scanStatusResp.ErrorMsg
+		}
+		if scanStatusResp.ImageDigest != "" {
+			scan.ImageDigest = scanStatusResp.ImageDigest
+		}
+
+		if err := store.UpdateScan(scan); err != nil {
+			logger.WithError(err).WithField("scan_id", scanID).Error("Ошибка обновления информации о сканировании")
+		}
+
+		// Если сканирование завершено и есть результаты, сохраняем их в БД
+		if scan.Status == "completed" && len(scanStatusResp.Vulnerabilities) > 0 {
+			for _, vuln := range scanStatusResp.Vulnerabilities {
+				// Дополняем данные об уязвимости
+				vuln.ID = uuid.New().String()
+				vuln.ScanID = scanID
+				vuln.ContainerID = scan.ContainerID
+				vuln.HostID = scan.HostID
+				vuln.DiscoveredAt = time.Now()
+
+				// Сохраняем уязвимость в БД
+				if err := store.AddVulnerability(&vuln); err != nil {
+					logger.WithError(err).WithFields(logrus.Fields{
+						"scan_id":          scanID,
+						"host_id":          scan.HostID,
+						"container_id":     scan.ContainerID,
+						"vulnerability_id": vuln.VulnerabilityID,
+					}).Error("Ошибка сохранения информации об уязвимости")
+				}
+			}
+		}
+
+		// Сохранение найденных секретов и ошибок конфигурации
+		var findings []models.Finding
+		if scan.Status == "completed" {
+			findings = append(append(findings, scanStatusResp.Secrets...), scanStatusResp.Misconfigs...)
+			for i := range findings {
+				findings[i].ID = uuid.New().String()
+				findings[i].ScanID = scanID
+				findings[i].ContainerID = scan.ContainerID
+				findings[i].HostID = scan.HostID
+				findings[i].DiscoveredAt = time.Now()
+
+				if err := store.AddFinding(&findings[i]); err != nil {
+					logger.WithError(err).WithFields(logrus.Fields{
+						"scan_id": scanID,
+						"kind":    findings[i].Kind,
+						"rule_id": findings[i].RuleID,
+					}).Error("Ошибка сохранения находки")
+				}
+			}
+		}
+
+		// Сохранение истории хуков, выполненных агентом для этого сканирования
+		if scan.Status == "completed" || scan.Status == "failed" {
+			if _, err := syncHookExecutions(host, "", scanID, store); err != nil {
+				logger.WithError(err).WithField("scan_id", scanID).Warn("Ошибка загрузки истории хуков")
+			}
+		}
+
+		// Получение информации о контейнере
+		container, _ := store.GetContainer(scan.ContainerID)
+		containerName := scan.ContainerID
+		if container != nil {
+			containerName = container.Name
+		}
+
+		// Сохранение SBOM образа
+		if scan.Status == "completed" && len(scanStatusResp.SBOMFormats) > 0 {
+			image := ""
+			if container != nil {
+				image = container.Image
+			}
+			importScanSBOMs(host, scan, image, scanStatusResp.SBOMFormats, store, logger)
+		}
+
+		// Отправка уведомления о завершении сканирования
+		if scan.Status == "completed" && notificationManager != nil {
+			result := utils.ScanResult{
+				Host:            *host,
+				Scan:            *scan,
+				Vulnerabilities: scanStatusResp.Vulnerabilities,
+				Findings:        findings,
+			}
+			if container != nil {
+				result.Container = *container
+			}
+			result.PreviousScan, result.PreviousVulnerabilities = previousCompletedScan(scan, store, logger)
+			notificationManager.SendScanCompletedNotification(result)
+		}
+
+		if !out.Human() {
+			renderScanStatus(out, scan, host, containerName, scanStatusResp.Vulnerabilities, findings)
+			return
+		}
+
+		// Вывод информации о сканировании
+		i18n.Printf("Сканирование: %s\n", scanID)
+		i18n.Printf("Хост: %s (%s)\n", host.Name, host.Address)
+		i18n.Printf("Контейнер: %s\n", containerName)
+		i18n.Printf("Статус: %s\n", scan.Status)
+		i18n.Printf("Начало: %s\n", scan.StartedAt.Format("2006-01-02 15:04:05"))
+
+		if scan.Status == "completed" || scan.Status == "failed" {
+			if !scan.FinishedAt.IsZero() {
+				i18n.Printf("Завершение: %s\n", scan.FinishedAt.Format("2006-01-02 15:04:05"))
+				duration := scan.FinishedAt.Sub(scan.StartedAt)
+				i18n.Printf("Длительность: %s\n", duration.String())
+			}
+
+			if scan.Status == "failed" && scan.ErrorMsg != "" {
+				i18n.Printf("Ошибка: %s\n", scan.ErrorMsg)
+			}
+
+			if scan.Status == "completed" {
+				// Вывод количества найденных уязвимостей
+				i18n.Printf("\nНайдено уязвимостей: %d\n", len(scanStatusResp.Vulnerabilities))
+
+				// Группировка уязвимостей по серьезности
+				var criticalCount, highCount, mediumCount, lowCount int
+				for _, vuln := range scanStatusResp.Vulnerabilities {
+					switch strings.ToUpper(vuln.Severity) {
+					case "CRITICAL":
+						criticalCount++
+					case "HIGH":
+						highCount++
+					case "MEDIUM":
+						mediumCount++
+					case "LOW":
+						lowCount++
+					}
+				}
+
+				i18n.Println("\nРезультаты сканирования:")
+				i18n.Printf("- Критических: %d\n", criticalCount)
+				i18n.Printf("- Высоких: %d\n", highCount)
+				i18n.Printf("- Средних: %d\n", mediumCount)
+				i18n.Printf("- Низких: %d\n", lowCount)
+
+				i18n.Println("\nДля просмотра подробной информации используйте:")
+				fmt.Printf("aegis vulnerabilities list --scan %s\n", scanID)
+
+				printFindingsSummary(scanID, findings)
+			}
+		} else {
+			i18n.Println("\nСканирование выполняется...")
+			i18n.Println("Для обновления статуса повторите команду позже.")
+		}
+	}
+	return cmd
+}
+
+// parseScanners разбирает значение флага --scanners
+func parseScanners(value string) ([]string, error) {
+	var scanners []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		switch name {
+		case models.ScannerVuln, models.ScannerSecret, models.ScannerMisconfig:
+			scanners = append(scanners, name)
+		default:
+			return nil, i18n.Errorf("неизвестный тип сканера: %s (допустимо: vuln, secret, misconfig)", name)
+		}
+	}
+	if len(scanners) == 0 {
+		return nil, i18n.Errorf("не указан ни один тип сканера")
+	}
+	return scanners, nil
+}
+
+// printFindingsSummary выводит количество найденных секретов и ошибок конфигурации
+func printFindingsSummary(scanID string, findings []models.Finding) {
+	var secrets, misconfigs int
+	for _, f := range findings {
+		switch f.Kind {
+		case models.FindingKindSecret:
+			secrets++
+		case models.FindingKindMisconfig:
+			misconfigs++
+		}
+	}
+
+	if secrets > 0 {
+		i18n.Printf("\nНайдено секретов: %d\n", secrets)
+		fmt.Printf("aegis secrets list --scan %s\n", scanID)
+	}
+	if misconfigs > 0 {
+		i18n.Printf("\nНайдено ошибок конфигурации: %d\n", misconfigs)
+		fmt.Printf("aegis misconfig list --scan %s\n", scanID)
+	}
+}
+
+// scanStatusReport - результат команды scan status в машиночитаемых форматах
+type scanStatusReport struct {
+	models.Scan
+	HostName      string              `json:"host_name"`
+	HostAddress   string              `json:"host_address"`
+	ContainerName string              `json:"container_name"`
+	Summary       *models.ScanSummary `json:"summary,omitempty"` // Только для завершенного сканирования
+}
+
+// renderScanStatus выводит статус сканирования в формате, выбранном флагами вывода
+func renderScanStatus(out output.Options, scan *models.Scan, host *models.Host, containerName string, vulnerabilities []models.Vulnerability, findings []models.Finding) {
+	report := scanStatusReport{
+		Scan:          *scan,
+		HostName:      host.Name,
+		HostAddress:   host.Address,
+		ContainerName: containerName,
+	}
+	if scan.Status == "completed" {
+		report.Summary = &models.ScanSummary{}
+		for _, vuln := range vulnerabilities {
+			report.Summary.Vulnerabilities.Add(vuln.Severity)
+		}
+		for _, f := range findings {
+			switch f.Kind {
+			case models.FindingKindSecret:
+				report.Summary.Secrets.Add(f.Severity)
+			case models.FindingKindMisconfig:
+				report.Summary.Misconfigs.Add(f.Severity)
+			}
+		}
+	}
+
+	finished := ""
+	if !scan.FinishedAt.IsZero() {
+		finished = scan.FinishedAt.Format("2006-01-02 15:04:05")
+	}
+	table := &output.Table{
+		Columns: []output.Column{
+			{Key: "id", Header: "ID"},
+			{Key: "host", Header: i18n.T("Хост")},
+			{Key: "container", Header: i18n.T("Контейнер")},
+			{Key: "status", Header: i18n.T("Статус")},
+			{Key: "started_at", Header: i18n.T("Начало")},
+			{Key: "finished_at", Header: i18n.T("Завершение")},
+			{Key: "vulnerabilities", Header: i18n.T("Уязвимости")},
+			{Key: "error", Header: i18n.T("Ошибка")},
+		},
+	}
+	total := ""
+	if report.Summary != nil {
+		total = strconv.Itoa(report.Summary.Vulnerabilities.Total)
+	}
+	table.Row(scan.ID, scan.ID, host.Name, containerName, scan.Status,
+		scan.StartedAt.Format("2006-01-02 15:04:05"), finished, total, scan.ErrorMsg)
+	renderOutput(out, report, table)
+}
+
+// previousCompletedScan возвращает предыдущее завершенное сканирование того же контейнера
+// и его уязвимости, чтобы правила уведомлений могли учитывать изменения
+func previousCompletedScan(scan *models.Scan, store *db.Store, logger *logrus.Logger) (*models.Scan, []models.Vulnerability) {
+	scans, err := store.ListScans(scan.HostID, scan.ContainerID)
+	if err != nil {
+		logger.WithError(err).WithField("scan_id", scan.ID).Warn("Ошибка поиска предыдущего сканирования")
+		return nil, nil
+	}
+
+	// Сканирования отсортированы от новых к старым
+	for i := range scans {
+		previous := &scans[i]
+		if previous.ID == scan.ID || previous.Status != "completed" || !previous.StartedAt.Before(scan.StartedAt) {
+			continue
+		}
+
+		vulns, err := store.ListVulnerabilities("", "", previous.ID, "")
+		if err != nil {
+			logger.WithError(err).WithField("scan_id", previous.ID).Warn("Ошибка загрузки уязвимостей предыдущего сканирования")
+			return nil, nil
+		}
+		return previous, vulns
+	}
+
+	return nil, nil
+}
+
+// importScanSBOMs загружает с агента SBOM, построенные при сканировании, и сохраняет их в БД
+func importScanSBOMs(host *models.Host, scan *models.Scan, image string, formats []string, store *db.Store, logger *logrus.Logger) {
+	client := agentclient.New(host)
+
+	for _, format := range formats {
+		data, err := client.GetSBOM(scan.ID, format)
+		if err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"scan_id": scan.ID,
+				"format":  format,
+			}).Error("Ошибка загрузки SBOM")
+			continue
+		}
+
+		record, packages, err := sbom.NewRecord(scan, image, format, data)
+		if err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"scan_id": scan.ID,
+				"format":  format,
+			}).Error("Ошибка разбора SBOM")
+			continue
+		}
+
+		if err := store.SaveSBOM(record, packages); err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"scan_id":      scan.ID,
+				"format":       format,
+				"image_digest": scan.ImageDigest,
+			}).Error("Ошибка сохранения SBOM")
+		}
+	}
+}
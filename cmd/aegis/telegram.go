@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/telegram"
+	"github.com/spf13/cobra"
+)
+
+// newTelegramCommand создает группу команд Telegram-бота
+func newTelegramCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telegram",
+		Short: i18n.T("Интерактивный Telegram-бот"),
+	}
+	cmd.AddCommand(newTelegramServeCommand(a))
+	return cmd
+}
+
+// newTelegramServeCommand создает команду запуска Telegram-бота
+func newTelegramServeCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: i18n.T("Запуск бота с командами сканирования"),
+		Long:  i18n.T("Бот отвечает на команды /hosts, /scan, /status, /top и /report из разрешенных чатов"),
+		Args:  cobra.NoArgs,
+	}
+	pollTimeout := cmd.Flags().Duration("poll-timeout", telegram.DefaultPollTimeout, i18n.T("Время ожидания обновлений в одном запросе getUpdates"))
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger, cfg := a.store, a.logger, a.cfg
+
+		bot, err := telegram.NewBot(cfg, store, logger)
+		if err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			i18n.Println("Укажите telegram_bot_token и telegram_allowed_chats в конфигурации")
+			return
+		}
+		bot.SetPollTimeout(*pollTimeout)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		i18n.Printf("Telegram-бот запущен (%s), разрешенные чаты: %v\n", cfg.TelegramAPIURL, bot.AllowedChats())
+		i18n.Println("Для остановки нажмите Ctrl+C")
+		logger.WithField("api_url", cfg.TelegramAPIURL).Info("Запуск Telegram-бота")
+
+		if err := bot.Run(ctx); err != nil {
+			logger.WithError(err).Error("Ошибка работы Telegram-бота")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+		i18n.Println("Telegram-бот остановлен")
+	}
+	return cmd
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aegis/aegis-cli/pkg/db"
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// newVulnerabilitiesCommand создает группу команд работы с уязвимостями
+func newVulnerabilitiesCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vulnerabilities",
+		Short: i18n.T("Работа с уязвимостями"),
+	}
+	cmd.AddCommand(newVulnerabilitiesListCommand(a))
+	return cmd
+}
+
+// newVulnerabilitiesListCommand создает команду вывода списка уязвимостей
+func newVulnerabilitiesListCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("Список найденных уязвимостей"),
+		Args:  cobra.NoArgs,
+	}
+	hostID := cmd.Flags().String("host", "", i18n.T("ID хоста для фильтрации"))
+	containerID := cmd.Flags().String("container", "", i18n.T("ID контейнера для фильтрации"))
+	scanID := cmd.Flags().String("scan", "", i18n.T("ID сканирования для фильтрации"))
+	severity := cmd.Flags().String("severity", "", i18n.T("Серьезность уязвимостей (CRITICAL, HIGH, MEDIUM, LOW)"))
+	groupBy := cmd.Flags().String("group-by", "", i18n.T("Группировка по Kubernetes: namespace или workload"))
+	a.registerCompletions(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		store, logger, out := a.store, a.logger, a.out
+
+		if err := validateGroupBy(*groupBy); err != nil {
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		// Получение списка уязвимостей
+		vulnerabilities, err := store.ListVulnerabilities(*hostID, *containerID, *scanID, *severity)
+		if err != nil {
+			logger.WithError(err).Error("Ошибка получения списка уязвимостей")
+			i18n.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			return
+		}
+
+		if *groupBy != "" {
+			entries := make([]severityEntry, 0, len(vulnerabilities))
+			for _, vuln := range vulnerabilities {
+				entries = append(entries, severityEntry{containerID: vuln.ContainerID, severity: vuln.Severity})
+			}
+			if out.Human() && len(vulnerabilities) > 0 {
+				printSeveritySummary(vulnerabilities)
+			}
+			printWorkloadGroups(out, *groupBy, entries, store)
+			return
+		}
+
+		table := &output.Table{
+			Columns: []output.Column{
+				{Key: "id", Header: "ID", Width: 15, Prefix: 12},
+				{Key: "vulnerability_id", Header: "CVE", Width: 15, Prefix: 13},
+				{Key: "package", Header: i18n.T("Пакет"), Width: 40, Max: 38},
+				{Key: "severity", Header: i18n.T("Серьезность"), Width: 10},
+				{Key: "discovered_at", Header: i18n.T("Обнаружено"), Width: 20},
+				{Key: "title", Header: i18n.T("Заголовок"), Wide: true},
+			},
+			Empty: i18n.T("Уязвимости не найдены"),
+		}
+		for _, vuln := range vulnerabilities {
+			pkg := fmt.Sprintf("%s (%s -> %s)", vuln.Package, vuln.InstalledVersion, vuln.FixedVersion)
+			table.Row(vuln.ID, vuln.ID, vuln.VulnerabilityID, pkg, vuln.Severity,
+				vuln.DiscoveredAt.Format("2006-01-02 15:04:05"), vuln.Title)
+		}
+
+		// Подробности и стратегии устранения выводятся только в формате для человека
+		if !out.Human() || len(vulnerabilities) == 0 {
+			renderOutput(out, vulnerabilities, table)
+			return
+		}
+
+		printSeveritySummary(vulnerabilities)
+		renderOutput(out, vulnerabilities, table)
+		fmt.Println()
+
+		// Если был указан конкретный ID сканирования, выводим подробности
+		if *scanID != "" {
+			i18n.Println("Подробная информация о найденных уязвимостях:")
+			fmt.Println()
+
+			var container *models.Container
+			scan, err := store.GetScan(*scanID)
+			if err == nil {
+				host, _ := store.GetHost(scan.HostID)
+				container, _ = store.GetContainer(scan.ContainerID)
+
+				if host != nil && container != nil {
+					i18n.Printf("Хост: %s (%s)\n", host.Name, host.Address)
+					i18n.Printf("Контейнер: %s\n", container.Name)
+					if workload := container.Workload(); workload != "" {
+						fmt.Printf("Kubernetes: pod %s, %s\n", container.PodName, workload)
+					}
+					i18n.Printf("Образ: %s\n", container.Image)
+					i18n.Printf("Дата сканирования: %s\n\n", scan.StartedAt.Format("2006-01-02 15:04:05"))
+				}
+			}
+
+			// Группировка по серьезности и сортировка
+			critical := make([]models.Vulnerability, 0)
+			high := make([]models.Vulnerability, 0)
+			medium := make([]models.Vulnerability, 0)
+			low := make([]models.Vulnerability, 0)
+
+			for _, vuln := range vulnerabilities {
+				switch strings.ToUpper(vuln.Severity) {
+				case "CRITICAL":
+					critical = append(critical, vuln)
+				case "HIGH":
+					high = append(high, vuln)
+				case "MEDIUM":
+					medium = append(medium, vuln)
+				case "LOW":
+					low = append(low, vuln)
+				}
+			}
+
+			// Вывод критических уязвимостей
+			if len(critical) > 0 {
+				i18n.Println("КРИТИЧЕСКИЕ УЯЗВИМОСТИ:")
+				fmt.Println(strings.Repeat("-", 80))
+				for _, vuln := range critical {
+					printVulnerabilityDetails(vuln)
+				}
+			}
+
+			// Вывод высоких уязвимостей
+			if len(high) > 0 {
+				i18n.Println("ВЫСОКИЕ УЯЗВИМОСТИ:")
+				fmt.Println(strings.Repeat("-", 80))
+				for _, vuln := range high {
+					printVulnerabilityDetails(vuln)
+				}
+			}
+
+			// Вывод средних уязвимостей
+			if len(medium) > 0 {
+				i18n.Println("СРЕДНИЕ УЯЗВИМОСТИ:")
+				fmt.Println(strings.Repeat("-", 80))
+				for _, vuln := range medium {
+					printVulnerabilityDetails(vuln)
+				}
+			}
+
+			// Вывод низких уязвимостей
+			if len(low) > 0 {
+				i18n.Println("НИЗКИЕ УЯЗВИМОСТИ:")
+				fmt.Println(strings.Repeat("-", 80))
+				for _, vuln := range low {
+					printVulnerabilityDetails(vuln)
+				}
+			}
+
+			// Вывод информации о возможных стратегиях восстановления
+			strategies, err := store.ListRemediationStrategies()
+			if err == nil && len(strategies) > 0 {
+				i18n.Println("\nВОЗМОЖНЫЕ СТРАТЕГИИ УСТРАНЕНИЯ УЯЗВИМОСТЕЙ:")
+				fmt.Println(strings.Repeat("-", 80))
+				for _, strategy := range strategies {
+					i18n.Printf("Название: %s\n", strategy.Name)
+					i18n.Printf("Тип: %s\n", strategy.Type)
+					i18n.Printf("Описание: %s\n", strategy.Description)
+					i18n.Printf("Ожидаемое время простоя: %s\n", strategy.EstimatedDowntime)
+					i18n.Printf("Команда: %s\n", strategy.RenderCommand(container))
+					fmt.Println(strings.Repeat("-", 80))
+				}
+			}
+		}
+	}
+	return cmd
+}
+
+// printSeveritySummary выводит количество уязвимостей по серьезности
+func printSeveritySummary(vulnerabilities []models.Vulnerability) {
+	var counts models.SeverityCounts
+	for _, vuln := range vulnerabilities {
+		counts.Add(vuln.Severity)
+	}
+
+	i18n.Printf("Найдено уязвимостей: %d\n", len(vulnerabilities))
+	i18n.Printf("- Критических: %d\n", counts.Critical)
+	i18n.Printf("- Высоких: %d\n", counts.High)
+	i18n.Printf("- Средних: %d\n", counts.Medium)
+	i18n.Printf("- Низких: %d\n", counts.Low)
+	fmt.Println()
+}
+
+// printVulnerabilityDetails выводит подробную информацию об уязвимости
+func printVulnerabilityDetails(vuln models.Vulnerability) {
+	fmt.Printf("CVE: %s\n", vuln.VulnerabilityID)
+	i18n.Printf("Пакет: %s\n", vuln.Package)
+	i18n.Printf("Установленная версия: %s\n", vuln.InstalledVersion)
+	if vuln.FixedVersion != "" {
+		i18n.Printf("Исправлено в версии: %s\n", vuln.FixedVersion)
+	}
+	i18n.Printf("Серьезность: %s\n", vuln.Severity)
+	i18n.Printf("Название: %s\n", vuln.Title)
+	if vuln.Description != "" {
+		i18n.Printf("Описание: %s\n", vuln.Description)
+	}
+
+	// Вывод ссылок
+	if vuln.References != "" {
+		i18n.Println("Ссылки:")
+		references := strings.Split(vuln.References, ",")
+		for _, ref := range references {
+			if ref != "" {
+				fmt.Printf("- %s\n", ref)
+			}
+		}
+	}
+	fmt.Println(strings.Repeat("-", 80))
+}
+
+// Значения флага --group-by
+const (
+	groupByNamespace = "namespace"
+	groupByWorkload  = "workload"
+)
+
+// outsideKubernetes обозначает группу контейнеров, запущенных вне Kubernetes
+const outsideKubernetes = "(вне Kubernetes)"
+
+// severityEntry описывает находку, учитываемую при группировке по Kubernetes
+type severityEntry struct {
+	containerID string
+	severity    string
+}
+
+// validateGroupBy проверяет значение флага --group-by
+func validateGroupBy(groupBy string) error {
+	switch groupBy {
+	case "", groupByNamespace, groupByWorkload:
+		return nil
+	default:
+		return i18n.Errorf("недопустимое значение --group-by: %s (допустимо: namespace, workload)", groupBy)
+	}
+}
+
+// workloadGroup - количество находок по серьезности для namespace или рабочей нагрузки
+type workloadGroup struct {
+	Group string `json:"group"`
+	models.SeverityCounts
+}
+
+// printWorkloadGroups выводит количество находок по серьезности для каждого namespace
+// или рабочей нагрузки Kubernetes, к которым относятся контейнеры
+func printWorkloadGroups(out output.Options, groupBy string, entries []severityEntry, store *db.Store) {
+	containers := make(map[string]*models.Container)
+	counts := make(map[string]*models.SeverityCounts)
+
+	for _, e := range entries {
+		c, ok := containers[e.containerID]
+		if !ok {
+			c, _ = store.GetContainer(e.containerID)
+			containers[e.containerID] = c
+		}
+
+		// Контейнеры вне Kubernetes попадают в группу с пустым именем
+		group := ""
+		if c != nil && c.PodName != "" {
+			if groupBy == groupByNamespace {
+				group = c.PodNamespace
+			} else {
+				group = c.Workload()
+			}
+		}
+
+		if counts[group] == nil {
+			counts[group] = &models.SeverityCounts{}
+		}
+		counts[group].Add(e.severity)
+	}
+
+	groups := make([]workloadGroup, 0, len(counts))
+	for group, c := range counts {
+		groups = append(groups, workloadGroup{Group: group, SeverityCounts: *c})
+	}
+	// Группы с наибольшим числом критических находок выводятся первыми
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Critical != groups[j].Critical {
+			return groups[i].Critical > groups[j].Critical
+		}
+		if groups[i].High != groups[j].High {
+			return groups[i].High > groups[j].High
+		}
+		return groups[i].Group < groups[j].Group
+	})
+
+	header := "Namespace"
+	if groupBy == groupByWorkload {
+		header = i18n.T("Рабочая нагрузка")
+	}
+	table := &output.Table{
+		Columns: []output.Column{
+			{Key: "group", Header: header, Width: 50, Max: 48},
+			{Key: "critical", Header: "CRITICAL", Width: 10},
+			{Key: "high", Header: "HIGH", Width: 10},
+			{Key: "medium", Header: "MEDIUM", Width: 10},
+			{Key: "low", Header: "LOW", Width: 10},
+			{Key: "total", Header: i18n.T("Всего"), Width: 10},
+		},
+		Empty: i18n.T("Ничего не найдено"),
+	}
+	for _, g := range groups {
+		name := g.Group
+		if name == "" {
+			name = i18n.T(outsideKubernetes)
+		}
+		table.Row(g.Group, name, strconv.Itoa(g.Critical), strconv.Itoa(g.High),
+			strconv.Itoa(g.Medium), strconv.Itoa(g.Low), strconv.Itoa(g.Total))
+	}
+	renderOutput(out, groups, table)
+}
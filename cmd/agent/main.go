@@ -2,8 +2,8 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,46 +12,113 @@ import (
 
 	"github.com/aegis/aegis-cli/pkg/api"
 	"github.com/aegis/aegis-cli/pkg/config"
+	"github.com/aegis/aegis-cli/pkg/db"
 	"github.com/aegis/aegis-cli/pkg/hooks"
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/logging"
 	"github.com/aegis/aegis-cli/pkg/scanner"
+	"github.com/aegis/aegis-cli/pkg/tracing"
 	"github.com/sirupsen/logrus"
 )
 
+// version - версия агента
+const version = "0.1.0"
+
 func main() {
-	// Отладочная информация
-	fmt.Println("Aegis Agent v0.1.0")
-	fmt.Println("Запуск...")
-	fmt.Println("Текущая директория:", getCurrentDir())
-
-	// Логирование
-	logger := logrus.New()
-	logger.SetFormatter(&logrus.JSONFormatter{})
-
-	// Создаем файл для логирования ошибок
-	logFile, err := os.OpenFile("aegis-agent-debug.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err == nil {
-		logger.SetOutput(logFile)
-	} else {
-		fmt.Println("Ошибка открытия файла лога:", err)
-	}
+	verbose := flag.Bool("verbose", false, "Журнал уровня debug; при записи в файл журнал дублируется в stderr")
+	flag.Parse()
 
-	logger.SetLevel(logrus.DebugLevel)
-	logger.Debug("Начало выполнения агента")
+	// Ошибки агента попадают в JSON API (error_msg, error), поэтому язык сообщений
+	// не зависит от LANG хоста: они всегда передаются исходными строками
+	i18n.SetLanguage(i18n.DefaultLanguage)
 
-	// Загрузка конфигурации
+	// Загрузка конфигурации. Журнал настраивается по ней, поэтому ошибки выводятся только в stderr
 	cfg, err := config.LoadAgentConfig()
 	if err != nil {
-		log.Fatalf("Ошибка загрузки конфигурации: %v", err)
+		fmt.Fprintf(os.Stderr, "Ошибка загрузки конфигурации: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Логирование. Под systemd без log_file журнал выводится в stderr в формате journald
+	logger, err := logging.New(logging.Options{
+		Level:      cfg.LogLevel,
+		Format:     cfg.LogFormat,
+		File:       cfg.LogFile,
+		MaxSizeMB:  cfg.LogMaxSizeMB,
+		MaxBackups: cfg.LogMaxBackups,
+		Verbose:    *verbose,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка настройки журнала: %v\n", err)
+		os.Exit(1)
 	}
+	defer logging.Close(logger)
+	logger.WithFields(logrus.Fields{
+		"version": version,
+		"dir":     getCurrentDir(),
+	}).Info("Запуск Aegis Agent")
+
+	// Трассировка OpenTelemetry. Контекст traceparent из запросов CLI принимается и без нее
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:       cfg.Tracing.Exporter,
+		Endpoint:       cfg.Tracing.Endpoint,
+		Insecure:       cfg.Tracing.Insecure,
+		File:           cfg.Tracing.File,
+		SampleRatio:    cfg.Tracing.SampleRatio,
+		ServiceName:    "aegis-agent",
+		ServiceVersion: version,
+		Logger:         logger,
+	})
+	if err != nil {
+		logger.WithError(err).Fatal("Ошибка настройки трассировки")
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.WithError(err).Warn("Ошибка отправки трассировки")
+		}
+	}()
+
+	// Подключение к среде выполнения контейнеров
+	runtime, err := scanner.NewRuntime(scanner.RuntimeOptions{
+		Name:                 cfg.Runtime,
+		DockerSocketPath:     cfg.DockerSocketPath,
+		PodmanSocketPath:     cfg.PodmanSocketPath,
+		ContainerdSocketPath: cfg.ContainerdSocketPath,
+		ContainerdNamespace:  cfg.ContainerdNamespace,
+	})
+	if err != nil {
+		logger.WithError(err).Fatal("Ошибка подключения к среде выполнения контейнеров")
+	}
+	logger.WithField("runtime", runtime.Name()).Info("Среда выполнения контейнеров выбрана")
 
 	// Инициализация сканера
-	scannerInstance := scanner.NewScanner(cfg.ScanConcurrency, cfg.DockerSocketPath)
+	scannerInstance := scanner.NewScanner(cfg.ScanConcurrency, runtime, logger)
+
+	// Инициализация хранилища истории выполнения хуков и хуков, добавленных через API
+	var executionStore hooks.ExecutionStore
+	store, err := db.NewAgentStore(cfg.DatabasePath, logger)
+	if err != nil {
+		logger.WithError(err).WithField("database_path", cfg.DatabasePath).
+			Warn("Хранилище агента недоступно, история выполнения хуков и хуки из API не сохраняются")
+	} else {
+		defer store.Close()
+		executionStore = store
+	}
 
 	// Инициализация менеджера хуков
-	hookManager := hooks.NewManager(cfg.Hooks)
+	hookManager := hooks.NewManager(cfg.Hooks, executionStore)
+	hookManager.SetLogger(logger)
+	hookManager.SetConcurrency(cfg.HookConcurrency)
+	if store != nil {
+		if err := hookManager.UseHookStore(store); err != nil {
+			logger.WithError(err).Error("Ошибка загрузки хуков, добавленных через API")
+		}
+	}
 
 	// Инициализация API
-	apiHandler := api.NewHandler(scannerInstance, hookManager)
+	apiHandler := api.NewHandler(scannerInstance, hookManager, time.Duration(cfg.ScanTimeoutSeconds)*time.Second, version, cfg.HooksToken, logger)
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.Port),
 		Handler: apiHandler,
@@ -63,22 +130,22 @@ func main() {
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 		<-sigChan
 
-		log.Println("Получен сигнал завершения, останавливаем сервер...")
+		logger.Info("Получен сигнал завершения, останавливаем сервер")
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
 		if err := server.Shutdown(ctx); err != nil {
-			log.Printf("Ошибка при остановке сервера: %v", err)
+			logger.WithError(err).Error("Ошибка при остановке сервера")
 		}
 	}()
 
 	// Запуск HTTP-сервера
-	log.Printf("Запуск Aegis Agent на порту %d...", cfg.Port)
+	logger.WithField("port", cfg.Port).Info("Запуск HTTP-сервера агента")
 	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Ошибка при запуске сервера: %v", err)
+		logger.WithError(err).Fatal("Ошибка при запуске сервера")
 	}
 
-	log.Println("Сервер успешно остановлен")
+	logger.Info("Сервер успешно остановлен")
 }
 
 // Вспомогательная функция для получения текущей директории
@@ -93,8 +160,6 @@ func getCurrentDir() string {
 // Вспомогательная функция для логирования выхода
 func logAndExit(logger *logrus.Logger, code int, message string) {
 	logger.Debug(message)
-	if logFile, ok := logger.Out.(*os.File); ok {
-		logFile.Close()
-	}
+	logging.Close(logger)
 	os.Exit(code)
 }
@@ -1,15 +1,16 @@
 #!/bin/bash
 # Хук для события ошибки сканирования
+#
+# Текст ошибки передается в AEGIS_ERROR, полное событие - в stdin в формате JSON.
 
-SCAN_ID=$1
 TIMESTAMP=$(date +"%Y-%m-%d %H:%M:%S")
 
-echo "$TIMESTAMP - Ошибка при сканировании $SCAN_ID" >> /var/log/aegis-hooks.log
+echo "$TIMESTAMP - Ошибка при сканировании $AEGIS_SCAN_ID ($AEGIS_CONTAINER_NAME на $AEGIS_HOST): $AEGIS_ERROR" >> /var/log/aegis-hooks.log
 
 # Пример отправки уведомления по email
 if [ -n "$EMAIL" ]; then
-    echo "Произошла ошибка при сканировании контейнера (Scan ID: $SCAN_ID)" | \
-    mail -s "Aegis: Ошибка сканирования" $EMAIL
+    echo "Произошла ошибка при сканировании контейнера $AEGIS_CONTAINER_NAME (Scan ID: $AEGIS_SCAN_ID): $AEGIS_ERROR" | \
+    mail -s "Aegis: Ошибка сканирования" "$EMAIL"
 fi
 
-exit 0 
+exit 0
@@ -1,17 +1,28 @@
 #!/bin/bash
 # Хук для события завершения сканирования
+#
+# Агент передает событие в формате JSON в stdin, а основные поля -
+# в переменных окружения AEGIS_*. ID сканирования по-прежнему доступен в $1.
 
-SCAN_ID=$1
+EVENT=$(cat)
 TIMESTAMP=$(date +"%Y-%m-%d %H:%M:%S")
 
-echo "$TIMESTAMP - Сканирование $SCAN_ID завершено" >> /var/log/aegis-hooks.log
+echo "$TIMESTAMP - Сканирование $AEGIS_SCAN_ID контейнера $AEGIS_CONTAINER_NAME ($AEGIS_IMAGE) завершено:" \
+    "critical=$AEGIS_VULNS_CRITICAL high=$AEGIS_VULNS_HIGH secrets=$AEGIS_SECRETS_TOTAL" >> /var/log/aegis-hooks.log
+
+# Список критических уязвимостей из полного события (требуется jq)
+if command -v jq >/dev/null 2>&1; then
+    echo "$EVENT" | jq -r '.vulnerabilities[]? | select(.severity == "CRITICAL") | "  \(.vulnerability_id) \(.package)"' \
+        >> /var/log/aegis-hooks.log
+fi
 
 # Пример отправки уведомления через webhook
 if [ -n "$WEBHOOK_URL" ]; then
+    TEXT="Сканирование $AEGIS_CONTAINER_NAME на $AEGIS_HOST: критических $AEGIS_VULNS_CRITICAL, высоких $AEGIS_VULNS_HIGH"
     curl -s -X POST \
         -H "Content-Type: application/json" \
-        -d "{\"text\":\"Сканирование контейнера завершено\", \"scan_id\":\"$SCAN_ID\"}" \
-        $WEBHOOK_URL
+        -d "{\"text\":\"$TEXT\", \"scan_id\":\"$AEGIS_SCAN_ID\"}" \
+        "$WEBHOOK_URL"
 fi
 
-exit 0 
+exit 0
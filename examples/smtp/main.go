@@ -0,0 +1,110 @@
+// Пример SMTP-сервера для проверки email-уведомлений Aegis: принимает письма
+// без шифрования и аутентификации и выводит их в консоль или сохраняет в каталог.
+//
+// Запуск: go run ./examples/smtp --listen 127.0.0.1:2525 --dir /tmp/aegis-mail
+// Канал в конфигурации CLI: {type: email, host: 127.0.0.1, port: 2525, tls: none, ...}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:2525", "Адрес для приема писем")
+	dir := flag.String("dir", "", "Каталог для сохранения писем в формате .eml (по умолчанию вывод в консоль)")
+	flag.Parse()
+
+	if *dir != "" {
+		if err := os.MkdirAll(*dir, 0755); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Прием писем на %s", *listen)
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			log.Fatal(err)
+		}
+		go serve(conn, *dir)
+	}
+}
+
+// serve обрабатывает один SMTP-сеанс
+func serve(conn net.Conn, dir string) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+
+	reply := func(code int, msg string) {
+		tp.PrintfLine("%d %s", code, msg)
+	}
+
+	reply(220, "aegis-smtp-sink ready")
+
+	var from string
+	var to []string
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(cmd) {
+		case "EHLO", "HELO":
+			reply(250, "aegis-smtp-sink")
+		case "MAIL":
+			from, to = strings.TrimPrefix(arg, "FROM:"), nil
+			reply(250, "OK")
+		case "RCPT":
+			to = append(to, strings.TrimPrefix(arg, "TO:"))
+			reply(250, "OK")
+		case "DATA":
+			reply(354, "End data with <CR><LF>.<CR><LF>")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			if err := store(dir, from, to, data); err != nil {
+				reply(451, err.Error())
+				continue
+			}
+			reply(250, "OK")
+		case "RSET", "NOOP":
+			reply(250, "OK")
+		case "QUIT":
+			reply(221, "Bye")
+			return
+		default:
+			reply(502, "Command not implemented")
+		}
+	}
+}
+
+// store выводит полученное письмо или сохраняет его в каталог
+func store(dir, from string, to []string, data []byte) error {
+	if dir == "" {
+		log.Printf("Письмо от %s для %s:\n%s", from, strings.Join(to, ", "), data)
+		return nil
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%d.eml", time.Now().UnixNano()))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	log.Printf("Письмо от %s для %s сохранено в %s", from, strings.Join(to, ", "), path)
+	return nil
+}
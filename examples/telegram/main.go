@@ -0,0 +1,190 @@
+// Пример локальной замены Telegram Bot API для проверки команд aegis telegram serve:
+// строки, введенные в консоли, передаются боту как сообщения из чата, а ответы бота
+// выводятся в консоль. Отправленные ботом файлы сохраняются в каталог.
+//
+// Запуск: go run ./examples/telegram --listen 127.0.0.1:8081 --chat 12345 --dir /tmp/aegis-bot
+// Конфигурация CLI: telegram_api_url: http://127.0.0.1:8081, telegram_allowed_chats: [12345]
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// update повторяет формат обновления Bot API
+type update struct {
+	UpdateID int64   `json:"update_id"`
+	Message  message `json:"message"`
+}
+
+type message struct {
+	MessageID int64  `json:"message_id"`
+	Chat      chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+type chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// queue хранит обновления, которые еще не подтверждены ботом
+type queue struct {
+	mu      sync.Mutex
+	updates []update
+	nextID  int64
+	notify  chan struct{}
+}
+
+func (q *queue) push(chatID int64, text string) {
+	q.mu.Lock()
+	q.nextID++
+	q.updates = append(q.updates, update{
+		UpdateID: q.nextID,
+		Message:  message{MessageID: q.nextID, Chat: chat{ID: chatID, Type: "private"}, Text: text},
+	})
+	q.mu.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// pending удаляет подтвержденные обновления (с ID меньше offset) и возвращает остальные
+func (q *queue) pending(offset int64) []update {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	kept := q.updates[:0]
+	for _, u := range q.updates {
+		if u.UpdateID >= offset {
+			kept = append(kept, u)
+		}
+	}
+	q.updates = kept
+	return append([]update{}, kept...)
+}
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:8081", "Адрес для приема запросов бота")
+	chatID := flag.Int64("chat", 12345, "ID чата, от имени которого отправляются сообщения")
+	dir := flag.String("dir", os.TempDir(), "Каталог для сохранения файлов, отправленных ботом")
+	flag.Parse()
+
+	q := &queue{notify: make(chan struct{}, 1)}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		// Путь запроса: /bot<token>/<method>
+		method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		switch method {
+		case "getUpdates":
+			handleGetUpdates(w, r, q)
+		case "sendMessage":
+			handleSendMessage(w, r)
+		case "sendDocument":
+			handleSendDocument(w, r, *dir)
+		default:
+			respond(w, false, nil, "method not found: "+method)
+		}
+	})
+
+	go func() {
+		log.Printf("Bot API на http://%s, сообщения отправляются от чата %d", *listen, *chatID)
+		log.Fatal(http.ListenAndServe(*listen, nil))
+	}()
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		if text := strings.TrimSpace(scanner.Text()); text != "" {
+			q.push(*chatID, text)
+		}
+	}
+}
+
+func handleGetUpdates(w http.ResponseWriter, r *http.Request, q *queue) {
+	r.ParseForm()
+	offset, _ := strconv.ParseInt(r.FormValue("offset"), 10, 64)
+	timeout, _ := strconv.Atoi(r.FormValue("timeout"))
+
+	deadline := time.After(time.Duration(timeout) * time.Second)
+	for {
+		if updates := q.pending(offset); len(updates) > 0 || timeout == 0 {
+			respond(w, true, updates, "")
+			return
+		}
+		select {
+		case <-q.notify:
+		case <-deadline:
+			respond(w, true, []update{}, "")
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func handleSendMessage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ChatID int64  `json:"chat_id"`
+		Text   string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond(w, false, nil, err.Error())
+		return
+	}
+	fmt.Printf("\n[бот -> %d]\n%s\n\n", req.ChatID, req.Text)
+	respond(w, true, map[string]int64{"message_id": time.Now().UnixNano()}, "")
+}
+
+func handleSendDocument(w http.ResponseWriter, r *http.Request, dir string) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		respond(w, false, nil, err.Error())
+		return
+	}
+	file, header, err := r.FormFile("document")
+	if err != nil {
+		respond(w, false, nil, err.Error())
+		return
+	}
+	defer file.Close()
+
+	path := filepath.Join(dir, filepath.Base(header.Filename))
+	out, err := os.Create(path)
+	if err != nil {
+		respond(w, false, nil, err.Error())
+		return
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, file); err != nil {
+		respond(w, false, nil, err.Error())
+		return
+	}
+
+	fmt.Printf("\n[бот -> %s] файл сохранен в %s\n%s\n\n", r.FormValue("chat_id"), path, r.FormValue("caption"))
+	respond(w, true, map[string]int64{"message_id": time.Now().UnixNano()}, "")
+}
+
+// respond отправляет ответ в формате Bot API
+func respond(w http.ResponseWriter, ok bool, result interface{}, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ok":          ok,
+		"result":      result,
+		"description": description,
+	})
+}
@@ -0,0 +1,52 @@
+// Пример приемника webhook-хуков Aegis: проверяет подпись и выводит полученное событие.
+//
+// Запуск: AEGIS_WEBHOOK_SECRET=secret go run ./examples/webhook --listen :9000
+package main
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/aegis/aegis-cli/pkg/hooks"
+	"github.com/aegis/aegis-cli/pkg/models"
+)
+
+func main() {
+	listen := flag.String("listen", ":9000", "Адрес для приема запросов")
+	flag.Parse()
+
+	secret := os.Getenv("AEGIS_WEBHOOK_SECRET")
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "ошибка чтения тела", http.StatusBadRequest)
+			return
+		}
+
+		// Подпись сравнивается за постоянное время, чтобы не раскрывать ее по таймингам
+		if secret != "" && !hmac.Equal([]byte(r.Header.Get(hooks.SignatureHeader)), []byte(hooks.Sign(secret, body))) {
+			http.Error(w, "неверная подпись", http.StatusUnauthorized)
+			return
+		}
+
+		var event models.HookEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			// Тело сформировано пользовательским шаблоном
+			log.Printf("%s %s: %s", r.Header.Get(hooks.EventHeader), r.Header.Get(hooks.DeliveryHeader), body)
+			return
+		}
+
+		log.Printf("%s %s: сканирование %s (%s), критических уязвимостей: %d",
+			event.Event, r.Header.Get(hooks.DeliveryHeader), event.Scan.ID, event.Scan.Status,
+			event.Summary.Vulnerabilities.Critical)
+	})
+
+	log.Printf("Прием webhook на %s", *listen)
+	log.Fatal(http.ListenAndServe(*listen, nil))
+}
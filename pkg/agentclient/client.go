@@ -0,0 +1,297 @@
+package agentclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/tracing"
+)
+
+// Client представляет HTTP-клиент API агента
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string // Токен hooks_token агента для изменения хуков
+}
+
+// New создает клиент для агента, работающего на указанном хосте
+func New(host *models.Host) *Client {
+	return &Client{
+		baseURL:    fmt.Sprintf("http://%s:%d", host.Address, host.Port),
+		httpClient: &http.Client{Timeout: 30 * time.Second, Transport: tracing.NewTransport(nil)},
+	}
+}
+
+// WithToken задает токен, который клиент передает агенту при добавлении и удалении хуков
+func (c *Client) WithToken(token string) *Client {
+	c.token = token
+	return c
+}
+
+// healthTimeout ограничивает проверку доступности, чтобы недоступные агенты не задерживали опрос
+const healthTimeout = 5 * time.Second
+
+// Health проверяет, что агент запущен и отвечает
+func (c *Client) Health() error {
+	client := &http.Client{Timeout: healthTimeout, Transport: tracing.NewTransport(nil)}
+	resp, err := client.Get(c.baseURL + "/health")
+	if err != nil {
+		return i18n.Errorf("ошибка подключения к агенту: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return responseError(resp.StatusCode, body)
+	}
+	return nil
+}
+
+// GetInfo возвращает сведения об агенте: версии, возраст БД trivy, очередь и возможности
+func (c *Client) GetInfo() (*models.AgentInfo, error) {
+	body, err := c.get("/info")
+	if err != nil {
+		return nil, err
+	}
+
+	var info models.AgentInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, i18n.Errorf("ошибка декодирования ответа агента: %w", err)
+	}
+	return &info, nil
+}
+
+// ListContainers возвращает контейнеры хоста
+func (c *Client) ListContainers() ([]models.Container, error) {
+	body, err := c.get("/containers")
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.ContainerListResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, i18n.Errorf("ошибка декодирования ответа агента: %w", err)
+	}
+	return result.Containers, nil
+}
+
+// StartScan запускает сканирование контейнера и возвращает ID сканирования
+func (c *Client) StartScan(req models.ScanRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", i18n.Errorf("ошибка формирования запроса: %w", err)
+	}
+
+	body, err := c.send(http.MethodPost, "/scan", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	var result models.ScanResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", i18n.Errorf("ошибка декодирования ответа агента: %w", err)
+	}
+	return result.ScanID, nil
+}
+
+// GetScanStatus возвращает статус сканирования и его результаты, если оно завершено
+func (c *Client) GetScanStatus(scanID string) (*models.ScanStatusResponse, error) {
+	body, err := c.get("/scan/" + url.PathEscape(scanID))
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.ScanStatusResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, i18n.Errorf("ошибка декодирования ответа агента: %w", err)
+	}
+	return &result, nil
+}
+
+// GetSBOM загружает SBOM, построенный агентом при сканировании
+func (c *Client) GetSBOM(scanID, format string) ([]byte, error) {
+	query := url.Values{}
+	query.Set("format", format)
+
+	return c.get(fmt.Sprintf("/scan/%s/sbom?%s", url.PathEscape(scanID), query.Encode()))
+}
+
+// Audit запускает аудит конфигурации контейнеров. Если containerID пуст, проверяются все контейнеры
+func (c *Client) Audit(containerID string) (*models.AuditResponse, error) {
+	path := "/audit"
+	if containerID != "" {
+		path += "/" + url.PathEscape(containerID)
+	}
+
+	body, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.AuditResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, i18n.Errorf("ошибка декодирования ответа агента: %w", err)
+	}
+	return &result, nil
+}
+
+// GetHostPosture запрашивает проверку настроек Docker daemon на хосте
+func (c *Client) GetHostPosture() (*models.HostPosture, error) {
+	body, err := c.get("/host/posture")
+	if err != nil {
+		return nil, err
+	}
+
+	var posture models.HostPosture
+	if err := json.Unmarshal(body, &posture); err != nil {
+		return nil, i18n.Errorf("ошибка декодирования ответа агента: %w", err)
+	}
+	return &posture, nil
+}
+
+// ListHookExecutions возвращает историю выполнения хуков на агенте
+func (c *Client) ListHookExecutions(hookID, scanID string) ([]models.HookExecution, error) {
+	query := url.Values{}
+	if hookID != "" {
+		query.Set("hook_id", hookID)
+	}
+	if scanID != "" {
+		query.Set("scan_id", scanID)
+	}
+
+	path := "/hooks/executions"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	body, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.HookExecutionListResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, i18n.Errorf("ошибка декодирования ответа агента: %w", err)
+	}
+	return result.Executions, nil
+}
+
+// ListHookDeadLetters возвращает недоставленные события webhook на агенте
+func (c *Client) ListHookDeadLetters(hookID string) ([]models.HookDeadLetter, error) {
+	path := "/hooks/dead-letters"
+	if hookID != "" {
+		path += "?" + url.Values{"hook_id": {hookID}}.Encode()
+	}
+
+	body, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.HookDeadLetterListResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, i18n.Errorf("ошибка декодирования ответа агента: %w", err)
+	}
+	return result.DeadLetters, nil
+}
+
+// ListHooks возвращает хуки агента
+func (c *Client) ListHooks() ([]models.Hook, error) {
+	body, err := c.get("/hooks")
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.HookListResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, i18n.Errorf("ошибка декодирования ответа агента: %w", err)
+	}
+	return result.Hooks, nil
+}
+
+// SaveHook добавляет хук на агенте или обновляет хук с тем же ID
+func (c *Client) SaveHook(hook *models.Hook) error {
+	data, err := json.Marshal(hook)
+	if err != nil {
+		return i18n.Errorf("ошибка формирования запроса: %w", err)
+	}
+
+	_, err = c.send(http.MethodPost, "/hooks", bytes.NewReader(data))
+	return err
+}
+
+// DeleteHook удаляет хук, добавленный на агент через API
+func (c *Client) DeleteHook(id string) error {
+	_, err := c.send(http.MethodDelete, "/hooks/"+url.PathEscape(id), nil)
+	return err
+}
+
+// get выполняет GET-запрос и возвращает тело успешного ответа
+func (c *Client) get(path string) ([]byte, error) {
+	resp, err := c.httpClient.Get(c.baseURL + path)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка подключения к агенту: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка чтения ответа агента: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp.StatusCode, body)
+	}
+
+	return body, nil
+}
+
+// send выполняет запрос с телом в формате JSON и возвращает тело успешного ответа
+func (c *Client) send(method, path string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка создания запроса: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка подключения к агенту: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка чтения ответа агента: %w", err)
+	}
+
+	// Запуск сканирования возвращает 202 Accepted
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return nil, responseError(resp.StatusCode, respBody)
+	}
+
+	return respBody, nil
+}
+
+// responseError формирует ошибку из ответа агента вида {"error": "..."}
+func responseError(statusCode int, body []byte) error {
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Error != "" {
+		return i18n.Errorf("агент вернул статус %d: %s", statusCode, apiErr.Error)
+	}
+	return i18n.Errorf("агент вернул статус %d", statusCode)
+}
@@ -1,17 +1,30 @@
 package api
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aegis/aegis-cli/pkg/hooks"
+	"github.com/aegis/aegis-cli/pkg/metrics"
 	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/sbom"
 	"github.com/aegis/aegis-cli/pkg/scanner"
+	"github.com/aegis/aegis-cli/pkg/tracing"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Handler представляет HTTP-обработчик API агента
@@ -20,27 +33,62 @@ type Handler struct {
 	hookManager *hooks.Manager
 	router      *mux.Router
 	logger      *logrus.Logger
-	scans       map[string]*models.ScanStatusResponse
+	scanTimeout time.Duration // 0 - без ограничения
+	version     string        // Версия агента для GET /info
+	hooksToken  string        // Токен изменения хуков через API; пусто - изменение отключено
+
+	// Сканирования и построенные для них SBOM. Горутина сканирования меняет поля записи
+	// scans только под scansMu, обработчики запросов читают их под RLock
+	scans   map[string]*models.ScanStatusResponse
+	sboms   map[string]map[string]string // scan_id -> формат -> путь к файлу SBOM
+	scansMu sync.RWMutex
+
+	// Уязвимости последнего завершенного сканирования каждого репозитория образов
+	// для события on_new_vulnerability. Хранятся в памяти до перезапуска агента
+	previousScans map[string]previousScan
+	previousMu    sync.Mutex
 }
 
-// NewHandler создает новый обработчик API
-func NewHandler(scanner *scanner.Scanner, hookManager *hooks.Manager) http.Handler {
+// previousScan содержит уязвимости, найденные предыдущим сканированием образа
+type previousScan struct {
+	scanID          string
+	vulnerabilities map[string]bool // CVE|пакет
+}
+
+// NewHandler создает новый обработчик API. scanTimeout ограничивает длительность
+// сканирования вместе с ожиданием в очереди, 0 - без ограничения; version - версия агента;
+// hooksToken - токен для POST и DELETE /hooks, пустой токен отключает эти маршруты;
+// logger - журнал агента
+func NewHandler(scanner *scanner.Scanner, hookManager *hooks.Manager, scanTimeout time.Duration, version, hooksToken string, logger *logrus.Logger) http.Handler {
 	h := &Handler{
-		scanner:     scanner,
-		hookManager: hookManager,
-		router:      mux.NewRouter(),
-		logger:      logrus.New(),
-		scans:       make(map[string]*models.ScanStatusResponse),
+		scanner:       scanner,
+		hookManager:   hookManager,
+		router:        mux.NewRouter(),
+		logger:        logger,
+		scans:         make(map[string]*models.ScanStatusResponse),
+		sboms:         make(map[string]map[string]string),
+		scanTimeout:   scanTimeout,
+		version:       version,
+		hooksToken:    hooksToken,
+		previousScans: make(map[string]previousScan),
 	}
 
-	// Настройка логгера
-	h.logger.SetFormatter(&logrus.JSONFormatter{})
-
 	// Настройка маршрутов
 	h.router.HandleFunc("/containers", h.listContainers).Methods("GET")
 	h.router.HandleFunc("/scan", h.startScan).Methods("POST")
 	h.router.HandleFunc("/scan/{scan_id}", h.getScanStatus).Methods("GET")
+	h.router.HandleFunc("/scan/{scan_id}/sbom", h.getScanSBOM).Methods("GET")
+	h.router.HandleFunc("/audit", h.auditContainers).Methods("GET")
+	h.router.HandleFunc("/audit/{container_id}", h.auditContainers).Methods("GET")
+	h.router.HandleFunc("/host/posture", h.getHostPosture).Methods("GET")
+	h.router.HandleFunc("/hooks", h.listHooks).Methods("GET")
+	h.router.HandleFunc("/hooks", h.requireHooksToken(h.saveHook)).Methods("POST")
+	h.router.HandleFunc("/hooks/executions", h.listHookExecutions).Methods("GET")
+	h.router.HandleFunc("/hooks/dead-letters", h.listHookDeadLetters).Methods("GET")
+	h.router.HandleFunc("/hooks/{hook_id}", h.requireHooksToken(h.deleteHook)).Methods("DELETE")
 	h.router.HandleFunc("/health", h.healthCheck).Methods("GET")
+	h.router.HandleFunc("/info", h.getInfo).Methods("GET")
+	h.router.Handle("/metrics", metrics.Default).Methods("GET")
 
 	// Добавляем middleware для логирования запросов
 	h.router.Use(h.loggingMiddleware)
@@ -48,39 +96,62 @@ func NewHandler(scanner *scanner.Scanner, hookManager *hooks.Manager) http.Handl
 	return h.router
 }
 
-// loggingMiddleware добавляет логирование запросов
+// loggingMiddleware добавляет логирование запросов и span запроса. Контекст трассировки
+// берется из заголовка traceparent, который передает CLI; ID трассировки попадает в журнал
+// рядом с request_id
 func (h *Handler) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+		route := routeTemplate(r)
 
 		// Добавляем уникальный ID запроса
 		requestID := uuid.New().String()
-		ctx := r.Context()
+		ctx, span := tracing.Start(tracing.Extract(r.Context(), r.Header), r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("client.address", r.RemoteAddr),
+				attribute.String("aegis.request_id", requestID),
+			),
+		)
+		defer span.End()
 		r = r.WithContext(ctx)
 
+		fields := logrus.Fields{"request_id": requestID}
+		if traceID := tracing.TraceID(ctx); traceID != "" {
+			fields["trace_id"] = traceID
+		}
+
 		// Логируем запрос
-		h.logger.WithFields(logrus.Fields{
-			"request_id": requestID,
-			"method":     r.Method,
-			"path":       r.URL.Path,
-			"remote":     r.RemoteAddr,
+		h.logger.WithFields(fields).WithFields(logrus.Fields{
+			"method": r.Method,
+			"path":   r.URL.Path,
+			"remote": r.RemoteAddr,
 		}).Info("Request started")
 
-		next.ServeHTTP(w, r)
+		recorder := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		observeRequest(r, route, recorder.code, start)
+		span.SetAttributes(attribute.Int("http.response.status_code", recorder.code))
+		if recorder.code >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.code))
+		}
 
 		// Логируем завершение запроса
-		h.logger.WithFields(logrus.Fields{
-			"request_id": requestID,
-			"method":     r.Method,
-			"path":       r.URL.Path,
-			"duration":   time.Since(start).String(),
+		h.logger.WithFields(fields).WithFields(logrus.Fields{
+			"method":   r.Method,
+			"path":     r.URL.Path,
+			"status":   recorder.code,
+			"duration": time.Since(start).String(),
 		}).Info("Request completed")
 	})
 }
 
 // listContainers возвращает список контейнеров
 func (h *Handler) listContainers(w http.ResponseWriter, r *http.Request) {
-	containers, err := h.scanner.ListContainers()
+	containers, err := h.scanner.ListContainers(r.Context())
 	if err != nil {
 		h.respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Ошибка получения списка контейнеров: %v", err))
 		return
@@ -106,11 +177,19 @@ func (h *Handler) startScan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(req.Scanners) == 0 {
+		req.Scanners = scanner.DefaultScanners
+	}
+	if err := scanner.ValidateScanners(req.Scanners); err != nil {
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// Генерируем уникальный ID для сканирования
 	scanID := uuid.New().String()
 
 	// Получаем информацию о контейнере
-	container, err := h.scanner.GetContainer(req.ContainerID)
+	container, err := h.scanner.GetContainer(r.Context(), req.ContainerID)
 	if err != nil {
 		h.respondWithError(w, http.StatusNotFound, fmt.Sprintf("Контейнер не найден: %s", req.ContainerID))
 		return
@@ -124,31 +203,110 @@ func (h *Handler) startScan(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Сохраняем запись о сканировании
+	h.scansMu.Lock()
 	h.scans[scanID] = scan
+	h.scansMu.Unlock()
+
+	// Сканирование продолжается после ответа, поэтому не зависит от отмены запроса,
+	// но его span остается в трассировке запроса
+	scanCtx, span := tracing.Start(context.WithoutCancel(r.Context()), "scan", trace.WithAttributes(
+		attribute.String("aegis.scan_id", scanID),
+		attribute.String("container.id", container.ID),
+		attribute.String("container.image.name", container.Image),
+		attribute.StringSlice("aegis.scanners", req.Scanners),
+	))
 
 	// Запускаем хук on_scan_start
-	go h.hookManager.ExecuteHooks("on_scan_start", scanID)
+	go h.hookManager.ExecuteHooksContext(scanCtx, h.hookEvent(models.HookEventScanStart, scan, container, req.Scanners))
 
 	// Запускаем сканирование в горутине
 	go func() {
+		var scanErr error
+		defer func() { tracing.End(span, scanErr) }()
+		h.scansMu.Lock()
 		scan.Status = "running"
+		h.scansMu.Unlock()
+
+		ctx, cancel := context.WithCancel(scanCtx)
+		if h.scanTimeout > 0 {
+			ctx, cancel = context.WithTimeout(scanCtx, h.scanTimeout)
+		}
+		defer cancel()
 
-		results, err := h.scanner.ScanContainer(container)
+		result, err := h.scanner.ScanContainerContext(ctx, container, req.Scanners)
 		if err != nil {
+			scanErr = err
+			timedOut := errors.Is(err, context.DeadlineExceeded)
+			finishedAt := time.Now()
+			h.scansMu.Lock()
 			scan.Status = "failed"
 			scan.ErrorMsg = err.Error()
-			// Запускаем хук on_error
-			h.hookManager.ExecuteHooks("on_error", scanID)
+			if timedOut {
+				scan.ErrorMsg = fmt.Sprintf("превышен таймаут сканирования %s", h.scanTimeout)
+			}
+			scan.FinishedAt = &finishedAt
+			h.scansMu.Unlock()
+			if timedOut {
+				observeScan(scan, scanMetricTimeout)
+			} else {
+				observeScan(scan, scan.Status)
+			}
+
+			// Таймаут - частный случай ошибки: хуки on_error получают и его
+			if timedOut {
+				h.hookManager.ExecuteHooksContext(scanCtx, h.hookEvent(models.HookEventScanTimeout, scan, container, req.Scanners))
+			}
+			h.hookManager.ExecuteHooksContext(scanCtx, h.hookEvent(models.HookEventError, scan, container, req.Scanners))
 			return
 		}
 
+		// Строим SBOM до смены статуса, чтобы клиент получил их вместе с результатами.
+		// Список пакетов попадает в отчет Trivy только при сканировании уязвимостей
+		var sbomFiles map[string]string
+		var sbomFormats []string
+		if scanner.HasScanner(req.Scanners, scanner.ScannerVuln) {
+			sbomFiles = h.scanner.GenerateSBOMs(scanCtx, result.ReportPath)
+			for _, format := range sbom.Formats {
+				if _, ok := sbomFiles[format]; ok {
+					sbomFormats = append(sbomFormats, format)
+				}
+			}
+		}
+
 		finishedAt := time.Now()
-		scan.Status = "completed"
+		h.scansMu.Lock()
+		if sbomFiles != nil {
+			h.sboms[scanID] = sbomFiles
+		}
+		scan.SBOMFormats = sbomFormats
+		scan.ImageDigest = result.ImageDigest
+		scan.Vulnerabilities = result.Vulnerabilities
+		scan.Secrets = result.Secrets
+		scan.Misconfigs = result.Misconfigs
 		scan.FinishedAt = &finishedAt
-		scan.Vulnerabilities = results
+		scan.Status = "completed"
+		h.scansMu.Unlock()
+		observeScan(scan, scan.Status)
+		span.SetAttributes(
+			attribute.Int("aegis.vulnerabilities", len(scan.Vulnerabilities)),
+			attribute.Int("aegis.secrets", len(scan.Secrets)),
+			attribute.Int("aegis.misconfigs", len(scan.Misconfigs)),
+		)
 
 		// Запускаем хук on_scan_complete
-		h.hookManager.ExecuteHooks("on_scan_complete", scanID)
+		event := h.hookEvent(models.HookEventScanComplete, scan, container, req.Scanners)
+		h.hookManager.ExecuteHooksContext(scanCtx, event)
+
+		// Порог серьезности и количества находок для on_critical_found задается в каждом хуке
+		if event.Summary.AtLeast("LOW") > 0 {
+			critical := *event
+			critical.Event = models.HookEventCriticalFound
+			h.hookManager.ExecuteHooksContext(scanCtx, &critical)
+		}
+
+		if newEvent := h.newVulnerabilityEvent(event, container); newEvent != nil {
+			h.hookManager.ExecuteHooksContext(scanCtx, newEvent)
+		}
 	}()
 
 	// Отправляем ID сканирования клиенту
@@ -159,18 +317,299 @@ func (h *Handler) startScan(w http.ResponseWriter, r *http.Request) {
 	h.respondWithJSON(w, http.StatusAccepted, response)
 }
 
+// hookEvent формирует событие для хуков по текущему состоянию сканирования
+func (h *Handler) hookEvent(event string, scan *models.ScanStatusResponse, container *models.Container, scanners []string) *models.HookEvent {
+	hostname, _ := os.Hostname()
+
+	result := &models.HookEvent{
+		Version:   models.HookEventVersion,
+		Event:     event,
+		Timestamp: time.Now(),
+		Host: models.HookEventHost{
+			Hostname: hostname,
+			Runtime:  h.scanner.RuntimeName(),
+		},
+		Container: container,
+		Scan: models.HookEventScan{
+			ID:          scan.ScanID,
+			Status:      scan.Status,
+			Scanners:    scanners,
+			ImageDigest: scan.ImageDigest,
+			Error:       scan.ErrorMsg,
+			StartedAt:   scan.StartedAt,
+			FinishedAt:  scan.FinishedAt,
+		},
+		Vulnerabilities: scan.Vulnerabilities,
+		Secrets:         scan.Secrets,
+		Misconfigs:      scan.Misconfigs,
+	}
+
+	if scan.FinishedAt != nil {
+		result.Scan.DurationMs = scan.FinishedAt.Sub(scan.StartedAt).Milliseconds()
+	}
+	for _, v := range scan.Vulnerabilities {
+		result.Summary.Vulnerabilities.Add(v.Severity)
+	}
+	for _, f := range scan.Secrets {
+		result.Summary.Secrets.Add(f.Severity)
+	}
+	for _, f := range scan.Misconfigs {
+		result.Summary.Misconfigs.Add(f.Severity)
+	}
+
+	return result
+}
+
+// newVulnerabilityEvent сравнивает уязвимости сканирования с предыдущим сканированием того же
+// репозитория образов и возвращает событие on_new_vulnerability со списком новых уязвимостей.
+// Первое сканирование образа после запуска агента становится базовым, событие не формируется
+func (h *Handler) newVulnerabilityEvent(event *models.HookEvent, container *models.Container) *models.HookEvent {
+	current := make(map[string]bool, len(event.Vulnerabilities))
+	for _, v := range event.Vulnerabilities {
+		current[vulnerabilityKey(v)] = true
+	}
+
+	// Обновление тега сравнивается с предыдущей версией образа
+	repository := models.ImageRepository(container.Image)
+
+	h.previousMu.Lock()
+	previous, ok := h.previousScans[repository]
+	h.previousScans[repository] = previousScan{scanID: event.Scan.ID, vulnerabilities: current}
+	h.previousMu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	var added []models.Vulnerability
+	for _, v := range event.Vulnerabilities {
+		if !previous.vulnerabilities[vulnerabilityKey(v)] {
+			added = append(added, v)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	result := *event
+	result.Event = models.HookEventNewVulnerability
+	result.Scan.PreviousScanID = previous.scanID
+	result.Vulnerabilities = added
+	result.Secrets = nil
+	result.Misconfigs = nil
+	result.Summary = models.ScanSummary{}
+	for _, v := range added {
+		result.Summary.Vulnerabilities.Add(v.Severity)
+	}
+	return &result
+}
+
+// vulnerabilityKey идентифицирует уязвимость пакета независимо от сканирования
+func vulnerabilityKey(v models.Vulnerability) string {
+	return v.VulnerabilityID + "|" + v.Package
+}
+
 // getScanStatus возвращает статус сканирования
 func (h *Handler) getScanStatus(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	scanID := vars["scan_id"]
 
+	// Копия записи, чтобы не кодировать ее в JSON под блокировкой
+	h.scansMu.RLock()
 	scan, exists := h.scans[scanID]
+	var status models.ScanStatusResponse
+	if exists {
+		status = *scan
+	}
+	h.scansMu.RUnlock()
+	if !exists {
+		h.respondWithError(w, http.StatusNotFound, fmt.Sprintf("Сканирование не найдено: %s", scanID))
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, status)
+}
+
+// getScanSBOM возвращает SBOM образа, построенный при сканировании
+func (h *Handler) getScanSBOM(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scanID := vars["scan_id"]
+
+	h.scansMu.RLock()
+	_, exists := h.scans[scanID]
+	h.scansMu.RUnlock()
 	if !exists {
 		h.respondWithError(w, http.StatusNotFound, fmt.Sprintf("Сканирование не найдено: %s", scanID))
 		return
 	}
 
-	h.respondWithJSON(w, http.StatusOK, scan)
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = sbom.FormatCycloneDX
+	}
+	if !sbom.IsValidFormat(format) {
+		h.respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Неподдерживаемый формат SBOM: %s", format))
+		return
+	}
+
+	h.scansMu.RLock()
+	path, exists := h.sboms[scanID][format]
+	h.scansMu.RUnlock()
+	if !exists {
+		h.respondWithError(w, http.StatusNotFound, fmt.Sprintf("SBOM в формате %s для сканирования %s не найден", format, scanID))
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		h.respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Ошибка чтения SBOM: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", sbom.ContentType(format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// auditContainers проверяет конфигурацию контейнеров по правилам CIS Docker Benchmark
+func (h *Handler) auditContainers(w http.ResponseWriter, r *http.Request) {
+	containerID := mux.Vars(r)["container_id"]
+
+	if containerID != "" {
+		if _, err := h.scanner.GetContainer(r.Context(), containerID); err != nil {
+			h.respondWithError(w, http.StatusNotFound, fmt.Sprintf("Контейнер не найден: %s", containerID))
+			return
+		}
+	}
+
+	result, err := h.scanner.AuditContainers(r.Context(), containerID)
+	if err != nil {
+		h.respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Ошибка аудита контейнеров: %v", err))
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, result)
+}
+
+// getHostPosture возвращает результаты проверки настроек Docker daemon на хосте
+func (h *Handler) getHostPosture(w http.ResponseWriter, r *http.Request) {
+	posture, err := h.scanner.HostPosture(r.Context())
+	if err != nil {
+		h.respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Ошибка проверки хоста: %v", err))
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, posture)
+}
+
+// secretMask заменяет секрет webhook в ответах API
+const secretMask = "******"
+
+// listHooks возвращает хуки агента из конфигурации и добавленные через API
+func (h *Handler) listHooks(w http.ResponseWriter, r *http.Request) {
+	hookList := h.hookManager.ListHooks()
+	for i := range hookList {
+		if hookList[i].Secret != "" {
+			hookList[i].Secret = secretMask
+		}
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.HookListResponse{Hooks: hookList})
+}
+
+// requireHooksToken пропускает запрос, только если он передает токен hooks_token из конфигурации
+// агента в заголовке Authorization: Bearer. Хук может запустить любую программу хоста или
+// отправить результаты сканирования на любой адрес, поэтому без токена изменение хуков
+// через API отключено
+func (h *Handler) requireHooksToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.hooksToken == "" {
+			h.respondWithError(w, http.StatusForbidden, "Управление хуками через API отключено: задайте hooks_token в конфигурации агента")
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.hooksToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			h.respondWithError(w, http.StatusUnauthorized, "Неверный токен управления хуками")
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// saveHook добавляет хук или обновляет хук с тем же ID
+func (h *Handler) saveHook(w http.ResponseWriter, r *http.Request) {
+	var hook models.Hook
+	if err := json.NewDecoder(r.Body).Decode(&hook); err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Неверный формат JSON")
+		return
+	}
+
+	if hook.ID == "" {
+		hook.ID = uuid.New().String()
+	}
+	if hook.TimeoutSeconds == 0 {
+		hook.TimeoutSeconds = 30
+	}
+	now := time.Now()
+	if hook.CreatedAt.IsZero() {
+		hook.CreatedAt = now
+	}
+	hook.UpdatedAt = now
+
+	if err := h.hookManager.SaveHook(hook); err != nil {
+		h.respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Ошибка сохранения хука: %v", err))
+		return
+	}
+
+	if hook.Secret != "" {
+		hook.Secret = secretMask
+	}
+	hook.Source = models.HookSourceAPI
+	h.respondWithJSON(w, http.StatusOK, hook)
+}
+
+// deleteHook удаляет хук, добавленный через API
+func (h *Handler) deleteHook(w http.ResponseWriter, r *http.Request) {
+	hookID := mux.Vars(r)["hook_id"]
+
+	if _, err := h.hookManager.GetHook(hookID); err != nil {
+		h.respondWithError(w, http.StatusNotFound, fmt.Sprintf("Хук не найден: %s", hookID))
+		return
+	}
+
+	if err := h.hookManager.DeleteHook(hookID); err != nil {
+		h.respondWithError(w, http.StatusConflict, fmt.Sprintf("Ошибка удаления хука: %v", err))
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// listHookExecutions возвращает историю выполнения хуков агента
+func (h *Handler) listHookExecutions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	executions, err := h.hookManager.ListExecutions(query.Get("hook_id"), query.Get("scan_id"))
+	if err != nil {
+		h.respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Ошибка получения истории хуков: %v", err))
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.HookExecutionListResponse{Executions: executions})
+}
+
+// listHookDeadLetters возвращает события webhook, которые не удалось доставить
+func (h *Handler) listHookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	letters, err := h.hookManager.ListDeadLetters(r.URL.Query().Get("hook_id"))
+	if err != nil {
+		h.respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Ошибка получения недоставленных событий: %v", err))
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.HookDeadLetterListResponse{DeadLetters: letters})
 }
 
 // healthCheck проверяет работоспособность агента
@@ -183,6 +622,53 @@ func (h *Handler) healthCheck(w http.ResponseWriter, r *http.Request) {
 	h.respondWithJSON(w, http.StatusOK, response)
 }
 
+// getInfo возвращает сведения об агенте: версии, состояние БД trivy, очередь и возможности.
+// Недоступные сведения (нет trivy, daemon не отвечает) перечисляются в errors, ответ остается 200
+func (h *Handler) getInfo(w http.ResponseWriter, r *http.Request) {
+	concurrency, running, queued := h.scanner.QueueState()
+	info := models.AgentInfo{
+		CollectedAt:     time.Now(),
+		Version:         h.version,
+		GoVersion:       runtime.Version(),
+		Runtime:         h.scanner.RuntimeName(),
+		ScanConcurrency: concurrency,
+		ScansRunning:    running,
+		ScansQueued:     queued,
+		Scanners:        []string{scanner.ScannerVuln, scanner.ScannerSecret, scanner.ScannerMisconfig},
+		Features: []string{
+			models.AgentFeatureSBOM,
+			models.AgentFeatureAudit,
+			models.AgentFeatureHooks,
+			models.AgentFeatureWebhooks,
+			models.AgentFeatureMetrics,
+		},
+	}
+	if h.scanTimeout > 0 {
+		info.Features = append(info.Features, models.AgentFeatureScanTimeout)
+	}
+
+	runtimeVersion, err := h.scanner.RuntimeVersion(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to get runtime version")
+		info.Errors = append(info.Errors, err.Error())
+	}
+	info.RuntimeVersion = runtimeVersion
+	if h.scanner.SupportsHostPosture() {
+		info.Features = append(info.Features, models.AgentFeatureHostPosture)
+	}
+
+	trivy, err := h.scanner.TrivyVersion(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Warn("Failed to get trivy version")
+		info.Errors = append(info.Errors, err.Error())
+	} else {
+		info.TrivyVersion = trivy.Version
+		info.TrivyDBUpdatedAt = trivy.DBUpdatedAt
+	}
+
+	h.respondWithJSON(w, http.StatusOK, info)
+}
+
 // respondWithJSON отправляет JSON-ответ
 func (h *Handler) respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	response, err := json.Marshal(payload)
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aegis/aegis-cli/pkg/hooks"
+	"github.com/sirupsen/logrus"
+)
+
+// hookRequest выполняет запрос к маршрутам управления хуками с токеном token
+func hookRequest(t *testing.T, server *httptest.Server, method, path, token, body string) int {
+	t.Helper()
+
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func newHooksTestServer(t *testing.T, hooksToken string) *httptest.Server {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	server := httptest.NewServer(NewHandler(nil, hooks.NewManager(nil, nil), 0, "test", hooksToken, logger))
+	t.Cleanup(server.Close)
+	return server
+}
+
+const testWebhook = `{"id":"hook-api","name":"SIEM","event":"on_scan_complete","type":"webhook","url":"https://siem.example.com/aegis","enabled":true}`
+
+func TestHookManagementDisabledWithoutToken(t *testing.T) {
+	server := newHooksTestServer(t, "")
+
+	if code := hookRequest(t, server, http.MethodPost, "/hooks", "anything", testWebhook); code != http.StatusForbidden {
+		t.Errorf("POST /hooks: статус %d, ожидается %d", code, http.StatusForbidden)
+	}
+	if code := hookRequest(t, server, http.MethodDelete, "/hooks/hook-api", "anything", ""); code != http.StatusForbidden {
+		t.Errorf("DELETE /hooks/{hook_id}: статус %d, ожидается %d", code, http.StatusForbidden)
+	}
+	// Чтение списка хуков не требует токена
+	if code := hookRequest(t, server, http.MethodGet, "/hooks", "", ""); code != http.StatusOK {
+		t.Errorf("GET /hooks: статус %d", code)
+	}
+}
+
+func TestHookManagementRequiresToken(t *testing.T) {
+	server := newHooksTestServer(t, "s3cr3t")
+
+	for _, token := range []string{"", "wrong"} {
+		if code := hookRequest(t, server, http.MethodPost, "/hooks", token, testWebhook); code != http.StatusUnauthorized {
+			t.Errorf("POST /hooks с токеном %q: статус %d, ожидается %d", token, code, http.StatusUnauthorized)
+		}
+	}
+
+	if code := hookRequest(t, server, http.MethodPost, "/hooks", "s3cr3t", testWebhook); code != http.StatusOK {
+		t.Fatalf("POST /hooks: статус %d", code)
+	}
+	if code := hookRequest(t, server, http.MethodDelete, "/hooks/hook-api", "wrong", ""); code != http.StatusUnauthorized {
+		t.Errorf("DELETE /hooks/{hook_id} с неверным токеном: статус %d, ожидается %d", code, http.StatusUnauthorized)
+	}
+	if code := hookRequest(t, server, http.MethodDelete, "/hooks/hook-api", "s3cr3t", ""); code != http.StatusOK {
+		t.Errorf("DELETE /hooks/{hook_id}: статус %d", code)
+	}
+}
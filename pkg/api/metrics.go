@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/metrics"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/gorilla/mux"
+)
+
+// Метрики HTTP-запросов и сканирований, запущенных через API
+var (
+	httpRequests = metrics.NewCounterVec("aegis_agent_http_requests_total",
+		"HTTP requests by method, route and status code.", "method", "route", "code")
+	httpDuration = metrics.NewHistogramVec("aegis_agent_http_request_duration_seconds",
+		"HTTP request latency by method and route.", metrics.DefBuckets, "method", "route")
+
+	scansTotal = metrics.NewCounterVec("aegis_agent_scans_total",
+		"Finished scans by status (completed, failed, timeout).", "status")
+	scanDuration = metrics.NewHistogramVec("aegis_agent_scan_duration_seconds",
+		"Scan duration including queue wait and SBOM generation, by status.",
+		[]float64{1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600}, "status")
+)
+
+// Статус сканирования в метриках: таймаут учитывается отдельно от остальных ошибок
+const scanMetricTimeout = "timeout"
+
+// observeScan учитывает завершившееся сканирование
+func observeScan(scan *models.ScanStatusResponse, status string) {
+	scansTotal.Inc(status)
+	if scan.FinishedAt != nil {
+		scanDuration.Observe(scan.FinishedAt.Sub(scan.StartedAt).Seconds(), status)
+	}
+}
+
+// observeRequest учитывает HTTP-запрос
+func observeRequest(r *http.Request, route string, code int, start time.Time) {
+	httpRequests.Inc(r.Method, route, strconv.Itoa(code))
+	httpDuration.Observe(time.Since(start).Seconds(), r.Method, route)
+}
+
+// routeTemplate возвращает шаблон пути маршрута запроса, чтобы ID сканирований
+// и контейнеров не порождали отдельные серии метрик и имена span
+func routeTemplate(r *http.Request) string {
+	if current := mux.CurrentRoute(r); current != nil {
+		if template, err := current.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return "unknown"
+}
+
+// statusRecorder запоминает код ответа для метрик
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.code = code
+	r.ResponseWriter.WriteHeader(code)
+}
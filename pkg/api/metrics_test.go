@@ -0,0 +1,74 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aegis/aegis-cli/pkg/hooks"
+	"github.com/aegis/aegis-cli/pkg/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// TestMetricsEndpoint проверяет, что запросы к API учитываются в метриках и GET /metrics
+// отдает их в текстовом формате Prometheus
+func TestMetricsEndpoint(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	// Маршруты /health и /scan/{scan_id} не обращаются к сканеру
+	server := httptest.NewServer(NewHandler(nil, hooks.NewManager(nil, nil), 0, "test", "", logger))
+	defer server.Close()
+
+	get := func(path string) (int, string, string) {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("GET %s: чтение ответа: %v", path, err)
+		}
+		return resp.StatusCode, resp.Header.Get("Content-Type"), string(body)
+	}
+
+	if code, _, _ := get("/health"); code != http.StatusOK {
+		t.Fatalf("GET /health: статус %d", code)
+	}
+	if code, _, _ := get("/scan/0b3c9e1e-unknown"); code != http.StatusNotFound {
+		t.Fatalf("GET /scan/{scan_id}: статус %d", code)
+	}
+
+	code, contentType, body := get("/metrics")
+	if code != http.StatusOK {
+		t.Fatalf("GET /metrics: статус %d", code)
+	}
+	if contentType != metrics.ContentType {
+		t.Errorf("Content-Type = %q, ожидается %q", contentType, metrics.ContentType)
+	}
+
+	for _, line := range []string{
+		"# TYPE aegis_agent_http_requests_total counter",
+		`aegis_agent_http_requests_total{method="GET",route="/health",code="200"} 1`,
+		// ID сканирования не попадает в метки: используется шаблон маршрута
+		`aegis_agent_http_requests_total{method="GET",route="/scan/{scan_id}",code="404"} 1`,
+		"# TYPE aegis_agent_http_request_duration_seconds histogram",
+		`aegis_agent_http_request_duration_seconds_bucket{method="GET",route="/health",le="+Inf"} 1`,
+		`aegis_agent_http_request_duration_seconds_count{method="GET",route="/health"} 1`,
+		"# TYPE aegis_agent_scans_running gauge",
+		"aegis_agent_scans_running 0",
+		"# TYPE aegis_agent_scans_total counter",
+		"# TYPE aegis_agent_hook_executions_total counter",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("нет строки %q в выводе:\n%s", line, body)
+		}
+	}
+	if strings.Contains(body, "0b3c9e1e-unknown") {
+		t.Errorf("ID сканирования попал в метки:\n%s", body)
+	}
+}
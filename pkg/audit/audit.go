@@ -0,0 +1,209 @@
+package audit
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/google/uuid"
+)
+
+// Rule представляет правило аудита конфигурации контейнера
+type Rule struct {
+	ID          string // Номер рекомендации CIS Docker Benchmark
+	Severity    string
+	Title       string
+	Remediation string
+	// Check возвращает описание нарушения или пустую строку, если контейнер соответствует правилу
+	Check func(info *container.InspectResponse) string
+}
+
+// sensitiveHostPaths содержит системные каталоги хоста, которые не должны монтироваться в контейнер (CIS 5.5)
+var sensitiveHostPaths = []string{"/", "/boot", "/dev", "/etc", "/lib", "/proc", "/sys", "/usr"}
+
+// runtimeSockets содержит сокеты среды выполнения, дающие полный контроль над хостом (CIS 5.31)
+var runtimeSockets = []string{"docker.sock", "podman.sock", "containerd.sock"}
+
+// Rules содержит набор правил аудита в порядке рекомендаций CIS Docker Benchmark
+var Rules = []Rule{
+	{
+		ID:          "4.1",
+		Severity:    "MEDIUM",
+		Title:       "Контейнер запущен от имени root",
+		Remediation: "Создайте в образе непривилегированного пользователя и укажите его в USER или через --user",
+		Check: func(info *container.InspectResponse) string {
+			user := ""
+			if info.Config != nil {
+				user = info.Config.User
+			}
+			name := strings.SplitN(user, ":", 2)[0]
+			if name == "" || name == "root" || name == "0" {
+				if user == "" {
+					return "пользователь не задан (root по умолчанию)"
+				}
+				return fmt.Sprintf("пользователь: %s", user)
+			}
+			return ""
+		},
+	},
+	{
+		ID:          "5.3",
+		Severity:    "HIGH",
+		Title:       "Контейнеру добавлены capabilities ядра",
+		Remediation: "Удалите --cap-add или оставьте только необходимые capabilities, начиная с --cap-drop=ALL",
+		Check: func(info *container.InspectResponse) string {
+			if info.HostConfig == nil || len(info.HostConfig.CapAdd) == 0 {
+				return ""
+			}
+			return fmt.Sprintf("cap_add: %s", strings.Join(info.HostConfig.CapAdd, ", "))
+		},
+	},
+	{
+		ID:          "5.4",
+		Severity:    "CRITICAL",
+		Title:       "Контейнер запущен в привилегированном режиме",
+		Remediation: "Не используйте --privileged; выдайте контейнеру только нужные устройства и capabilities",
+		Check: func(info *container.InspectResponse) string {
+			if info.HostConfig != nil && info.HostConfig.Privileged {
+				return "privileged: true"
+			}
+			return ""
+		},
+	},
+	{
+		ID:          "5.5",
+		Severity:    "HIGH",
+		Title:       "В контейнер смонтированы системные каталоги хоста",
+		Remediation: "Не монтируйте системные каталоги хоста; используйте тома Docker для данных приложения",
+		Check: func(info *container.InspectResponse) string {
+			var found []string
+			for _, m := range info.Mounts {
+				if m.Type != mount.TypeBind {
+					continue
+				}
+				if isSensitivePath(m.Source) {
+					found = append(found, describeMount(m))
+				}
+			}
+			return strings.Join(found, ", ")
+		},
+	},
+	{
+		ID:          "5.9",
+		Severity:    "HIGH",
+		Title:       "Контейнер использует сетевое пространство имен хоста",
+		Remediation: "Не используйте --network=host; публикуйте нужные порты через -p",
+		Check: func(info *container.InspectResponse) string {
+			if info.HostConfig != nil && info.HostConfig.NetworkMode.IsHost() {
+				return "network_mode: host"
+			}
+			return ""
+		},
+	},
+	{
+		ID:          "5.10",
+		Severity:    "MEDIUM",
+		Title:       "Не ограничено потребление памяти",
+		Remediation: "Задайте лимит памяти через --memory",
+		Check: func(info *container.InspectResponse) string {
+			if info.HostConfig != nil && info.HostConfig.Memory == 0 {
+				return "memory: 0 (без ограничений)"
+			}
+			return ""
+		},
+	},
+	{
+		ID:          "5.12",
+		Severity:    "LOW",
+		Title:       "Корневая файловая система контейнера доступна для записи",
+		Remediation: "Запускайте контейнер с --read-only и монтируйте тома только для изменяемых данных",
+		Check: func(info *container.InspectResponse) string {
+			if info.HostConfig != nil && !info.HostConfig.ReadonlyRootfs {
+				return "read_only: false"
+			}
+			return ""
+		},
+	},
+	{
+		ID:          "5.15",
+		Severity:    "HIGH",
+		Title:       "Контейнер использует пространство имен процессов хоста",
+		Remediation: "Не используйте --pid=host",
+		Check: func(info *container.InspectResponse) string {
+			if info.HostConfig != nil && info.HostConfig.PidMode.IsHost() {
+				return "pid_mode: host"
+			}
+			return ""
+		},
+	},
+	{
+		ID:          "5.31",
+		Severity:    "CRITICAL",
+		Title:       "В контейнер смонтирован сокет среды выполнения контейнеров",
+		Remediation: "Не монтируйте docker.sock в контейнеры; для управления Docker используйте прокси с ограниченным API",
+		Check: func(info *container.InspectResponse) string {
+			var found []string
+			for _, m := range info.Mounts {
+				for _, socket := range runtimeSockets {
+					if path.Base(m.Source) == socket {
+						found = append(found, describeMount(m))
+						break
+					}
+				}
+			}
+			return strings.Join(found, ", ")
+		},
+	},
+}
+
+// Check проверяет контейнер по всем правилам и возвращает найденные нарушения
+func Check(info *container.InspectResponse, hostID string) []models.AuditFinding {
+	var findings []models.AuditFinding
+	now := time.Now()
+
+	for _, rule := range Rules {
+		details := rule.Check(info)
+		if details == "" {
+			continue
+		}
+
+		findings = append(findings, models.AuditFinding{
+			ID:            uuid.New().String(),
+			HostID:        hostID,
+			ContainerID:   info.ID,
+			ContainerName: strings.TrimPrefix(info.Name, "/"),
+			RuleID:        rule.ID,
+			Severity:      rule.Severity,
+			Title:         rule.Title,
+			Details:       details,
+			Remediation:   rule.Remediation,
+			AuditedAt:     now,
+		})
+	}
+
+	return findings
+}
+
+// isSensitivePath проверяет, относится ли путь хоста к системным каталогам
+func isSensitivePath(source string) bool {
+	source = path.Clean(source)
+	for _, p := range sensitiveHostPaths {
+		if source == p || (p != "/" && strings.HasPrefix(source, p+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeMount возвращает описание точки монтирования для отчета
+func describeMount(m container.MountPoint) string {
+	mode := "ro"
+	if m.RW {
+		mode = "rw"
+	}
+	return fmt.Sprintf("%s:%s:%s", m.Source, m.Destination, mode)
+}
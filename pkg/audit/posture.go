@@ -0,0 +1,215 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/system"
+)
+
+// DaemonConfigPath содержит путь к файлу конфигурации Docker daemon
+const DaemonConfigPath = "/etc/docker/daemon.json"
+
+// MinSupportedDockerMajor содержит минимальную мажорную версию Docker Engine,
+// которая еще получает исправления безопасности. Обновляется вместе с графиком выпусков Docker
+const MinSupportedDockerMajor = 27
+
+// daemonConfig описывает используемую часть daemon.json
+type daemonConfig struct {
+	Hosts     []string `json:"hosts"`
+	TLS       bool     `json:"tls"`
+	TLSVerify bool     `json:"tlsverify"`
+}
+
+// PostureInput содержит данные, собранные агентом для проверки состояния хоста
+type PostureInput struct {
+	Info         system.Info
+	Version      types.Version
+	DaemonConfig []byte // Содержимое daemon.json (пусто, если файла нет)
+	ContentTrust bool   // Значение DOCKER_CONTENT_TRUST в окружении агента
+}
+
+// CheckPosture проверяет настройки Docker daemon на хосте
+func CheckPosture(input PostureInput) *models.HostPosture {
+	posture := &models.HostPosture{
+		CheckedAt:     time.Now(),
+		DockerVersion: input.Version.Version,
+		APIVersion:    input.Version.APIVersion,
+		OS:            input.Info.OperatingSystem,
+		KernelVersion: input.Info.KernelVersion,
+	}
+
+	var daemon daemonConfig
+	if len(input.DaemonConfig) > 0 {
+		if err := json.Unmarshal(input.DaemonConfig, &daemon); err != nil {
+			posture.Checks = append(posture.Checks, models.PostureCheck{
+				ID:      "daemon_config",
+				Title:   "Файл конфигурации Docker daemon",
+				Status:  models.PostureStatusWarn,
+				Details: fmt.Sprintf("ошибка разбора %s: %v", DaemonConfigPath, err),
+			})
+		}
+	}
+
+	posture.Checks = append(posture.Checks,
+		checkDockerVersion(input.Version.Version),
+		checkUserNamespaces(input.Info.SecurityOptions),
+		checkLiveRestore(input.Info.LiveRestoreEnabled),
+		checkContentTrust(input.ContentTrust),
+		checkInsecureRegistries(input.Info),
+		checkDaemonTLS(daemon),
+	)
+
+	return posture
+}
+
+// checkDockerVersion проверяет, поддерживается ли версия Docker Engine
+func checkDockerVersion(version string) models.PostureCheck {
+	check := models.PostureCheck{
+		ID:    "docker_version",
+		Title: "Версия Docker Engine",
+	}
+
+	major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	switch {
+	case err != nil:
+		check.Status = models.PostureStatusWarn
+		check.Details = fmt.Sprintf("не удалось определить версию: %q", version)
+	case major < MinSupportedDockerMajor:
+		check.Status = models.PostureStatusFail
+		check.Details = fmt.Sprintf("версия %s больше не поддерживается (EOL), требуется %d.x или новее", version, MinSupportedDockerMajor)
+	default:
+		check.Status = models.PostureStatusPass
+		check.Details = fmt.Sprintf("версия %s поддерживается", version)
+	}
+
+	return check
+}
+
+// checkUserNamespaces проверяет включение user namespaces (userns-remap)
+func checkUserNamespaces(securityOptions []string) models.PostureCheck {
+	check := models.PostureCheck{
+		ID:      "userns",
+		Title:   "Изоляция user namespaces",
+		Status:  models.PostureStatusWarn,
+		Details: "userns-remap не настроен: root в контейнере соответствует root на хосте",
+	}
+
+	for _, opt := range securityOptions {
+		if strings.Contains(opt, "name=userns") {
+			check.Status = models.PostureStatusPass
+			check.Details = "userns-remap включен"
+			break
+		}
+	}
+
+	return check
+}
+
+// checkLiveRestore проверяет параметр live-restore
+func checkLiveRestore(enabled bool) models.PostureCheck {
+	check := models.PostureCheck{
+		ID:      "live_restore",
+		Title:   "Live restore",
+		Status:  models.PostureStatusPass,
+		Details: "контейнеры продолжают работу при перезапуске daemon",
+	}
+
+	if !enabled {
+		check.Status = models.PostureStatusWarn
+		check.Details = "live-restore выключен: обновление daemon останавливает контейнеры"
+	}
+
+	return check
+}
+
+// checkContentTrust проверяет включение Docker Content Trust
+func checkContentTrust(enabled bool) models.PostureCheck {
+	check := models.PostureCheck{
+		ID:      "content_trust",
+		Title:   "Docker Content Trust",
+		Status:  models.PostureStatusPass,
+		Details: "DOCKER_CONTENT_TRUST=1: загружаются только подписанные образы",
+	}
+
+	if !enabled {
+		check.Status = models.PostureStatusWarn
+		check.Details = "DOCKER_CONTENT_TRUST не включен: подписи образов не проверяются"
+	}
+
+	return check
+}
+
+// checkInsecureRegistries проверяет наличие реестров без TLS, кроме локальных адресов
+func checkInsecureRegistries(info system.Info) models.PostureCheck {
+	check := models.PostureCheck{
+		ID:      "insecure_registries",
+		Title:   "Небезопасные реестры",
+		Status:  models.PostureStatusPass,
+		Details: "реестры без TLS не настроены",
+	}
+
+	if info.RegistryConfig == nil {
+		return check
+	}
+
+	var insecure []string
+	for _, cidr := range info.RegistryConfig.InsecureRegistryCIDRs {
+		if cidr == nil {
+			continue
+		}
+		if value := cidr.String(); value != "127.0.0.0/8" && value != "::1/128" {
+			insecure = append(insecure, value)
+		}
+	}
+	for name, index := range info.RegistryConfig.IndexConfigs {
+		if index != nil && !index.Secure {
+			insecure = append(insecure, name)
+		}
+	}
+
+	if len(insecure) > 0 {
+		check.Status = models.PostureStatusFail
+		check.Details = fmt.Sprintf("реестры без TLS: %s", strings.Join(insecure, ", "))
+	}
+
+	return check
+}
+
+// checkDaemonTLS проверяет, что TCP-сокет Docker daemon защищен TLS с проверкой клиентов
+func checkDaemonTLS(daemon daemonConfig) models.PostureCheck {
+	check := models.PostureCheck{
+		ID:      "daemon_tls",
+		Title:   "TLS на сокете Docker daemon",
+		Status:  models.PostureStatusPass,
+		Details: "daemon доступен только через unix-сокет",
+	}
+
+	var tcpHosts []string
+	for _, host := range daemon.Hosts {
+		if strings.HasPrefix(host, "tcp://") {
+			tcpHosts = append(tcpHosts, host)
+		}
+	}
+	if len(tcpHosts) == 0 {
+		return check
+	}
+
+	switch {
+	case daemon.TLSVerify:
+		check.Details = fmt.Sprintf("TCP-сокет %s защищен TLS с проверкой клиентов", strings.Join(tcpHosts, ", "))
+	case daemon.TLS:
+		check.Status = models.PostureStatusWarn
+		check.Details = fmt.Sprintf("TCP-сокет %s использует TLS без проверки клиентских сертификатов (tlsverify)", strings.Join(tcpHosts, ", "))
+	default:
+		check.Status = models.PostureStatusFail
+		check.Details = fmt.Sprintf("TCP-сокет %s доступен без TLS", strings.Join(tcpHosts, ", "))
+	}
+
+	return check
+}
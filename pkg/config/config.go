@@ -1,10 +1,10 @@
 package config
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/aegis/aegis-cli/pkg/i18n"
 	"github.com/aegis/aegis-cli/pkg/models"
 	"github.com/spf13/viper"
 )
@@ -16,32 +16,67 @@ type CliConfig struct {
 	SQLitePath       string                    `mapstructure:"sqlite_path"`        // Для SQLite
 	DefaultAgentPort int                       `mapstructure:"default_agent_port"` // Порт по умолчанию для новых агентов
 	LogLevel         string                    `mapstructure:"log_level"`
-	LogFile          string                    `mapstructure:"log_file"`
+	LogFormat        string                    `mapstructure:"log_format"`      // text, json
+	LogFile          string                    `mapstructure:"log_file"`        // Путь к файлу или stderr
+	LogMaxSizeMB     int                       `mapstructure:"log_max_size_mb"` // Размер файла журнала для ротации, 0 - без ротации
+	LogMaxBackups    int                       `mapstructure:"log_max_backups"` // Количество ротированных файлов журнала
+	Language         string                    `mapstructure:"language"`        // ru, en; пусто - по LC_ALL, LC_MESSAGES или LANG
 	Notification     models.NotificationConfig `mapstructure:"notification"`
+	Tracing          TracingConfig             `mapstructure:"tracing"`
+	TrivyDBMaxAge    int                       `mapstructure:"trivy_db_max_age_hours"` // Порог в часах для предупреждения об устаревшей БД trivy агента, 0 - без проверки
+	AgentHooksToken  string                    `mapstructure:"agent_hooks_token"`      // Токен агентов (hooks_token) для aegis hook push
 	TelegramBotToken string                    `mapstructure:"telegram_bot_token"`
 	TelegramChatID   string                    `mapstructure:"telegram_chat_id"`
+	TelegramAPIURL   string                    `mapstructure:"telegram_api_url"`       // Адрес Bot API, по умолчанию https://api.telegram.org
+	TelegramAllowed  []int64                   `mapstructure:"telegram_allowed_chats"` // Чаты, которым отвечает aegis telegram serve
 }
 
 // AgentConfig представляет конфигурацию агента
 type AgentConfig struct {
-	Port             int           `mapstructure:"port"`
-	DockerSocketPath string        `mapstructure:"docker_socket_path"`
-	ScanConcurrency  int           `mapstructure:"scan_concurrency"`
-	LogLevel         string        `mapstructure:"log_level"`
-	LogFile          string        `mapstructure:"log_file"`
-	ResultsDir       string        `mapstructure:"results_dir"`
-	Hooks            []models.Hook `mapstructure:"hooks"`
+	Port                 int           `mapstructure:"port"`
+	Runtime              string        `mapstructure:"runtime"` // docker, podman, containerd
+	DockerSocketPath     string        `mapstructure:"docker_socket_path"`
+	PodmanSocketPath     string        `mapstructure:"podman_socket_path"` // Пусто - автоопределение
+	ContainerdSocketPath string        `mapstructure:"containerd_socket_path"`
+	ContainerdNamespace  string        `mapstructure:"containerd_namespace"`
+	ScanConcurrency      int           `mapstructure:"scan_concurrency"`
+	ScanTimeoutSeconds   int           `mapstructure:"scan_timeout_seconds"` // 0 - без ограничения
+	HookConcurrency      int           `mapstructure:"hook_concurrency"`     // Одновременно выполняемые скрипты хуков
+	LogLevel             string        `mapstructure:"log_level"`
+	LogFormat            string        `mapstructure:"log_format"`      // text, json, journald; пусто - journald под systemd при выводе в stderr
+	LogFile              string        `mapstructure:"log_file"`        // Путь к файлу или stderr
+	LogMaxSizeMB         int           `mapstructure:"log_max_size_mb"` // Размер файла журнала для ротации, 0 - без ротации
+	LogMaxBackups        int           `mapstructure:"log_max_backups"` // Количество ротированных файлов журнала
+	ResultsDir           string        `mapstructure:"results_dir"`
+	DatabasePath         string        `mapstructure:"database_path"` // SQLite-база истории выполнения хуков
+	Hooks                []models.Hook `mapstructure:"hooks"`
+	HooksToken           string        `mapstructure:"hooks_token"` // Токен POST и DELETE /hooks; пусто - управление хуками через API отключено
+	Tracing              TracingConfig `mapstructure:"tracing"`
 }
 
-// LoadCliConfig загружает конфигурацию CLI из файла
-func LoadCliConfig() (*CliConfig, error) {
+// TracingConfig содержит параметры трассировки OpenTelemetry, общие для CLI и агента
+type TracingConfig struct {
+	Exporter    string  `mapstructure:"exporter"`     // none, otlp, stdout, file
+	Endpoint    string  `mapstructure:"endpoint"`     // Адрес OTLP/HTTP: host:port или URL; пусто - OTEL_EXPORTER_OTLP_ENDPOINT
+	Insecure    bool    `mapstructure:"insecure"`     // OTLP без TLS
+	File        string  `mapstructure:"file"`         // Файл для экспортера file
+	SampleRatio float64 `mapstructure:"sample_ratio"` // Доля записываемых трассировок, начатых в этом процессе
+}
+
+// LoadCliConfig загружает конфигурацию CLI из файла path или, если он не указан,
+// из ~/.aegis/config.yaml, создавая его при отсутствии
+func LoadCliConfig(path string) (*CliConfig, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
+	if path != "" {
+		// Явно указанный файл должен существовать: конфигурация по умолчанию не создается
+		viper.SetConfigFile(path)
+	}
 
 	// Определение путей поиска конфигурации
 	userHome, err := os.UserHomeDir()
 	if err != nil {
-		return nil, fmt.Errorf("не удалось определить домашний каталог пользователя: %w", err)
+		return nil, i18n.Errorf("не удалось определить домашний каталог пользователя: %w", err)
 	}
 
 	aegisDir := filepath.Join(userHome, ".aegis")
@@ -57,14 +92,25 @@ func LoadCliConfig() (*CliConfig, error) {
 	viper.SetDefault("sqlite_path", filepath.Join(aegisDir, "aegis.db"))
 	viper.SetDefault("default_agent_port", 8080)
 	viper.SetDefault("log_level", "info")
+	viper.SetDefault("log_format", "text")
 	viper.SetDefault("log_file", filepath.Join(aegisDir, "aegis.log"))
+	viper.SetDefault("log_max_size_mb", 10)
+	viper.SetDefault("log_max_backups", 3)
+	viper.SetDefault("language", "") // Пусто - язык окружения; объявлен, чтобы действовала AEGIS_LANGUAGE
+	viper.SetDefault("telegram_api_url", "https://api.telegram.org")
+	viper.SetDefault("notification.templates_dir", filepath.Join(aegisDir, "templates"))
+	viper.SetDefault("tracing.exporter", "none")
+	viper.SetDefault("tracing.file", filepath.Join(aegisDir, "traces.json"))
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+	viper.SetDefault("trivy_db_max_age_hours", 48)
+	viper.SetDefault("agent_hooks_token", "") // Объявлен, чтобы действовала AEGIS_AGENT_HOOKS_TOKEN
 
 	// Загрузка конфигурации
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
 			// Создать конфигурацию по умолчанию, если файл не найден
 			if err := os.MkdirAll(aegisDir, 0755); err != nil {
-				return nil, fmt.Errorf("ошибка создания каталога конфигурации: %w", err)
+				return nil, i18n.Errorf("ошибка создания каталога конфигурации: %w", err)
 			}
 
 			// Создаем и используем конфигурацию по умолчанию
@@ -91,16 +137,16 @@ func LoadCliConfig() (*CliConfig, error) {
 
 			configPath := filepath.Join(aegisDir, "config.yaml")
 			if err := viper.WriteConfigAs(configPath); err != nil {
-				return nil, fmt.Errorf("ошибка создания файла конфигурации: %w", err)
+				return nil, i18n.Errorf("ошибка создания файла конфигурации: %w", err)
 			}
 		} else {
-			return nil, fmt.Errorf("ошибка чтения конфигурации: %w", err)
+			return nil, i18n.Errorf("ошибка чтения конфигурации: %w", err)
 		}
 	}
 
 	var config CliConfig
 	if err := viper.Unmarshal(&config); err != nil {
-		return nil, fmt.Errorf("ошибка декодирования конфигурации: %w", err)
+		return nil, i18n.Errorf("ошибка декодирования конфигурации: %w", err)
 	}
 
 	return &config, nil
@@ -121,11 +167,24 @@ func LoadAgentConfig() (*AgentConfig, error) {
 
 	// Установка значений по умолчанию
 	viper.SetDefault("port", 8080)
+	viper.SetDefault("runtime", "docker")
 	viper.SetDefault("docker_socket_path", "/var/run/docker.sock")
+	viper.SetDefault("containerd_socket_path", "/run/containerd/containerd.sock")
+	viper.SetDefault("containerd_namespace", "default")
 	viper.SetDefault("scan_concurrency", 2)
+	viper.SetDefault("scan_timeout_seconds", 1800)
+	viper.SetDefault("hook_concurrency", 4)
 	viper.SetDefault("log_level", "info")
+	viper.SetDefault("log_format", "")
 	viper.SetDefault("log_file", "/var/log/aegis-agent/agent.log")
+	viper.SetDefault("log_max_size_mb", 50)
+	viper.SetDefault("log_max_backups", 5)
 	viper.SetDefault("results_dir", "/var/lib/aegis-agent/results")
+	viper.SetDefault("database_path", "/var/lib/aegis-agent/agent.db")
+	viper.SetDefault("hooks_token", "") // Объявлен, чтобы действовала AEGIS_AGENT_HOOKS_TOKEN
+	viper.SetDefault("tracing.exporter", "none")
+	viper.SetDefault("tracing.file", "/var/log/aegis-agent/traces.json")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
 
 	// Загрузка конфигурации
 	if err := viper.ReadInConfig(); err != nil {
@@ -137,41 +196,53 @@ func LoadAgentConfig() (*AgentConfig, error) {
 
 			for _, dir := range []string{agentConfigDir, resultsDir, logDir} {
 				if err := os.MkdirAll(dir, 0755); err != nil {
-					return nil, fmt.Errorf("ошибка создания каталога %s: %w", dir, err)
+					return nil, i18n.Errorf("ошибка создания каталога %s: %w", dir, err)
 				}
 			}
 
 			// Создаем и используем конфигурацию по умолчанию
 			defaultConfig := &AgentConfig{
-				Port:             8080,
-				DockerSocketPath: "/var/run/docker.sock",
-				ScanConcurrency:  2,
-				LogLevel:         "info",
-				LogFile:          "/var/log/aegis-agent/agent.log",
-				ResultsDir:       "/var/lib/aegis-agent/results",
-				Hooks:            []models.Hook{},
+				Port:                 8080,
+				Runtime:              "docker",
+				DockerSocketPath:     "/var/run/docker.sock",
+				ContainerdSocketPath: "/run/containerd/containerd.sock",
+				ContainerdNamespace:  "default",
+				ScanConcurrency:      2,
+				ScanTimeoutSeconds:   1800,
+				HookConcurrency:      4,
+				LogLevel:             "info",
+				LogFile:              "/var/log/aegis-agent/agent.log",
+				ResultsDir:           "/var/lib/aegis-agent/results",
+				DatabasePath:         "/var/lib/aegis-agent/agent.db",
+				Hooks:                []models.Hook{},
 			}
 
 			// Устанавливаем значения Viper из defaultConfig
 			viper.Set("port", defaultConfig.Port)
+			viper.Set("runtime", defaultConfig.Runtime)
 			viper.Set("docker_socket_path", defaultConfig.DockerSocketPath)
+			viper.Set("containerd_socket_path", defaultConfig.ContainerdSocketPath)
+			viper.Set("containerd_namespace", defaultConfig.ContainerdNamespace)
 			viper.Set("scan_concurrency", defaultConfig.ScanConcurrency)
+			viper.Set("scan_timeout_seconds", defaultConfig.ScanTimeoutSeconds)
+			viper.Set("hook_concurrency", defaultConfig.HookConcurrency)
 			viper.Set("log_level", defaultConfig.LogLevel)
 			viper.Set("log_file", defaultConfig.LogFile)
 			viper.Set("results_dir", defaultConfig.ResultsDir)
+			viper.Set("database_path", defaultConfig.DatabasePath)
 
 			configPath := filepath.Join(agentConfigDir, "config.yaml")
 			if err := viper.WriteConfigAs(configPath); err != nil {
-				return nil, fmt.Errorf("ошибка создания файла конфигурации: %w", err)
+				return nil, i18n.Errorf("ошибка создания файла конфигурации: %w", err)
 			}
 		} else {
-			return nil, fmt.Errorf("ошибка чтения конфигурации: %w", err)
+			return nil, i18n.Errorf("ошибка чтения конфигурации: %w", err)
 		}
 	}
 
 	var config AgentConfig
 	if err := viper.Unmarshal(&config); err != nil {
-		return nil, fmt.Errorf("ошибка декодирования конфигурации: %w", err)
+		return nil, i18n.Errorf("ошибка декодирования конфигурации: %w", err)
 	}
 
 	return &config, nil
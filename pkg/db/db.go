@@ -2,11 +2,13 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/aegis/aegis-cli/pkg/config"
+	"github.com/aegis/aegis-cli/pkg/i18n"
 	"github.com/aegis/aegis-cli/pkg/models"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
@@ -18,7 +20,7 @@ import (
 type Store struct {
 	db     *sqlx.DB
 	logger *logrus.Logger
-	config *config.CliConfig
+	dbType string // Тип базы данных: sqlite или postgresql
 }
 
 // NewStore создает новое подключение к базе данных
@@ -29,34 +31,55 @@ func NewStore(cfg *config.CliConfig, logger *logrus.Logger) (*Store, error) {
 	if cfg.DatabaseType == "postgresql" {
 		db, err = sqlx.Connect("postgres", cfg.DatabaseURL)
 		if err != nil {
-			return nil, fmt.Errorf("ошибка подключения к PostgreSQL: %w", err)
+			return nil, i18n.Errorf("ошибка подключения к PostgreSQL: %w", err)
 		}
 	} else if cfg.DatabaseType == "sqlite" {
 		db, err = sqlx.Connect("sqlite3", cfg.SQLitePath)
 		if err != nil {
-			return nil, fmt.Errorf("ошибка подключения к SQLite: %w", err)
+			return nil, i18n.Errorf("ошибка подключения к SQLite: %w", err)
 		}
 	} else {
-		return nil, fmt.Errorf("неподдерживаемый тип базы данных: %s", cfg.DatabaseType)
+		return nil, i18n.Errorf("неподдерживаемый тип базы данных: %s", cfg.DatabaseType)
 	}
 
-	// Настройка соединения
+	store := newStore(db, cfg.DatabaseType, logger)
+
+	// Инициализация схемы базы данных
+	if err := store.initSchema(); err != nil {
+		return nil, i18n.Errorf("ошибка инициализации схемы: %w", err)
+	}
+
+	return store, nil
+}
+
+// NewAgentStore открывает SQLite-базу агента по пути path. В ней создаются только таблицы
+// хуков, добавленных через API, истории их выполнения и недоставленных событий webhook
+func NewAgentStore(path string, logger *logrus.Logger) (*Store, error) {
+	db, err := sqlx.Connect("sqlite3", path)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка подключения к SQLite: %w", err)
+	}
+
+	store := newStore(db, "sqlite", logger)
+	if err := store.initHookSchema(); err != nil {
+		db.Close()
+		return nil, i18n.Errorf("ошибка инициализации схемы: %w", err)
+	}
+
+	return store, nil
+}
+
+// newStore настраивает пул соединений и создает хранилище
+func newStore(db *sqlx.DB, dbType string, logger *logrus.Logger) *Store {
 	db.SetMaxOpenConns(25)
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(5 * time.Minute)
 
-	store := &Store{
+	return &Store{
 		db:     db,
 		logger: logger,
-		config: cfg,
-	}
-
-	// Инициализация схемы базы данных
-	if err := store.initSchema(); err != nil {
-		return nil, fmt.Errorf("ошибка инициализации схемы: %w", err)
+		dbType: dbType,
 	}
-
-	return store, nil
 }
 
 // initSchema инициализирует схему базы данных
@@ -71,11 +94,21 @@ func (s *Store) initSchema() error {
         status TEXT NOT NULL,
         last_seen TIMESTAMP,
         created_at TIMESTAMP NOT NULL,
-        description TEXT
+        description TEXT,
+        posture TEXT NOT NULL DEFAULT '',
+        agent_info TEXT NOT NULL DEFAULT ''
     )
     `)
 	if err != nil {
-		return fmt.Errorf("ошибка создания таблицы hosts: %w", err)
+		return i18n.Errorf("ошибка создания таблицы hosts: %w", err)
+	}
+
+	// Колонки, добавленные после первой версии схемы
+	if err := s.ensureColumn("hosts", "posture", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.ensureColumn("hosts", "agent_info", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
 	}
 
 	// Таблица контейнеров
@@ -88,11 +121,23 @@ func (s *Store) initSchema() error {
         status TEXT NOT NULL,
         created_at TIMESTAMP NOT NULL,
         updated_at TIMESTAMP NOT NULL,
+        runtime TEXT NOT NULL DEFAULT '',
+        pod_name TEXT NOT NULL DEFAULT '',
+        pod_namespace TEXT NOT NULL DEFAULT '',
+        workload_kind TEXT NOT NULL DEFAULT '',
+        workload_name TEXT NOT NULL DEFAULT '',
         FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE
     )
     `)
 	if err != nil {
-		return fmt.Errorf("ошибка создания таблицы containers: %w", err)
+		return i18n.Errorf("ошибка создания таблицы containers: %w", err)
+	}
+
+	// Колонки, добавленные после первой версии схемы
+	for _, column := range []string{"runtime", "pod_name", "pod_namespace", "workload_kind", "workload_name"} {
+		if err := s.ensureColumn("containers", column, "TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
 	}
 
 	// Таблица сканирований
@@ -106,12 +151,18 @@ func (s *Store) initSchema() error {
         finished_at TIMESTAMP,
         result_path TEXT,
         error_msg TEXT,
+        image_digest TEXT NOT NULL DEFAULT '',
         FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE,
         FOREIGN KEY (container_id) REFERENCES containers(id) ON DELETE CASCADE
     )
     `)
 	if err != nil {
-		return fmt.Errorf("ошибка создания таблицы scans: %w", err)
+		return i18n.Errorf("ошибка создания таблицы scans: %w", err)
+	}
+
+	// Колонки, добавленные после первой версии схемы
+	if err := s.ensureColumn("scans", "image_digest", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
 	}
 
 	// Таблица уязвимостей
@@ -136,43 +187,171 @@ func (s *Store) initSchema() error {
     )
     `)
 	if err != nil {
-		return fmt.Errorf("ошибка создания таблицы vulnerabilities: %w", err)
+		return i18n.Errorf("ошибка создания таблицы vulnerabilities: %w", err)
 	}
 
-	// Таблица хуков
+	// Таблица находок (секреты и ошибки конфигурации)
 	_, err = s.db.Exec(`
-    CREATE TABLE IF NOT EXISTS hooks (
+    CREATE TABLE IF NOT EXISTS findings (
+        id TEXT PRIMARY KEY,
+        scan_id TEXT NOT NULL,
+        container_id TEXT NOT NULL,
+        host_id TEXT NOT NULL,
+        kind TEXT NOT NULL,
+        rule_id TEXT NOT NULL,
+        severity TEXT NOT NULL,
+        title TEXT NOT NULL,
+        description TEXT NOT NULL DEFAULT '',
+        target TEXT NOT NULL DEFAULT '',
+        location TEXT NOT NULL DEFAULT '',
+        start_line INTEGER NOT NULL DEFAULT 0,
+        end_line INTEGER NOT NULL DEFAULT 0,
+        resolution TEXT NOT NULL DEFAULT '',
+        discovered_at TIMESTAMP NOT NULL,
+        FOREIGN KEY (scan_id) REFERENCES scans(id) ON DELETE CASCADE,
+        FOREIGN KEY (container_id) REFERENCES containers(id) ON DELETE CASCADE,
+        FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE
+    )
+    `)
+	if err != nil {
+		return i18n.Errorf("ошибка создания таблицы findings: %w", err)
+	}
+	for _, column := range []string{"start_line", "end_line"} {
+		if err := s.ensureColumn("findings", column, "INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return err
+		}
+	}
+
+	for _, index := range []string{
+		`CREATE INDEX IF NOT EXISTS idx_findings_scan_id ON findings(scan_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_findings_kind ON findings(kind)`,
+	} {
+		if _, err := s.db.Exec(index); err != nil {
+			return i18n.Errorf("ошибка создания индекса findings: %w", err)
+		}
+	}
+
+	// Таблица результатов аудита конфигурации контейнеров
+	_, err = s.db.Exec(`
+    CREATE TABLE IF NOT EXISTS audit_findings (
+        id TEXT PRIMARY KEY,
+        host_id TEXT NOT NULL,
+        container_id TEXT NOT NULL,
+        container_name TEXT NOT NULL,
+        rule_id TEXT NOT NULL,
+        severity TEXT NOT NULL,
+        title TEXT NOT NULL,
+        details TEXT NOT NULL DEFAULT '',
+        remediation TEXT NOT NULL DEFAULT '',
+        audited_at TIMESTAMP NOT NULL,
+        FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE
+    )
+    `)
+	if err != nil {
+		return i18n.Errorf("ошибка создания таблицы audit_findings: %w", err)
+	}
+
+	if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_audit_findings_host_id ON audit_findings(host_id)`); err != nil {
+		return i18n.Errorf("ошибка создания индекса audit_findings: %w", err)
+	}
+
+	// Таблица SBOM (по одному документу на образ и формат)
+	_, err = s.db.Exec(`
+    CREATE TABLE IF NOT EXISTS sboms (
+        id TEXT PRIMARY KEY,
+        image_digest TEXT NOT NULL,
+        image TEXT NOT NULL,
+        format TEXT NOT NULL,
+        scan_id TEXT NOT NULL,
+        host_id TEXT NOT NULL,
+        container_id TEXT NOT NULL,
+        content TEXT NOT NULL,
+        created_at TIMESTAMP NOT NULL,
+        UNIQUE (image_digest, format)
+    )
+    `)
+	if err != nil {
+		return i18n.Errorf("ошибка создания таблицы sboms: %w", err)
+	}
+
+	// Таблица пакетов из SBOM для поиска по составу образов
+	_, err = s.db.Exec(`
+    CREATE TABLE IF NOT EXISTS sbom_packages (
         id TEXT PRIMARY KEY,
+        image_digest TEXT NOT NULL,
         name TEXT NOT NULL,
+        version TEXT NOT NULL,
+        type TEXT NOT NULL,
+        purl TEXT NOT NULL
+    )
+    `)
+	if err != nil {
+		return i18n.Errorf("ошибка создания таблицы sbom_packages: %w", err)
+	}
+
+	for _, index := range []string{
+		`CREATE INDEX IF NOT EXISTS idx_sbom_packages_name ON sbom_packages(name)`,
+		`CREATE INDEX IF NOT EXISTS idx_sbom_packages_image_digest ON sbom_packages(image_digest)`,
+	} {
+		if _, err := s.db.Exec(index); err != nil {
+			return i18n.Errorf("ошибка создания индекса sbom_packages: %w", err)
+		}
+	}
+
+	// Таблицы хуков
+	if err := s.initHookSchema(); err != nil {
+		return err
+	}
+
+	// Очередь уведомлений, ожидающих отправки сводкой
+	_, err = s.db.Exec(`
+    CREATE TABLE IF NOT EXISTS notification_queue (
+        id TEXT PRIMARY KEY,
+        channel TEXT NOT NULL,
         event TEXT NOT NULL,
-        script_path TEXT NOT NULL,
-        timeout_seconds INTEGER NOT NULL,
-        enabled BOOLEAN NOT NULL,
-        created_at TIMESTAMP NOT NULL,
-        updated_at TIMESTAMP NOT NULL
+        fingerprint TEXT NOT NULL,
+        payload TEXT NOT NULL,
+        created_at TIMESTAMP NOT NULL
     )
     `)
 	if err != nil {
-		return fmt.Errorf("ошибка создания таблицы hooks: %w", err)
+		return i18n.Errorf("ошибка создания таблицы notification_queue: %w", err)
 	}
 
-	// Таблица выполнений хуков
+	// Отправленные сообщения каналов для ограничения частоты и расписания дайджестов
 	_, err = s.db.Exec(`
-    CREATE TABLE IF NOT EXISTS hook_executions (
+    CREATE TABLE IF NOT EXISTS notification_deliveries (
         id TEXT PRIMARY KEY,
-        hook_id TEXT NOT NULL,
-        scan_id TEXT NOT NULL,
-        status TEXT NOT NULL,
-        output TEXT,
-        error_msg TEXT,
-        started_at TIMESTAMP NOT NULL,
-        finished_at TIMESTAMP NOT NULL,
-        FOREIGN KEY (hook_id) REFERENCES hooks(id) ON DELETE CASCADE,
-        FOREIGN KEY (scan_id) REFERENCES scans(id) ON DELETE CASCADE
+        channel TEXT NOT NULL,
+        event TEXT NOT NULL,
+        events INTEGER NOT NULL,
+        sent_at TIMESTAMP NOT NULL
     )
     `)
 	if err != nil {
-		return fmt.Errorf("ошибка создания таблицы hook_executions: %w", err)
+		return i18n.Errorf("ошибка создания таблицы notification_deliveries: %w", err)
+	}
+
+	// Отпечатки отправленных находок для подавления повторов
+	_, err = s.db.Exec(`
+    CREATE TABLE IF NOT EXISTS notification_fingerprints (
+        channel TEXT NOT NULL,
+        fingerprint TEXT NOT NULL,
+        sent_at TIMESTAMP NOT NULL,
+        PRIMARY KEY (channel, fingerprint)
+    )
+    `)
+	if err != nil {
+		return i18n.Errorf("ошибка создания таблицы notification_fingerprints: %w", err)
+	}
+
+	for _, index := range []string{
+		`CREATE INDEX IF NOT EXISTS idx_notification_queue_channel ON notification_queue(channel)`,
+		`CREATE INDEX IF NOT EXISTS idx_notification_deliveries_sent_at ON notification_deliveries(sent_at)`,
+	} {
+		if _, err := s.db.Exec(index); err != nil {
+			return i18n.Errorf("ошибка создания индекса уведомлений: %w", err)
+		}
 	}
 
 	// Таблица стратегий восстановления
@@ -188,7 +367,7 @@ func (s *Store) initSchema() error {
     )
     `)
 	if err != nil {
-		return fmt.Errorf("ошибка создания таблицы remediation_strategies: %w", err)
+		return i18n.Errorf("ошибка создания таблицы remediation_strategies: %w", err)
 	}
 
 	// Добавление начальных стратегий восстановления
@@ -197,15 +376,211 @@ func (s *Store) initSchema() error {
     VALUES 
         ('strategy-1', 'Горячее обновление', 'hot-patch', 'Нет простоя', 'apt-get update && apt-get upgrade -y {{package}}', 'Обновление пакета без перезапуска контейнера', CURRENT_TIMESTAMP),
         ('strategy-2', 'Перезапуск', 'restart', '10-30 секунд', 'docker restart {{container_id}}', 'Перезапуск контейнера после обновления образа', CURRENT_TIMESTAMP),
-        ('strategy-3', 'Постепенное обновление', 'rolling-update', '1-5 минут на узел', 'kubectl rollout restart deployment/{{deployment_name}}', 'Постепенное обновление контейнеров в Kubernetes', CURRENT_TIMESTAMP)
+        ('strategy-3', 'Постепенное обновление', 'rolling-update', '1-5 минут на узел', 'kubectl rollout restart deployment/{{deployment_name}} -n {{namespace}}', 'Постепенное обновление контейнеров в Kubernetes', CURRENT_TIMESTAMP)
     `)
 	if err != nil {
-		return fmt.Errorf("ошибка создания начальных стратегий восстановления: %w", err)
+		return i18n.Errorf("ошибка создания начальных стратегий восстановления: %w", err)
+	}
+
+	// В ранних версиях команда постепенного обновления не учитывала namespace
+	_, err = s.db.Exec(
+		"UPDATE remediation_strategies SET command = $1 WHERE id = $2 AND command = $3",
+		"kubectl rollout restart deployment/{{deployment_name}} -n {{namespace}}",
+		"strategy-3",
+		"kubectl rollout restart deployment/{{deployment_name}}",
+	)
+	if err != nil {
+		return i18n.Errorf("ошибка обновления стратегий восстановления: %w", err)
 	}
 
 	return nil
 }
 
+// hookExecutionsColumns - колонки таблицы hook_executions. Внешних ключей нет: история хранит
+// и выполнения хуков из конфигурации агента, которых нет в таблице hooks, и выполнения
+// на агенте для сканирований, не запущенных из этого CLI
+const hookExecutionsColumns = `
+        id TEXT PRIMARY KEY,
+        hook_id TEXT NOT NULL,
+        scan_id TEXT NOT NULL,
+        status TEXT NOT NULL,
+        output TEXT,
+        error_msg TEXT,
+        started_at TIMESTAMP NOT NULL,
+        finished_at TIMESTAMP NOT NULL,
+        hook_name TEXT NOT NULL DEFAULT '',
+        event TEXT NOT NULL DEFAULT '',
+        host_id TEXT NOT NULL DEFAULT '',
+        exit_code INTEGER NOT NULL DEFAULT 0,
+        duration_ms INTEGER NOT NULL DEFAULT 0
+    `
+
+// initHookSchema создает таблицы хуков, истории их выполнения и недоставленных событий webhook.
+// Используется и базой CLI, и базой агента
+func (s *Store) initHookSchema() error {
+	// Таблица хуков
+	_, err := s.db.Exec(`
+    CREATE TABLE IF NOT EXISTS hooks (
+        id TEXT PRIMARY KEY,
+        name TEXT NOT NULL,
+        event TEXT NOT NULL,
+        script_path TEXT NOT NULL,
+        timeout_seconds INTEGER NOT NULL,
+        enabled BOOLEAN NOT NULL,
+        created_at TIMESTAMP NOT NULL,
+        updated_at TIMESTAMP NOT NULL,
+        payload TEXT NOT NULL DEFAULT '',
+        type TEXT NOT NULL DEFAULT '',
+        url TEXT NOT NULL DEFAULT '',
+        headers TEXT NOT NULL DEFAULT '',
+        payload_template TEXT NOT NULL DEFAULT '',
+        secret TEXT NOT NULL DEFAULT '',
+        host_filter TEXT NOT NULL DEFAULT '',
+        image_pattern TEXT NOT NULL DEFAULT '',
+        min_severity TEXT NOT NULL DEFAULT '',
+        threshold INTEGER NOT NULL DEFAULT 0,
+        run_as_user TEXT NOT NULL DEFAULT '',
+        run_as_group TEXT NOT NULL DEFAULT '',
+        work_dir TEXT NOT NULL DEFAULT '',
+        env_allowlist TEXT NOT NULL DEFAULT '',
+        max_output_bytes INTEGER NOT NULL DEFAULT 0,
+        cpu_limit_seconds INTEGER NOT NULL DEFAULT 0,
+        memory_limit_mb INTEGER NOT NULL DEFAULT 0
+    )
+    `)
+	if err != nil {
+		return i18n.Errorf("ошибка создания таблицы hooks: %w", err)
+	}
+
+	// Колонки, добавленные после первой версии схемы
+	for _, column := range []string{"payload", "type", "url", "headers", "payload_template", "secret",
+		"host_filter", "image_pattern", "min_severity", "run_as_user", "run_as_group", "work_dir", "env_allowlist"} {
+		if err := s.ensureColumn("hooks", column, "TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+	for _, column := range []string{"threshold", "max_output_bytes", "cpu_limit_seconds", "memory_limit_mb"} {
+		if err := s.ensureColumn("hooks", column, "INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return err
+		}
+	}
+
+	// Таблица выполнений хуков
+	_, err = s.db.Exec("CREATE TABLE IF NOT EXISTS hook_executions (" + hookExecutionsColumns + ")")
+	if err != nil {
+		return i18n.Errorf("ошибка создания таблицы hook_executions: %w", err)
+	}
+
+	// Колонки, добавленные после первой версии схемы
+	for _, column := range []string{"hook_name", "event", "host_id"} {
+		if err := s.ensureColumn("hook_executions", column, "TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+	for _, column := range []string{"exit_code", "duration_ms"} {
+		if err := s.ensureColumn("hook_executions", column, "INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return err
+		}
+	}
+
+	if err := s.dropHookExecutionForeignKeys(); err != nil {
+		return i18n.Errorf("ошибка обновления таблицы hook_executions: %w", err)
+	}
+
+	_, err = s.db.Exec("CREATE INDEX IF NOT EXISTS idx_hook_executions_started_at ON hook_executions(started_at)")
+	if err != nil {
+		return i18n.Errorf("ошибка создания индекса hook_executions: %w", err)
+	}
+
+	// Таблица недоставленных событий webhook
+	_, err = s.db.Exec(`
+    CREATE TABLE IF NOT EXISTS hook_dead_letters (
+        id TEXT PRIMARY KEY,
+        hook_id TEXT NOT NULL,
+        hook_name TEXT NOT NULL,
+        event TEXT NOT NULL,
+        scan_id TEXT NOT NULL,
+        url TEXT NOT NULL,
+        payload TEXT NOT NULL,
+        attempts INTEGER NOT NULL,
+        status_code INTEGER NOT NULL,
+        last_error TEXT NOT NULL,
+        created_at TIMESTAMP NOT NULL
+    )
+    `)
+	if err != nil {
+		return i18n.Errorf("ошибка создания таблицы hook_dead_letters: %w", err)
+	}
+
+	return nil
+}
+
+// dropHookExecutionForeignKeys удаляет внешние ключи hook_executions(hook_id, scan_id)
+// из баз, созданных первой версией схемы
+func (s *Store) dropHookExecutionForeignKeys() error {
+	if s.dbType == "postgresql" {
+		_, err := s.db.Exec(`
+        ALTER TABLE hook_executions DROP CONSTRAINT IF EXISTS hook_executions_hook_id_fkey;
+        ALTER TABLE hook_executions DROP CONSTRAINT IF EXISTS hook_executions_scan_id_fkey
+        `)
+		return err
+	}
+
+	// SQLite не удаляет ограничения через ALTER TABLE: таблица пересоздается с копированием данных
+	var count int
+	if err := s.db.Get(&count, "SELECT COUNT(*) FROM pragma_foreign_key_list('hook_executions')"); err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	columns := "id, hook_id, scan_id, status, output, error_msg, started_at, finished_at, " +
+		"hook_name, event, host_id, exit_code, duration_ms"
+	for _, query := range []string{
+		"CREATE TABLE hook_executions_new (" + hookExecutionsColumns + ")",
+		"INSERT INTO hook_executions_new (" + columns + ") SELECT " + columns + " FROM hook_executions",
+		"DROP TABLE hook_executions",
+		"ALTER TABLE hook_executions_new RENAME TO hook_executions",
+	} {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ensureColumn добавляет колонку в существующую таблицу, если её ещё нет.
+// Нужна для баз, созданных предыдущими версиями схемы
+func (s *Store) ensureColumn(table, column, definition string) error {
+	if s.dbType == "postgresql" {
+		_, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", table, column, definition))
+		if err != nil {
+			return i18n.Errorf("ошибка добавления колонки %s.%s: %w", table, column, err)
+		}
+		return nil
+	}
+
+	var count int
+	if err := s.db.Get(&count, "SELECT COUNT(*) FROM pragma_table_info($1) WHERE name = $2", table, column); err != nil {
+		return i18n.Errorf("ошибка чтения структуры таблицы %s: %w", table, err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if _, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return i18n.Errorf("ошибка добавления колонки %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
 // Close закрывает соединение с базой данных
 func (s *Store) Close() error {
 	return s.db.Close()
@@ -228,7 +603,7 @@ func (s *Store) GetHost(id string) (*models.Host, error) {
 	err := s.db.Get(&host, "SELECT * FROM hosts WHERE id = $1", id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("хост не найден: %s", id)
+			return nil, i18n.Errorf("хост не найден: %s", id)
 		}
 		return nil, err
 	}
@@ -253,6 +628,28 @@ func (s *Store) UpdateHost(host *models.Host) error {
 	return err
 }
 
+// UpdateHostPosture сохраняет последний снимок состояния безопасности хоста
+func (s *Store) UpdateHostPosture(id string, posture *models.HostPosture) error {
+	data, err := json.Marshal(posture)
+	if err != nil {
+		return i18n.Errorf("ошибка сериализации состояния хоста: %w", err)
+	}
+
+	_, err = s.db.Exec("UPDATE hosts SET posture = $1 WHERE id = $2", string(data), id)
+	return err
+}
+
+// UpdateHostAgentInfo сохраняет последние сведения об агенте хоста
+func (s *Store) UpdateHostAgentInfo(id string, info *models.AgentInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return i18n.Errorf("ошибка сериализации сведений об агенте: %w", err)
+	}
+
+	_, err = s.db.Exec("UPDATE hosts SET agent_info = $1 WHERE id = $2", string(data), id)
+	return err
+}
+
 // DeleteHost удаляет хост
 func (s *Store) DeleteHost(id string) error {
 	_, err := s.db.Exec("DELETE FROM hosts WHERE id = $1", id)
@@ -264,8 +661,10 @@ func (s *Store) DeleteHost(id string) error {
 // AddContainer добавляет новый контейнер
 func (s *Store) AddContainer(container *models.Container) error {
 	_, err := s.db.NamedExec(`
-    INSERT INTO containers (id, host_id, name, image, status, created_at, updated_at)
-    VALUES (:id, :host_id, :name, :image, :status, :created_at, :updated_at)
+    INSERT INTO containers (id, host_id, name, image, status, runtime,
+        pod_name, pod_namespace, workload_kind, workload_name, created_at, updated_at)
+    VALUES (:id, :host_id, :name, :image, :status, :runtime,
+        :pod_name, :pod_namespace, :workload_kind, :workload_name, :created_at, :updated_at)
     `, container)
 	return err
 }
@@ -291,7 +690,7 @@ func (s *Store) GetContainer(id string) (*models.Container, error) {
 
 		// Проверяем, найден ли ровно один контейнер
 		if len(containers) == 0 {
-			return nil, fmt.Errorf("контейнер не найден: %s", id)
+			return nil, i18n.Errorf("контейнер не найден: %s", id)
 		}
 		if len(containers) > 1 {
 			// Возвращаем ошибку с перечислением найденных ID
@@ -304,7 +703,7 @@ func (s *Store) GetContainer(id string) (*models.Container, error) {
 				}
 				foundIDs = append(foundIDs, fmt.Sprintf("%s (%s)", shortID, c.Name))
 			}
-			return nil, fmt.Errorf("найдено несколько контейнеров с ID, начинающимся с %s: %s",
+			return nil, i18n.Errorf("найдено несколько контейнеров с ID, начинающимся с %s: %s",
 				id, strings.Join(foundIDs, ", "))
 		}
 
@@ -314,7 +713,7 @@ func (s *Store) GetContainer(id string) (*models.Container, error) {
 
 	// Возвращаем исходную ошибку, если поиск не удался
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("контейнер не найден: %s", id)
+		return nil, i18n.Errorf("контейнер не найден: %s", id)
 	}
 	return nil, err
 }
@@ -330,7 +729,9 @@ func (s *Store) ListContainers(hostID string) ([]models.Container, error) {
 func (s *Store) UpdateContainer(container *models.Container) error {
 	_, err := s.db.NamedExec(`
     UPDATE containers 
-    SET name = :name, image = :image, status = :status, updated_at = :updated_at 
+    SET name = :name, image = :image, status = :status, runtime = :runtime,
+        pod_name = :pod_name, pod_namespace = :pod_namespace,
+        workload_kind = :workload_kind, workload_name = :workload_name, updated_at = :updated_at
     WHERE id = :id
     `, container)
 	return err
@@ -347,8 +748,8 @@ func (s *Store) DeleteContainer(id string) error {
 // AddScan добавляет новое сканирование
 func (s *Store) AddScan(scan *models.Scan) error {
 	_, err := s.db.NamedExec(`
-    INSERT INTO scans (id, host_id, container_id, status, started_at, finished_at, result_path, error_msg)
-    VALUES (:id, :host_id, :container_id, :status, :started_at, :finished_at, :result_path, :error_msg)
+    INSERT INTO scans (id, host_id, container_id, status, started_at, finished_at, result_path, error_msg, image_digest)
+    VALUES (:id, :host_id, :container_id, :status, :started_at, :finished_at, :result_path, :error_msg, :image_digest)
     `, scan)
 	return err
 }
@@ -359,7 +760,7 @@ func (s *Store) GetScan(id string) (*models.Scan, error) {
 	err := s.db.Get(&scan, "SELECT * FROM scans WHERE id = $1", id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("сканирование не найдено: %s", id)
+			return nil, i18n.Errorf("сканирование не найдено: %s", id)
 		}
 		return nil, err
 	}
@@ -393,7 +794,8 @@ func (s *Store) ListScans(hostID, containerID string) ([]models.Scan, error) {
 func (s *Store) UpdateScan(scan *models.Scan) error {
 	_, err := s.db.NamedExec(`
     UPDATE scans 
-    SET status = :status, finished_at = :finished_at, result_path = :result_path, error_msg = :error_msg
+    SET status = :status, finished_at = :finished_at, result_path = :result_path, error_msg = :error_msg,
+        image_digest = :image_digest
     WHERE id = :id
     `, scan)
 	return err
@@ -427,7 +829,7 @@ func (s *Store) GetVulnerability(id string) (*models.Vulnerability, error) {
 	err := s.db.Get(&vulnerability, "SELECT * FROM vulnerabilities WHERE id = $1", id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("уязвимость не найдена: %s", id)
+			return nil, i18n.Errorf("уязвимость не найдена: %s", id)
 		}
 		return nil, err
 	}
@@ -467,7 +869,7 @@ func (s *Store) ListVulnerabilities(hostID, containerID, scanID string, severity
 	query += " ORDER BY discovered_at DESC"
 
 	// Заменяем ? на $1, $2 и т.д. для PostgreSQL
-	if s.config.DatabaseType == "postgresql" {
+	if s.dbType == "postgresql" {
 		for i := 1; i <= len(args); i++ {
 			query = sqlx.Rebind(sqlx.DOLLAR, query)
 		}
@@ -484,13 +886,261 @@ func (s *Store) DeleteVulnerability(id string) error {
 	return err
 }
 
+// Findings
+
+// AddFinding добавляет находку сканирования (секрет или ошибку конфигурации)
+func (s *Store) AddFinding(finding *models.Finding) error {
+	_, err := s.db.NamedExec(`
+    INSERT INTO findings (
+        id, scan_id, container_id, host_id, kind, rule_id, severity, title,
+        description, target, location, start_line, end_line, resolution, discovered_at
+    ) VALUES (
+        :id, :scan_id, :container_id, :host_id, :kind, :rule_id, :severity, :title,
+        :description, :target, :location, :start_line, :end_line, :resolution, :discovered_at
+    )
+    `, finding)
+	return err
+}
+
+// ListFindings возвращает список находок указанного вида
+func (s *Store) ListFindings(kind, hostID, containerID, scanID, severity string) ([]models.Finding, error) {
+	var args []interface{}
+	var conditions []string
+
+	if kind != "" {
+		conditions = append(conditions, "kind = ?")
+		args = append(args, kind)
+	}
+	if hostID != "" {
+		conditions = append(conditions, "host_id = ?")
+		args = append(args, hostID)
+	}
+	if containerID != "" {
+		conditions = append(conditions, "container_id = ?")
+		args = append(args, containerID)
+	}
+	if scanID != "" {
+		conditions = append(conditions, "scan_id = ?")
+		args = append(args, scanID)
+	}
+	if severity != "" {
+		conditions = append(conditions, "severity = ?")
+		args = append(args, severity)
+	}
+
+	query := "SELECT * FROM findings"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY discovered_at DESC"
+
+	// Заменяем ? на $1, $2 и т.д. для PostgreSQL
+	if s.dbType == "postgresql" {
+		query = sqlx.Rebind(sqlx.DOLLAR, query)
+	}
+
+	var findings []models.Finding
+	err := s.db.Select(&findings, query, args...)
+	return findings, err
+}
+
+// Audit
+
+// SaveAuditFindings заменяет результаты предыдущего аудита хоста (или одного контейнера) новыми
+func (s *Store) SaveAuditFindings(hostID, containerID string, findings []models.AuditFinding) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return i18n.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	if containerID != "" {
+		_, err = tx.Exec("DELETE FROM audit_findings WHERE host_id = $1 AND container_id = $2", hostID, containerID)
+	} else {
+		_, err = tx.Exec("DELETE FROM audit_findings WHERE host_id = $1", hostID)
+	}
+	if err != nil {
+		return i18n.Errorf("ошибка удаления результатов предыдущего аудита: %w", err)
+	}
+
+	for i := range findings {
+		_, err := tx.NamedExec(`
+        INSERT INTO audit_findings (
+            id, host_id, container_id, container_name, rule_id, severity, title,
+            details, remediation, audited_at
+        ) VALUES (
+            :id, :host_id, :container_id, :container_name, :rule_id, :severity, :title,
+            :details, :remediation, :audited_at
+        )
+        `, &findings[i])
+		if err != nil {
+			return i18n.Errorf("ошибка сохранения результата аудита: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListAuditFindings возвращает результаты аудита конфигурации контейнеров
+func (s *Store) ListAuditFindings(hostID, containerID, severity string) ([]models.AuditFinding, error) {
+	var args []interface{}
+	var conditions []string
+
+	if hostID != "" {
+		conditions = append(conditions, "host_id = ?")
+		args = append(args, hostID)
+	}
+	if containerID != "" {
+		conditions = append(conditions, "container_id = ?")
+		args = append(args, containerID)
+	}
+	if severity != "" {
+		conditions = append(conditions, "severity = ?")
+		args = append(args, severity)
+	}
+
+	query := "SELECT * FROM audit_findings"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY container_name, rule_id"
+
+	// Заменяем ? на $1, $2 и т.д. для PostgreSQL
+	if s.dbType == "postgresql" {
+		query = sqlx.Rebind(sqlx.DOLLAR, query)
+	}
+
+	var findings []models.AuditFinding
+	err := s.db.Select(&findings, query, args...)
+	return findings, err
+}
+
+// SBOM
+
+// SaveSBOM сохраняет SBOM образа, заменяя ранее сохраненный документ того же формата.
+// Если переданы пакеты, они заменяют состав образа, используемый для поиска
+func (s *Store) SaveSBOM(sbom *models.SBOM, packages []models.SBOMPackage) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM sboms WHERE image_digest = $1 AND format = $2", sbom.ImageDigest, sbom.Format); err != nil {
+		return err
+	}
+
+	_, err = tx.NamedExec(`
+    INSERT INTO sboms (id, image_digest, image, format, scan_id, host_id, container_id, content, created_at)
+    VALUES (:id, :image_digest, :image, :format, :scan_id, :host_id, :container_id, :content, :created_at)
+    `, sbom)
+	if err != nil {
+		return err
+	}
+
+	if packages != nil {
+		if _, err := tx.Exec("DELETE FROM sbom_packages WHERE image_digest = $1", sbom.ImageDigest); err != nil {
+			return err
+		}
+
+		for i := range packages {
+			_, err := tx.NamedExec(`
+            INSERT INTO sbom_packages (id, image_digest, name, version, type, purl)
+            VALUES (:id, :image_digest, :name, :version, :type, :purl)
+            `, &packages[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetSBOM получает SBOM образа в указанном формате.
+// Дайджест можно указывать сокращенно, как и ID контейнера
+func (s *Store) GetSBOM(imageDigest, format string) (*models.SBOM, error) {
+	digest, err := s.ResolveImageDigest(imageDigest)
+	if err != nil {
+		return nil, err
+	}
+
+	var sbom models.SBOM
+	err = s.db.Get(&sbom, "SELECT * FROM sboms WHERE image_digest = $1 AND format = $2", digest, format)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, i18n.Errorf("SBOM в формате %s для образа %s не найден", format, digest)
+		}
+		return nil, err
+	}
+	return &sbom, nil
+}
+
+// ResolveImageDigest находит полный дайджест образа по его началу
+func (s *Store) ResolveImageDigest(prefix string) (string, error) {
+	var digests []string
+	err := s.db.Select(&digests,
+		"SELECT DISTINCT image_digest FROM sboms WHERE image_digest LIKE $1 OR image_digest LIKE $2",
+		prefix+"%", "sha256:"+prefix+"%")
+	if err != nil {
+		return "", err
+	}
+
+	if len(digests) == 0 {
+		return "", i18n.Errorf("образ не найден: %s", prefix)
+	}
+	if len(digests) > 1 {
+		return "", i18n.Errorf("найдено несколько образов с дайджестом, начинающимся с %s: %s",
+			prefix, strings.Join(digests, ", "))
+	}
+	return digests[0], nil
+}
+
+// ListSBOMs возвращает список сохраненных SBOM без содержимого документов
+func (s *Store) ListSBOMs() ([]models.SBOM, error) {
+	var sboms []models.SBOM
+	err := s.db.Select(&sboms, `
+    SELECT id, image_digest, image, format, scan_id, host_id, container_id, '' AS content, created_at
+    FROM sboms ORDER BY created_at DESC
+    `)
+	return sboms, err
+}
+
+// ListSBOMPackages возвращает пакеты образа
+func (s *Store) ListSBOMPackages(imageDigest string) ([]models.SBOMPackage, error) {
+	var packages []models.SBOMPackage
+	err := s.db.Select(&packages, "SELECT * FROM sbom_packages WHERE image_digest = $1 ORDER BY name", imageDigest)
+	return packages, err
+}
+
+// FindSBOMPackages ищет пакеты по имени и, если указана, версии во всех образах
+func (s *Store) FindSBOMPackages(name, version string) ([]models.SBOMPackage, error) {
+	var packages []models.SBOMPackage
+	var err error
+
+	if version != "" {
+		err = s.db.Select(&packages,
+			"SELECT * FROM sbom_packages WHERE name = $1 AND version = $2 ORDER BY image_digest", name, version)
+	} else {
+		err = s.db.Select(&packages,
+			"SELECT * FROM sbom_packages WHERE name = $1 ORDER BY image_digest, version", name)
+	}
+	return packages, err
+}
+
 // Hooks
 
 // AddHook добавляет новый хук
 func (s *Store) AddHook(hook *models.Hook) error {
 	_, err := s.db.NamedExec(`
-    INSERT INTO hooks (id, name, event, script_path, timeout_seconds, enabled, created_at, updated_at)
-    VALUES (:id, :name, :event, :script_path, :timeout_seconds, :enabled, :created_at, :updated_at)
+    INSERT INTO hooks (id, name, type, event, script_path, timeout_seconds, enabled, payload,
+        url, headers, payload_template, secret, host_filter, image_pattern, min_severity, threshold,
+        run_as_user, run_as_group, work_dir, env_allowlist, max_output_bytes, cpu_limit_seconds, memory_limit_mb,
+        created_at, updated_at)
+    VALUES (:id, :name, :type, :event, :script_path, :timeout_seconds, :enabled, :payload,
+        :url, :headers, :payload_template, :secret, :host_filter, :image_pattern, :min_severity, :threshold,
+        :run_as_user, :run_as_group, :work_dir, :env_allowlist, :max_output_bytes, :cpu_limit_seconds, :memory_limit_mb,
+        :created_at, :updated_at)
     `, hook)
 	return err
 }
@@ -501,7 +1151,7 @@ func (s *Store) GetHook(id string) (*models.Hook, error) {
 	err := s.db.Get(&hook, "SELECT * FROM hooks WHERE id = $1", id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("хук не найден: %s", id)
+			return nil, i18n.Errorf("хук не найден: %s", id)
 		}
 		return nil, err
 	}
@@ -519,8 +1169,13 @@ func (s *Store) ListHooks() ([]models.Hook, error) {
 func (s *Store) UpdateHook(hook *models.Hook) error {
 	_, err := s.db.NamedExec(`
     UPDATE hooks 
-    SET name = :name, event = :event, script_path = :script_path, 
-        timeout_seconds = :timeout_seconds, enabled = :enabled, updated_at = :updated_at
+    SET name = :name, type = :type, event = :event, script_path = :script_path, 
+        timeout_seconds = :timeout_seconds, enabled = :enabled, payload = :payload,
+        url = :url, headers = :headers, payload_template = :payload_template, secret = :secret,
+        host_filter = :host_filter, image_pattern = :image_pattern, min_severity = :min_severity,
+        threshold = :threshold, run_as_user = :run_as_user, run_as_group = :run_as_group,
+        work_dir = :work_dir, env_allowlist = :env_allowlist, max_output_bytes = :max_output_bytes,
+        cpu_limit_seconds = :cpu_limit_seconds, memory_limit_mb = :memory_limit_mb, updated_at = :updated_at
     WHERE id = :id
     `, hook)
 	return err
@@ -537,19 +1192,43 @@ func (s *Store) DeleteHook(id string) error {
 // AddHookExecution добавляет новое выполнение хука
 func (s *Store) AddHookExecution(execution *models.HookExecution) error {
 	_, err := s.db.NamedExec(`
-    INSERT INTO hook_executions (id, hook_id, scan_id, status, output, error_msg, started_at, finished_at)
-    VALUES (:id, :hook_id, :scan_id, :status, :output, :error_msg, :started_at, :finished_at)
+    INSERT INTO hook_executions (id, hook_id, hook_name, event, host_id, scan_id, status, exit_code,
+        duration_ms, output, error_msg, started_at, finished_at)
+    VALUES (:id, :hook_id, :hook_name, :event, :host_id, :scan_id, :status, :exit_code,
+        :duration_ms, :output, :error_msg, :started_at, :finished_at)
     `, execution)
 	return err
 }
 
+// ImportHookExecutions сохраняет историю выполнения хуков, полученную с агента.
+// Уже сохраненные записи пропускаются
+func (s *Store) ImportHookExecutions(hostID string, executions []models.HookExecution) (int, error) {
+	imported := 0
+	for _, execution := range executions {
+		var count int
+		if err := s.db.Get(&count, "SELECT COUNT(*) FROM hook_executions WHERE id = $1", execution.ID); err != nil {
+			return imported, i18n.Errorf("ошибка проверки выполнения хука %s: %w", execution.ID, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		execution.HostID = hostID
+		if err := s.AddHookExecution(&execution); err != nil {
+			return imported, i18n.Errorf("ошибка сохранения выполнения хука %s: %w", execution.ID, err)
+		}
+		imported++
+	}
+	return imported, nil
+}
+
 // GetHookExecution получает выполнение хука по ID
 func (s *Store) GetHookExecution(id string) (*models.HookExecution, error) {
 	var execution models.HookExecution
 	err := s.db.Get(&execution, "SELECT * FROM hook_executions WHERE id = $1", id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("выполнение хука не найдено: %s", id)
+			return nil, i18n.Errorf("выполнение хука не найдено: %s", id)
 		}
 		return nil, err
 	}
@@ -558,20 +1237,39 @@ func (s *Store) GetHookExecution(id string) (*models.HookExecution, error) {
 
 // ListHookExecutions возвращает список выполнений хуков
 func (s *Store) ListHookExecutions(hookID, scanID string) ([]models.HookExecution, error) {
-	var query string
+	return s.FilterHookExecutions(hookID, scanID, "", "")
+}
+
+// FilterHookExecutions возвращает выполнения хуков с фильтрацией по хуку, сканированию, хосту и статусу
+func (s *Store) FilterHookExecutions(hookID, scanID, hostID, status string) ([]models.HookExecution, error) {
 	var args []interface{}
+	var conditions []string
 
-	if hookID != "" && scanID != "" {
-		query = "SELECT * FROM hook_executions WHERE hook_id = $1 AND scan_id = $2 ORDER BY started_at DESC"
-		args = []interface{}{hookID, scanID}
-	} else if hookID != "" {
-		query = "SELECT * FROM hook_executions WHERE hook_id = $1 ORDER BY started_at DESC"
-		args = []interface{}{hookID}
-	} else if scanID != "" {
-		query = "SELECT * FROM hook_executions WHERE scan_id = $1 ORDER BY started_at DESC"
-		args = []interface{}{scanID}
-	} else {
-		query = "SELECT * FROM hook_executions ORDER BY started_at DESC"
+	if hookID != "" {
+		conditions = append(conditions, "hook_id = ?")
+		args = append(args, hookID)
+	}
+	if scanID != "" {
+		conditions = append(conditions, "scan_id = ?")
+		args = append(args, scanID)
+	}
+	if hostID != "" {
+		conditions = append(conditions, "host_id = ?")
+		args = append(args, hostID)
+	}
+	if status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, status)
+	}
+
+	query := "SELECT * FROM hook_executions"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY started_at DESC"
+
+	if s.dbType == "postgresql" {
+		query = sqlx.Rebind(sqlx.DOLLAR, query)
 	}
 
 	var executions []models.HookExecution
@@ -579,6 +1277,148 @@ func (s *Store) ListHookExecutions(hookID, scanID string) ([]models.HookExecutio
 	return executions, err
 }
 
+// HookDeadLetters
+
+// AddHookDeadLetter сохраняет событие webhook, которое не удалось доставить
+func (s *Store) AddHookDeadLetter(letter *models.HookDeadLetter) error {
+	_, err := s.db.NamedExec(`
+    INSERT INTO hook_dead_letters (id, hook_id, hook_name, event, scan_id, url, payload, attempts,
+        status_code, last_error, created_at)
+    VALUES (:id, :hook_id, :hook_name, :event, :scan_id, :url, :payload, :attempts,
+        :status_code, :last_error, :created_at)
+    `, letter)
+	return err
+}
+
+// ListHookDeadLetters возвращает недоставленные события webhook, при непустом hookID - только для хука
+func (s *Store) ListHookDeadLetters(hookID string) ([]models.HookDeadLetter, error) {
+	var letters []models.HookDeadLetter
+	var err error
+	if hookID != "" {
+		err = s.db.Select(&letters, "SELECT * FROM hook_dead_letters WHERE hook_id = $1 ORDER BY created_at DESC", hookID)
+	} else {
+		err = s.db.Select(&letters, "SELECT * FROM hook_dead_letters ORDER BY created_at DESC")
+	}
+	return letters, err
+}
+
+// Notifications
+
+// AddNotificationQueueItem ставит уведомление в очередь канала
+func (s *Store) AddNotificationQueueItem(item *models.NotificationQueueItem) error {
+	_, err := s.db.NamedExec(`
+    INSERT INTO notification_queue (id, channel, event, fingerprint, payload, created_at)
+    VALUES (:id, :channel, :event, :fingerprint, :payload, :created_at)
+    `, item)
+	return err
+}
+
+// ListNotificationQueue возвращает уведомления в очереди от старых к новым,
+// при непустом channel - только для канала
+func (s *Store) ListNotificationQueue(channel string) ([]models.NotificationQueueItem, error) {
+	var items []models.NotificationQueueItem
+	var err error
+	if channel != "" {
+		err = s.db.Select(&items, "SELECT * FROM notification_queue WHERE channel = $1 ORDER BY created_at", channel)
+	} else {
+		err = s.db.Select(&items, "SELECT * FROM notification_queue ORDER BY channel, created_at")
+	}
+	return items, err
+}
+
+// ClaimNotificationQueueItems удаляет уведомления из очереди перед отправкой. Возвращает false,
+// если часть из них уже забрал другой процесс: тогда очередь не изменяется
+func (s *Store) ClaimNotificationQueueItems(ids []string) (bool, error) {
+	if len(ids) == 0 {
+		return true, nil
+	}
+
+	query, args, err := sqlx.In("DELETE FROM notification_queue WHERE id IN (?)", ids)
+	if err != nil {
+		return false, err
+	}
+	if s.dbType == "postgresql" {
+		query = sqlx.Rebind(sqlx.DOLLAR, query)
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+	if deleted, err := result.RowsAffected(); err != nil || deleted != int64(len(ids)) {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// AddNotificationDelivery записывает сообщение, отправленное в канал
+func (s *Store) AddNotificationDelivery(delivery *models.NotificationDelivery) error {
+	_, err := s.db.NamedExec(`
+    INSERT INTO notification_deliveries (id, channel, event, events, sent_at)
+    VALUES (:id, :channel, :event, :events, :sent_at)
+    `, delivery)
+	return err
+}
+
+// CountNotificationDeliveries возвращает число сообщений, отправленных в канал после since
+func (s *Store) CountNotificationDeliveries(channel string, since time.Time) (int, error) {
+	var count int
+	err := s.db.Get(&count, "SELECT COUNT(*) FROM notification_deliveries WHERE channel = $1 AND sent_at > $2", channel, since)
+	return count, err
+}
+
+// LastNotificationDelivery возвращает время последней отправки события в любой канал
+// или нулевое время, если событие не отправлялось
+func (s *Store) LastNotificationDelivery(event string) (time.Time, error) {
+	var deliveries []models.NotificationDelivery
+	err := s.db.Select(&deliveries, "SELECT * FROM notification_deliveries WHERE event = $1 ORDER BY sent_at DESC LIMIT 1", event)
+	if err != nil || len(deliveries) == 0 {
+		return time.Time{}, err
+	}
+	return deliveries[0].SentAt, nil
+}
+
+// NotificationSeen проверяет, отправлялись ли в канал находки с этим отпечатком после since
+// или ожидают ли они отправки в очереди канала
+func (s *Store) NotificationSeen(channel, fingerprint string, since time.Time) (bool, error) {
+	var count int
+	err := s.db.Get(&count, `
+    SELECT (SELECT COUNT(*) FROM notification_fingerprints WHERE channel = $1 AND fingerprint = $2 AND sent_at > $3)
+         + (SELECT COUNT(*) FROM notification_queue WHERE channel = $1 AND fingerprint = $2)
+    `, channel, fingerprint, since)
+	return count > 0, err
+}
+
+// SaveNotificationFingerprints запоминает отпечатки находок, отправленных в канал
+func (s *Store) SaveNotificationFingerprints(channel string, fingerprints []string, sentAt time.Time) error {
+	for _, fingerprint := range fingerprints {
+		_, err := s.db.Exec(`
+        INSERT INTO notification_fingerprints (channel, fingerprint, sent_at) VALUES ($1, $2, $3)
+        ON CONFLICT (channel, fingerprint) DO UPDATE SET sent_at = excluded.sent_at
+        `, channel, fingerprint, sentAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PruneNotificationHistory удаляет записи об отправленных сообщениях старше deliveriesBefore
+// и отпечатки находок старше fingerprintsBefore
+func (s *Store) PruneNotificationHistory(deliveriesBefore, fingerprintsBefore time.Time) error {
+	if _, err := s.db.Exec("DELETE FROM notification_deliveries WHERE sent_at < $1", deliveriesBefore); err != nil {
+		return err
+	}
+	_, err := s.db.Exec("DELETE FROM notification_fingerprints WHERE sent_at < $1", fingerprintsBefore)
+	return err
+}
+
 // RemediationStrategies
 
 // GetRemediationStrategy получает стратегию восстановления по ID
@@ -587,7 +1427,7 @@ func (s *Store) GetRemediationStrategy(id string) (*models.RemediationStrategy,
 	err := s.db.Get(&strategy, "SELECT * FROM remediation_strategies WHERE id = $1", id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("стратегия восстановления не найдена: %s", id)
+			return nil, i18n.Errorf("стратегия восстановления не найдена: %s", id)
 		}
 		return nil, err
 	}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func TestNewAgentStoreCreatesOnlyHookTables(t *testing.T) {
+	store, err := NewAgentStore(filepath.Join(t.TempDir(), "agent.db"), testLogger())
+	if err != nil {
+		t.Fatalf("NewAgentStore: %v", err)
+	}
+	defer store.Close()
+
+	var tables []string
+	if err := store.db.Select(&tables, "SELECT name FROM sqlite_master WHERE type = 'table' ORDER BY name"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"hook_dead_letters", "hook_executions", "hooks"}
+	if len(tables) != len(want) {
+		t.Fatalf("таблицы %v, ожидается %v", tables, want)
+	}
+	for i := range want {
+		if tables[i] != want[i] {
+			t.Fatalf("таблицы %v, ожидается %v", tables, want)
+		}
+	}
+
+	// Выполнение хука из конфигурации агента для сканирования, которого нет в базе
+	now := time.Now()
+	if err := store.AddHookExecution(&models.HookExecution{
+		ID: "exec-1", HookID: "config-hook", ScanID: "scan-1", Status: "success", StartedAt: now, FinishedAt: now,
+	}); err != nil {
+		t.Fatalf("AddHookExecution: %v", err)
+	}
+}
+
+func TestHookExecutionForeignKeysDropped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aegis.db")
+
+	// База первой версии схемы: hook_executions ссылается на hooks и scans
+	old, err := sqlx.Connect("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = old.Exec(`
+    CREATE TABLE hook_executions (
+        id TEXT PRIMARY KEY,
+        hook_id TEXT NOT NULL,
+        scan_id TEXT NOT NULL,
+        status TEXT NOT NULL,
+        output TEXT,
+        error_msg TEXT,
+        started_at TIMESTAMP NOT NULL,
+        finished_at TIMESTAMP NOT NULL,
+        FOREIGN KEY (hook_id) REFERENCES hooks(id) ON DELETE CASCADE,
+        FOREIGN KEY (scan_id) REFERENCES scans(id) ON DELETE CASCADE
+    );
+    INSERT INTO hook_executions VALUES ('exec-old', 'hook-1', 'scan-1', 'success', 'ok', '', '2024-01-01 00:00:00', '2024-01-01 00:00:01')
+    `)
+	old.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewAgentStore(path, testLogger())
+	if err != nil {
+		t.Fatalf("NewAgentStore: %v", err)
+	}
+	defer store.Close()
+
+	var count int
+	if err := store.db.Get(&count, "SELECT COUNT(*) FROM pragma_foreign_key_list('hook_executions')"); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("внешних ключей hook_executions: %d, ожидается 0", count)
+	}
+
+	execution, err := store.GetHookExecution("exec-old")
+	if err != nil {
+		t.Fatalf("запись потеряна при обновлении таблицы: %v", err)
+	}
+	if execution.HookID != "hook-1" || execution.Output != "ok" {
+		t.Errorf("запись после обновления таблицы: %+v", execution)
+	}
+}
@@ -0,0 +1,76 @@
+package hooks
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+)
+
+// eventPayload возвращает событие в формате JSON с объемом данных, выбранным в настройках хука
+func eventPayload(hook models.Hook, event *models.HookEvent) ([]byte, error) {
+	payload := *event
+	if hook.Payload == models.HookPayloadSummary {
+		payload.Vulnerabilities = nil
+		payload.Secrets = nil
+		payload.Misconfigs = nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка формирования события хука: %w", err)
+	}
+	return data, nil
+}
+
+// eventEnv возвращает переменные окружения AEGIS_* с основными полями события,
+// чтобы простым скриптам не требовалось разбирать JSON
+func eventEnv(event *models.HookEvent) []string {
+	vulns := event.Summary.Vulnerabilities
+	env := map[string]string{
+		"AEGIS_EVENT":            event.Event,
+		"AEGIS_EVENT_VERSION":    strconv.Itoa(event.Version),
+		"AEGIS_HOST":             event.Host.Hostname,
+		"AEGIS_HOST_ID":          event.Host.ID,
+		"AEGIS_HOST_ADDRESS":     event.Host.Address,
+		"AEGIS_RUNTIME":          event.Host.Runtime,
+		"AEGIS_SCAN_ID":          event.Scan.ID,
+		"AEGIS_SCAN_STATUS":      event.Scan.Status,
+		"AEGIS_SCANNERS":         strings.Join(event.Scan.Scanners, ","),
+		"AEGIS_SCAN_DURATION_MS": strconv.FormatInt(event.Scan.DurationMs, 10),
+		"AEGIS_IMAGE_DIGEST":     event.Scan.ImageDigest,
+		"AEGIS_ERROR":            event.Scan.Error,
+		"AEGIS_PREVIOUS_SCAN_ID": event.Scan.PreviousScanID,
+		"AEGIS_VULNS_TOTAL":      strconv.Itoa(vulns.Total),
+		"AEGIS_VULNS_CRITICAL":   strconv.Itoa(vulns.Critical),
+		"AEGIS_VULNS_HIGH":       strconv.Itoa(vulns.High),
+		"AEGIS_VULNS_MEDIUM":     strconv.Itoa(vulns.Medium),
+		"AEGIS_VULNS_LOW":        strconv.Itoa(vulns.Low),
+		"AEGIS_SECRETS_TOTAL":    strconv.Itoa(event.Summary.Secrets.Total),
+		"AEGIS_MISCONFIGS_TOTAL": strconv.Itoa(event.Summary.Misconfigs.Total),
+	}
+
+	if c := event.Container; c != nil {
+		env["AEGIS_CONTAINER_ID"] = c.ID
+		env["AEGIS_CONTAINER_NAME"] = c.Name
+		env["AEGIS_IMAGE"] = c.Image
+		env["AEGIS_POD_NAME"] = c.PodName
+		env["AEGIS_NAMESPACE"] = c.PodNamespace
+		env["AEGIS_WORKLOAD_KIND"] = c.WorkloadKind
+		env["AEGIS_WORKLOAD_NAME"] = c.WorkloadName
+	}
+
+	if r := event.Remediation; r != nil {
+		env["AEGIS_REMEDIATION_STRATEGY"] = r.StrategyID
+		env["AEGIS_REMEDIATION_TYPE"] = r.Type
+		env["AEGIS_REMEDIATION_COMMAND"] = r.Command
+	}
+
+	result := make([]string, 0, len(env))
+	for name, value := range env {
+		result = append(result, name+"="+value)
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package hooks
+
+import (
+	"path"
+	"strings"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+)
+
+// severities содержит допустимые значения min_severity
+var severities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
+
+// ValidateFilters проверяет фильтры событий хука
+func ValidateFilters(hook *models.Hook) error {
+	if hook.ImagePattern != "" {
+		if _, err := path.Match(hook.ImagePattern, ""); err != nil {
+			return i18n.Errorf("некорректный шаблон образа: %s", hook.ImagePattern)
+		}
+	}
+
+	if hook.MinSeverity != "" {
+		valid := false
+		for _, severity := range severities {
+			if strings.EqualFold(hook.MinSeverity, severity) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return i18n.Errorf("недопустимая серьезность: %s (допустимо: %s)", hook.MinSeverity, strings.Join(severities, ", "))
+		}
+	}
+
+	if hook.Threshold < 0 {
+		return i18n.Errorf("порог не может быть отрицательным")
+	}
+
+	return nil
+}
+
+// hasFindings проверяет, содержит ли событие результаты сканирования.
+// Фильтр серьезности применяется только к таким событиям
+func hasFindings(event string) bool {
+	switch event {
+	case models.HookEventScanComplete, models.HookEventCriticalFound, models.HookEventNewVulnerability:
+		return true
+	default:
+		return false
+	}
+}
+
+// matchesFilters проверяет, подходит ли событие под фильтры хука
+func matchesFilters(hook models.Hook, event *models.HookEvent) bool {
+	if hook.HostFilter != "" && hook.HostFilter != event.Host.Hostname && hook.HostFilter != event.Host.ID {
+		return false
+	}
+
+	// Событие без контейнера (например, on_host_offline) не может соответствовать шаблону образа
+	if hook.ImagePattern != "" {
+		if event.Container == nil {
+			return false
+		}
+		if ok, _ := path.Match(hook.ImagePattern, event.Container.Image); !ok {
+			return false
+		}
+	}
+
+	if !hasFindings(event.Event) {
+		return true
+	}
+
+	// on_critical_found по умолчанию срабатывает на первую критическую находку
+	severity := hook.MinSeverity
+	if severity == "" {
+		if event.Event != models.HookEventCriticalFound {
+			return true
+		}
+		severity = "CRITICAL"
+	}
+
+	threshold := hook.Threshold
+	if threshold < 1 {
+		threshold = 1
+	}
+	return event.Summary.AtLeast(severity) >= threshold
+}
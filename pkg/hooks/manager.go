@@ -1,33 +1,147 @@
 package hooks
 
 import (
+	"bytes"
 	"context"
-	"fmt"
+	"errors"
 	"os/exec"
 	"sync"
 	"time"
 
+	"github.com/aegis/aegis-cli/pkg/i18n"
 	"github.com/aegis/aegis-cli/pkg/models"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+// ExecutionStore сохраняет историю выполнения хуков
+type ExecutionStore interface {
+	AddHookExecution(execution *models.HookExecution) error
+	ListHookExecutions(hookID, scanID string) ([]models.HookExecution, error)
+	AddHookDeadLetter(letter *models.HookDeadLetter) error
+	ListHookDeadLetters(hookID string) ([]models.HookDeadLetter, error)
+}
+
+// HookStore сохраняет хуки, добавленные через API агента, между перезапусками
+type HookStore interface {
+	ListHooks() ([]models.Hook, error)
+	AddHook(hook *models.Hook) error
+	UpdateHook(hook *models.Hook) error
+	DeleteHook(id string) error
+}
+
 // Manager представляет менеджер хуков
 type Manager struct {
-	hooks  []models.Hook
-	logger *logrus.Logger
-	mu     sync.RWMutex // Мьютекс для безопасного доступа к хукам
+	hooks       []models.Hook
+	configHooks map[string]bool // ID хуков из конфигурации, которые нельзя изменить через API
+	store       ExecutionStore  // nil - история выполнения не сохраняется
+	hookStore   HookStore       // nil - хуки, добавленные через API, не переживают перезапуск
+	logger      *logrus.Logger
+	mu          sync.RWMutex        // Мьютекс для безопасного доступа к хукам
+	wg          sync.WaitGroup      // Выполняющиеся хуки
+	sem         chan struct{}       // Ограничивает число одновременно выполняющихся скриптов
+	sleep       func(time.Duration) // Ожидание между попытками доставки webhook, в тестах заменяется
 }
 
-// NewManager создает новый менеджер хуков
-func NewManager(hooks []models.Hook) *Manager {
+// NewManager создает новый менеджер хуков. Переданные хуки считаются заданными в конфигурации
+func NewManager(hooks []models.Hook, store ExecutionStore) *Manager {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 
+	configHooks := make(map[string]bool, len(hooks))
+	for i := range hooks {
+		hooks[i].Source = models.HookSourceConfig
+		configHooks[hooks[i].ID] = true
+	}
+
 	return &Manager{
-		hooks:  hooks,
-		logger: logger,
+		hooks:       hooks,
+		configHooks: configHooks,
+		store:       store,
+		logger:      logger,
+		sem:         make(chan struct{}, defaultHookConcurrency),
+		sleep:       time.Sleep,
+	}
+}
+
+// UseHookStore подключает хранилище хуков, добавленных через API, и загружает их.
+// Сохраненный хук с ID хука из конфигурации пропускается: конфигурация имеет приоритет
+func (m *Manager) UseHookStore(store HookStore) error {
+	stored, err := store.ListHooks()
+	if err != nil {
+		return i18n.Errorf("ошибка загрузки хуков: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hookStore = store
+	for _, hook := range stored {
+		if m.configHooks[hook.ID] {
+			m.logger.WithField("hook_id", hook.ID).Warn("Stored hook shadowed by config hook")
+			continue
+		}
+		hook.Source = models.HookSourceAPI
+		m.hooks = append(m.hooks, hook)
 	}
+	return nil
+}
+
+// SaveHook проверяет хук, добавляет или обновляет его и сохраняет в хранилище
+func (m *Manager) SaveHook(hook models.Hook) error {
+	if m.configHooks[hook.ID] {
+		return i18n.Errorf("хук %s задан в конфигурации агента и не может быть изменен через API", hook.ID)
+	}
+	if err := m.ValidateHook(&hook); err != nil {
+		return err
+	}
+	hook.Source = models.HookSourceAPI
+
+	if m.hookStore != nil {
+		_, err := m.GetHook(hook.ID)
+		if err == nil {
+			err = m.hookStore.UpdateHook(&hook)
+		} else {
+			err = m.hookStore.AddHook(&hook)
+		}
+		if err != nil {
+			return i18n.Errorf("ошибка сохранения хука: %w", err)
+		}
+	}
+
+	m.AddHook(hook)
+	return nil
+}
+
+// DeleteHook удаляет хук, добавленный через API, из менеджера и хранилища
+func (m *Manager) DeleteHook(id string) error {
+	if m.configHooks[id] {
+		return i18n.Errorf("хук %s задан в конфигурации агента и не может быть удален через API", id)
+	}
+	if err := m.RemoveHook(id); err != nil {
+		return err
+	}
+
+	if m.hookStore != nil {
+		if err := m.hookStore.DeleteHook(id); err != nil {
+			return i18n.Errorf("ошибка удаления хука из хранилища: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetLogger заменяет логгер менеджера, например на логгер CLI с выводом в файл
+func (m *Manager) SetLogger(logger *logrus.Logger) {
+	m.logger = logger
+}
+
+// SetConcurrency задает, сколько скриптов хуков может выполняться одновременно.
+// Остальные ожидают в очереди. Вызывается до выполнения хуков
+func (m *Manager) SetConcurrency(n int) {
+	if n < 1 {
+		n = defaultHookConcurrency
+	}
+	m.sem = make(chan struct{}, n)
 }
 
 // AddHook добавляет новый хук
@@ -72,7 +186,7 @@ func (m *Manager) RemoveHook(id string) error {
 		}
 	}
 
-	return fmt.Errorf("хук не найден: %s", id)
+	return i18n.Errorf("хук не найден: %s", id)
 }
 
 // GetHook возвращает хук по ID
@@ -86,7 +200,7 @@ func (m *Manager) GetHook(id string) (*models.Hook, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("хук не найден: %s", id)
+	return nil, i18n.Errorf("хук не найден: %s", id)
 }
 
 // ListHooks возвращает список всех хуков
@@ -101,13 +215,21 @@ func (m *Manager) ListHooks() []models.Hook {
 	return hooks
 }
 
-// ExecuteHooks выполняет все хуки для указанного события
-func (m *Manager) ExecuteHooks(event string, scanID string) {
+// ExecuteHooks выполняет все хуки, подписанные на событие и подходящие под свои фильтры.
+// Хук получает ID сканирования в argv[1], событие в формате JSON в stdin и его основные
+// поля в переменных AEGIS_*
+func (m *Manager) ExecuteHooks(event *models.HookEvent) {
+	m.ExecuteHooksContext(context.Background(), event)
+}
+
+// ExecuteHooksContext выполняет хуки события; span выполнения хуков становятся
+// дочерними для span из ctx. Отмена ctx выполнение не прерывает
+func (m *Manager) ExecuteHooksContext(ctx context.Context, event *models.HookEvent) {
 	m.mu.RLock()
 	// Сначала получаем список хуков для выполнения
 	var hooksToExecute []models.Hook
 	for _, hook := range m.hooks {
-		if hook.Event == event && hook.Enabled {
+		if hook.Event == event.Event && hook.Enabled && matchesFilters(hook, event) {
 			hooksToExecute = append(hooksToExecute, hook)
 		}
 	}
@@ -115,15 +237,28 @@ func (m *Manager) ExecuteHooks(event string, scanID string) {
 
 	// Затем выполняем хуки (вне критической секции)
 	for _, hook := range hooksToExecute {
-		go m.executeHook(hook, scanID)
+		m.wg.Add(1)
+		go func(hook models.Hook) {
+			defer m.wg.Done()
+			m.executeHook(ctx, hook, event)
+		}(hook)
 	}
 }
 
+// Wait ожидает завершения запущенных хуков. Нужен CLI, который завершается сразу после события
+func (m *Manager) Wait() {
+	m.wg.Wait()
+}
+
 // executeHook выполняет один хук
-func (m *Manager) executeHook(hook models.Hook, scanID string) {
+func (m *Manager) executeHook(ctx context.Context, hook models.Hook, event *models.HookEvent) {
+	scanID := event.Scan.ID
 	execution := models.HookExecution{
 		ID:        uuid.New().String(),
 		HookID:    hook.ID,
+		HookName:  hook.Name,
+		Event:     hook.Event,
+		HostID:    event.Host.ID,
 		ScanID:    scanID,
 		StartedAt: time.Now(),
 	}
@@ -136,70 +271,175 @@ func (m *Manager) executeHook(hook models.Hook, scanID string) {
 		"scan_id":      scanID,
 	}).Info("Executing hook")
 
-	// Создаем контекст с таймаутом
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(hook.TimeoutSeconds)*time.Second)
-	defer cancel()
-
-	// Выполняем скрипт
-	cmd := exec.CommandContext(ctx, hook.ScriptPath, scanID)
-	output, err := cmd.CombinedOutput()
+	var err error
+	if hook.IsWebhook() {
+		err = m.deliverWebhook(hook, event, &execution)
+	} else {
+		err = m.runScript(hook, event, &execution)
+	}
 
 	execution.FinishedAt = time.Now()
-	execution.Output = string(output)
+	execution.DurationMs = execution.FinishedAt.Sub(execution.StartedAt).Milliseconds()
 
 	if err != nil {
-		execution.Status = "failure"
+		execution.Status = models.HookStatusFailure
 		execution.ErrorMsg = err.Error()
 		m.logger.WithFields(logrus.Fields{
 			"execution_id": execution.ID,
 			"hook_id":      hook.ID,
 			"hook_name":    hook.Name,
+			"exit_code":    execution.ExitCode,
 			"error":        err,
-			"output":       string(output),
+			"output":       execution.Output,
 		}).Error("Hook execution failed")
 	} else {
-		execution.Status = "success"
+		execution.Status = models.HookStatusSuccess
 		m.logger.WithFields(logrus.Fields{
 			"execution_id": execution.ID,
 			"hook_id":      hook.ID,
 			"hook_name":    hook.Name,
-			"output":       string(output),
+			"duration_ms":  execution.DurationMs,
+			"output":       execution.Output,
 		}).Info("Hook execution succeeded")
 	}
 
-	// Здесь можно сохранить результаты выполнения в БД
-	// TODO: Реализовать сохранение результатов выполнения хука
+	observeExecution(ctx, hook, &execution, err)
+
+	if m.store == nil {
+		return
+	}
+	if err := m.store.AddHookExecution(&execution); err != nil {
+		m.logger.WithError(err).WithField("execution_id", execution.ID).Error("Failed to save hook execution")
+	}
+}
+
+// runScript выполняет скрипт хука в песочнице, передавая ему событие в stdin и переменных окружения.
+// Нарушение лимитов останавливает скрипт и считается ошибкой выполнения
+func (m *Manager) runScript(hook models.Hook, event *models.HookEvent, execution *models.HookExecution) error {
+	// Время ожидания в очереди не входит в длительность выполнения и таймаут
+	m.sem <- struct{}{}
+	defer func() { <-m.sem }()
+	execution.StartedAt = time.Now()
+
+	// Создаем контекст с таймаутом
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(hook.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	cmd, err := sandboxCommand(ctx, hook, event)
+	if err != nil {
+		execution.ExitCode = -1
+		return err
+	}
+	if payload, err := eventPayload(hook, event); err != nil {
+		m.logger.WithError(err).WithField("hook_id", hook.ID).Error("Failed to build hook event")
+	} else {
+		cmd.Stdin = bytes.NewReader(payload)
+	}
+
+	limit := hook.MaxOutputBytes
+	if limit <= 0 {
+		limit = defaultMaxOutputBytes
+	}
+	output := &limitedOutput{limit: limit, onOverflow: cancel}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	// Дочерние процессы скрипта могут удерживать вывод открытым после завершения по таймауту
+	cmd.WaitDelay = time.Second
+	err = cmd.Run()
+
+	execution.Output = output.String()
+	execution.ExitCode = exitCode(cmd, err)
+
+	switch {
+	case output.exceeded:
+		return i18n.Errorf("вывод превысил %d байт, скрипт остановлен", limit)
+	case err != nil && ctx.Err() == context.DeadlineExceeded:
+		return i18n.Errorf("превышен таймаут %d с", hook.TimeoutSeconds)
+	case err != nil && cpuLimitExceeded(cmd):
+		return i18n.Errorf("превышен лимит процессорного времени %d с", hook.CPULimitSeconds)
+	}
+	return err
+}
+
+// exitCode возвращает код завершения скрипта хука или -1, если скрипт не был запущен
+// либо был остановлен сигналом (в том числе по таймауту)
+func exitCode(cmd *exec.Cmd, err error) int {
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return -1
+	}
+	if cmd.ProcessState == nil {
+		return -1
+	}
+	return cmd.ProcessState.ExitCode()
+}
+
+// ListDeadLetters возвращает недоставленные события webhook
+func (m *Manager) ListDeadLetters(hookID string) ([]models.HookDeadLetter, error) {
+	if m.store == nil {
+		return nil, i18n.Errorf("недоставленные события не сохраняются: хранилище не настроено")
+	}
+	return m.store.ListHookDeadLetters(hookID)
+}
+
+// ListExecutions возвращает историю выполнения хуков с фильтрацией по хуку и сканированию
+func (m *Manager) ListExecutions(hookID, scanID string) ([]models.HookExecution, error) {
+	if m.store == nil {
+		return nil, i18n.Errorf("история выполнения хуков не сохраняется: хранилище не настроено")
+	}
+	return m.store.ListHookExecutions(hookID, scanID)
 }
 
 // ValidateHook проверяет хук на корректность
 func (m *Manager) ValidateHook(hook *models.Hook) error {
 	if hook.Name == "" {
-		return fmt.Errorf("имя хука не может быть пустым")
+		return i18n.Errorf("имя хука не может быть пустым")
 	}
 
-	if hook.ScriptPath == "" {
-		return fmt.Errorf("путь к скрипту не может быть пустым")
+	switch hook.Type {
+	case "", models.HookTypeScript:
+		if hook.ScriptPath == "" {
+			return i18n.Errorf("путь к скрипту не может быть пустым")
+		}
+	case models.HookTypeWebhook:
+		if err := ValidateWebhook(hook); err != nil {
+			return err
+		}
+	default:
+		return i18n.Errorf("недопустимый тип хука: %s (допустимо: script, webhook)", hook.Type)
 	}
 
 	if hook.TimeoutSeconds <= 0 {
-		return fmt.Errorf("таймаут должен быть положительным числом")
+		return i18n.Errorf("таймаут должен быть положительным числом")
 	}
 
 	// Проверка события
-	validEvents := map[string]bool{
-		"on_scan_start":    true,
-		"on_scan_complete": true,
-		"on_error":         true,
+	if !models.IsValidHookEvent(hook.Event) {
+		return i18n.Errorf("недопустимое событие: %s", hook.Event)
+	}
+
+	if err := ValidateFilters(hook); err != nil {
+		return err
 	}
 
-	if !validEvents[hook.Event] {
-		return fmt.Errorf("недопустимое событие: %s", hook.Event)
+	if err := ValidateSandbox(hook); err != nil {
+		return err
+	}
+
+	switch hook.Payload {
+	case "", models.HookPayloadFull, models.HookPayloadSummary:
+	default:
+		return i18n.Errorf("недопустимый объем данных события: %s (допустимо: full, summary)", hook.Payload)
 	}
 
 	// Проверка доступности скрипта
-	_, err := exec.LookPath(hook.ScriptPath)
-	if err != nil {
-		return fmt.Errorf("скрипт не найден или не исполняемый: %s", hook.ScriptPath)
+	if !hook.IsWebhook() {
+		if _, err := exec.LookPath(hook.ScriptPath); err != nil {
+			return i18n.Errorf("скрипт не найден или не исполняемый: %s", hook.ScriptPath)
+		}
+		if err := checkSandboxHost(hook); err != nil {
+			return err
+		}
 	}
 
 	return nil
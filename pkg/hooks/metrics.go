@@ -0,0 +1,47 @@
+package hooks
+
+import (
+	"context"
+
+	"github.com/aegis/aegis-cli/pkg/metrics"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Метрики выполнения хуков
+var (
+	hookExecutions = metrics.NewCounterVec("aegis_agent_hook_executions_total",
+		"Hook executions by hook type, event and result.", "type", "event", "status")
+	hookDuration = metrics.NewHistogramVec("aegis_agent_hook_duration_seconds",
+		"Hook execution time by hook type.", []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 300}, "type")
+)
+
+// observeExecution учитывает завершившееся выполнение хука в метриках и записывает его span.
+// Span строится по времени выполнения, без ожидания в очереди скриптов
+func observeExecution(ctx context.Context, hook models.Hook, execution *models.HookExecution, err error) {
+	hookType := models.HookTypeScript
+	if hook.IsWebhook() {
+		hookType = models.HookTypeWebhook
+	}
+	hookExecutions.Inc(hookType, execution.Event, execution.Status)
+	hookDuration.Observe(execution.FinishedAt.Sub(execution.StartedAt).Seconds(), hookType)
+
+	_, span := tracing.Start(ctx, "hook "+hook.Name,
+		trace.WithTimestamp(execution.StartedAt),
+		trace.WithAttributes(
+			attribute.String("aegis.hook.id", hook.ID),
+			attribute.String("aegis.hook.type", hookType),
+			attribute.String("aegis.hook.event", execution.Event),
+			attribute.String("aegis.execution_id", execution.ID),
+			attribute.Int("aegis.hook.exit_code", execution.ExitCode),
+		),
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(execution.FinishedAt))
+}
@@ -0,0 +1,148 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+)
+
+// Ограничения выполнения скриптов по умолчанию
+const (
+	defaultMaxOutputBytes  = 64 * 1024
+	defaultHookConcurrency = 4
+)
+
+// defaultEnvAllowlist содержит переменные окружения агента, которые получает скрипт без env_allowlist
+var defaultEnvAllowlist = models.HookEnvList{"PATH", "HOME", "LANG", "LC_ALL", "TZ"}
+
+// ValidateSandbox проверяет ограничения выполнения скрипта без обращения к системе.
+// Существование пользователя и каталога проверяет агент при сохранении хука
+func ValidateSandbox(hook *models.Hook) error {
+	if hook.WorkDir != "" && !filepath.IsAbs(hook.WorkDir) {
+		return i18n.Errorf("рабочий каталог должен быть абсолютным путем: %s", hook.WorkDir)
+	}
+	if hook.MaxOutputBytes < 0 || hook.CPULimitSeconds < 0 || hook.MemoryLimitMB < 0 {
+		return i18n.Errorf("лимиты выполнения не могут быть отрицательными")
+	}
+	for _, name := range hook.EnvAllowlist {
+		if strings.ContainsAny(name, "= ") {
+			return i18n.Errorf("некорректное имя переменной окружения: %q", name)
+		}
+	}
+	return nil
+}
+
+// checkSandboxHost проверяет, что пользователь, группа и рабочий каталог хука существуют на хосте
+func checkSandboxHost(hook *models.Hook) error {
+	if hook.WorkDir != "" {
+		info, err := os.Stat(hook.WorkDir)
+		if err != nil || !info.IsDir() {
+			return i18n.Errorf("рабочий каталог не найден: %s", hook.WorkDir)
+		}
+	}
+
+	cmd := exec.Command(hook.ScriptPath)
+	if _, err := setCredentials(cmd, hook.RunAsUser, hook.RunAsGroup); err != nil {
+		return err
+	}
+	return nil
+}
+
+// sandboxCommand создает команду запуска скрипта хука с ограничениями из его настроек
+func sandboxCommand(ctx context.Context, hook models.Hook, event *models.HookEvent) (*exec.Cmd, error) {
+	name, args := hook.ScriptPath, []string{event.Scan.ID}
+
+	// Лимиты ресурсов задаются через ulimit оболочки, которая затем заменяется скриптом:
+	// пакет os/exec не позволяет установить rlimit только для дочернего процесса
+	if limits := ulimitCommand(hook); limits != "" {
+		args = append([]string{"-c", limits + ` && exec "$0" "$@"`, hook.ScriptPath}, args...)
+		name = "/bin/sh"
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = hook.WorkDir
+
+	userEnv, err := setCredentials(cmd, hook.RunAsUser, hook.RunAsGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	// Более поздние значения переменных заменяют более ранние
+	cmd.Env = append(hookEnv(hook.EnvAllowlist), userEnv...)
+	cmd.Env = append(cmd.Env, eventEnv(event)...)
+	return cmd, nil
+}
+
+// ulimitCommand возвращает команду ulimit для лимитов процессорного времени и памяти хука
+func ulimitCommand(hook models.Hook) string {
+	var limits []string
+	if hook.CPULimitSeconds > 0 {
+		limits = append(limits, fmt.Sprintf("ulimit -t %d", hook.CPULimitSeconds))
+	}
+	if hook.MemoryLimitMB > 0 {
+		limits = append(limits, fmt.Sprintf("ulimit -v %d", hook.MemoryLimitMB*1024))
+	}
+	return strings.Join(limits, " && ")
+}
+
+// hookEnv возвращает переменные окружения агента, разрешенные списком
+func hookEnv(allowlist models.HookEnvList) []string {
+	if len(allowlist) == 0 {
+		allowlist = defaultEnvAllowlist
+	}
+
+	var env []string
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		for _, allowed := range allowlist {
+			if allowed == name || allowed == "*" ||
+				(strings.HasSuffix(allowed, "*") && strings.HasPrefix(name, strings.TrimSuffix(allowed, "*"))) {
+				env = append(env, entry)
+				break
+			}
+		}
+	}
+	return env
+}
+
+// limitedOutput сохраняет вывод скрипта до заданного размера. При превышении
+// вызывается onOverflow, чтобы остановить скрипт, а остаток вывода отбрасывается
+type limitedOutput struct {
+	mu         sync.Mutex
+	buf        []byte
+	limit      int
+	exceeded   bool
+	onOverflow func()
+}
+
+func (o *limitedOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.exceeded {
+		return len(p), nil
+	}
+
+	if room := o.limit - len(o.buf); len(p) > room {
+		o.buf = append(o.buf, p[:room]...)
+		o.exceeded = true
+		o.onOverflow()
+		return len(p), nil
+	}
+
+	o.buf = append(o.buf, p...)
+	return len(p), nil
+}
+
+func (o *limitedOutput) String() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return string(o.buf)
+}
@@ -0,0 +1,22 @@
+//go:build !unix
+
+package hooks
+
+import (
+	"os/exec"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+)
+
+// setCredentials не поддерживается на этой платформе: запуск от имени другого пользователя невозможен
+func setCredentials(cmd *exec.Cmd, username, group string) ([]string, error) {
+	if username != "" || group != "" {
+		return nil, i18n.Errorf("запуск хука от имени пользователя или группы не поддерживается на этой платформе")
+	}
+	return nil, nil
+}
+
+// cpuLimitExceeded всегда возвращает false: лимит процессорного времени доступен только в Unix
+func cpuLimitExceeded(cmd *exec.Cmd) bool {
+	return false
+}
@@ -0,0 +1,72 @@
+//go:build unix
+
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+)
+
+// setCredentials настраивает запуск команды от имени пользователя и группы и возвращает
+// переменные HOME и USER пользователя. Дополнительные группы процесса агента сбрасываются
+func setCredentials(cmd *exec.Cmd, username, group string) ([]string, error) {
+	if username == "" && group == "" {
+		return nil, nil
+	}
+
+	credential := &syscall.Credential{Uid: uint32(os.Getuid()), Gid: uint32(os.Getgid())}
+	var env []string
+
+	if username != "" {
+		u, err := user.Lookup(username)
+		if err != nil {
+			if u, err = user.LookupId(username); err != nil {
+				return nil, i18n.Errorf("пользователь не найден: %s", username)
+			}
+		}
+
+		uid, err := strconv.ParseUint(u.Uid, 10, 32)
+		if err != nil {
+			return nil, i18n.Errorf("некорректный UID пользователя %s: %s", username, u.Uid)
+		}
+		gid, err := strconv.ParseUint(u.Gid, 10, 32)
+		if err != nil {
+			return nil, i18n.Errorf("некорректный GID пользователя %s: %s", username, u.Gid)
+		}
+		credential.Uid = uint32(uid)
+		credential.Gid = uint32(gid)
+		env = append(env, "HOME="+u.HomeDir, "USER="+u.Username)
+	}
+
+	if group != "" {
+		g, err := user.LookupGroup(group)
+		if err != nil {
+			if g, err = user.LookupGroupId(group); err != nil {
+				return nil, i18n.Errorf("группа не найдена: %s", group)
+			}
+		}
+
+		gid, err := strconv.ParseUint(g.Gid, 10, 32)
+		if err != nil {
+			return nil, i18n.Errorf("некорректный GID группы %s: %s", group, g.Gid)
+		}
+		credential.Gid = uint32(gid)
+	}
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{Credential: credential}
+	return env, nil
+}
+
+// cpuLimitExceeded проверяет, был ли скрипт остановлен по лимиту процессорного времени
+func cpuLimitExceeded(cmd *exec.Cmd) bool {
+	if cmd.ProcessState == nil {
+		return false
+	}
+	status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled() && status.Signal() == syscall.SIGXCPU
+}
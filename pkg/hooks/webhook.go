@@ -0,0 +1,222 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Заголовки запроса webhook
+const (
+	SignatureHeader = "X-Aegis-Signature" // sha256=<HMAC-SHA256 тела запроса в hex>
+	EventHeader     = "X-Aegis-Event"
+	DeliveryHeader  = "X-Aegis-Delivery" // ID выполнения хука, одинаковый для всех попыток
+)
+
+// Параметры повторной доставки webhook
+const (
+	webhookMaxAttempts    = 5
+	webhookInitialBackoff = time.Second
+	webhookMaxBackoff     = 30 * time.Second
+	webhookResponseLimit  = 4096 // Сколько байт ответа сохраняется в истории выполнения
+)
+
+// templateFuncs содержит функции, доступные в шаблоне тела webhook
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// ValidateWebhook проверяет параметры хука типа webhook
+func ValidateWebhook(hook *models.Hook) error {
+	u, err := url.Parse(hook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return i18n.Errorf("некорректный URL webhook: %q (ожидается http:// или https://)", hook.URL)
+	}
+
+	for name := range hook.Headers {
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, " :\r\n") {
+			return i18n.Errorf("некорректное имя заголовка: %q", name)
+		}
+	}
+
+	if hook.PayloadTemplate != "" {
+		if _, err := template.New(hook.Name).Funcs(templateFuncs).Parse(hook.PayloadTemplate); err != nil {
+			return i18n.Errorf("ошибка в шаблоне тела webhook: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Sign возвращает значение заголовка X-Aegis-Signature для тела запроса
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBody формирует тело запроса: по шаблону хука или событие в формате JSON
+func webhookBody(hook models.Hook, event *models.HookEvent) ([]byte, error) {
+	if hook.PayloadTemplate == "" {
+		return eventPayload(hook, event)
+	}
+
+	tmpl, err := template.New(hook.Name).Funcs(templateFuncs).Parse(hook.PayloadTemplate)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка в шаблоне тела webhook: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return nil, i18n.Errorf("ошибка заполнения шаблона тела webhook: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// deliverWebhook отправляет событие на URL хука, повторяя попытки с экспоненциальной задержкой.
+// Если все попытки неудачны, событие сохраняется в таблице недоставленных
+func (m *Manager) deliverWebhook(hook models.Hook, event *models.HookEvent, execution *models.HookExecution) error {
+	body, err := webhookBody(hook, event)
+	if err != nil {
+		execution.ExitCode = -1
+		return err
+	}
+
+	var lastErr error
+	attempts := 0
+	for attempts < webhookMaxAttempts {
+		attempts++
+
+		statusCode, response, err := postWebhook(hook, execution.ID, body)
+		execution.ExitCode = statusCode
+		execution.Output = response
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		// Ответы 4xx, кроме 408 и 429, означают ошибку в запросе: повтор не поможет
+		if !retryableStatus(statusCode) || attempts == webhookMaxAttempts {
+			break
+		}
+
+		backoff := webhookBackoff(attempts)
+		m.logger.WithFields(logrus.Fields{
+			"execution_id": execution.ID,
+			"hook_id":      hook.ID,
+			"attempt":      attempts,
+			"retry_in":     backoff.String(),
+			"error":        err,
+		}).Warn("Webhook delivery failed, retrying")
+
+		m.sleep(backoff)
+	}
+
+	m.saveDeadLetter(hook, event, body, attempts, execution.ExitCode, lastErr)
+	return i18n.Errorf("доставка не удалась после %d попыток: %w", attempts, lastErr)
+}
+
+// webhookBackoff возвращает задержку перед повтором после неудачной попытки attempt (с 1):
+// задержка удваивается с каждой попыткой, но не превышает webhookMaxBackoff
+func webhookBackoff(attempt int) time.Duration {
+	backoff := webhookInitialBackoff
+	for i := 1; i < attempt && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > webhookMaxBackoff {
+		backoff = webhookMaxBackoff
+	}
+	return backoff
+}
+
+// postWebhook выполняет одну попытку доставки и возвращает код ответа (0 при ошибке соединения)
+func postWebhook(hook models.Hook, deliveryID string, body []byte) (int, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(hook.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", i18n.Errorf("ошибка создания запроса: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "aegis-agent")
+	for name, value := range hook.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set(EventHeader, hook.Event)
+	req.Header.Set(DeliveryHeader, deliveryID)
+	if hook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(hook.Secret, body))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, "", i18n.Errorf("ошибка отправки запроса: %w", err)
+	}
+	defer resp.Body.Close()
+
+	response, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, string(response), i18n.Errorf("сервер вернул статус %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(response), nil
+}
+
+// retryableStatus проверяет, имеет ли смысл повторить запрос с таким кодом ответа
+func retryableStatus(statusCode int) bool {
+	return statusCode == 0 ||
+		statusCode == http.StatusRequestTimeout ||
+		statusCode == http.StatusTooManyRequests ||
+		statusCode >= 500
+}
+
+// saveDeadLetter сохраняет недоставленное событие для последующего разбора
+func (m *Manager) saveDeadLetter(hook models.Hook, event *models.HookEvent, body []byte, attempts, statusCode int, deliveryErr error) {
+	letter := models.HookDeadLetter{
+		ID:         uuid.New().String(),
+		HookID:     hook.ID,
+		HookName:   hook.Name,
+		Event:      event.Event,
+		ScanID:     event.Scan.ID,
+		URL:        hook.URL,
+		Payload:    string(body),
+		Attempts:   attempts,
+		StatusCode: statusCode,
+		LastError:  deliveryErr.Error(),
+		CreatedAt:  time.Now(),
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"hook_id":     hook.ID,
+		"hook_name":   hook.Name,
+		"url":         hook.URL,
+		"attempts":    attempts,
+		"status_code": statusCode,
+		"error":       deliveryErr,
+	}).Error("Webhook moved to dead letters")
+
+	if m.store == nil {
+		return
+	}
+	if err := m.store.AddHookDeadLetter(&letter); err != nil {
+		m.logger.WithError(err).WithField("hook_id", hook.ID).Error("Failed to save dead letter")
+	}
+}
@@ -0,0 +1,282 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/models"
+)
+
+// memoryStore хранит историю выполнения и недоставленные события в памяти
+type memoryStore struct {
+	mu          sync.Mutex
+	executions  []models.HookExecution
+	deadLetters []models.HookDeadLetter
+}
+
+func (s *memoryStore) AddHookExecution(execution *models.HookExecution) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.executions = append(s.executions, *execution)
+	return nil
+}
+
+func (s *memoryStore) ListHookExecutions(hookID, scanID string) ([]models.HookExecution, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.HookExecution(nil), s.executions...), nil
+}
+
+func (s *memoryStore) AddHookDeadLetter(letter *models.HookDeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadLetters = append(s.deadLetters, *letter)
+	return nil
+}
+
+func (s *memoryStore) ListHookDeadLetters(hookID string) ([]models.HookDeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.HookDeadLetter(nil), s.deadLetters...), nil
+}
+
+// webhookRequest - запрос, полученный тестовым сервером
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookServer запускает сервер, который отвечает кодами statuses по очереди
+// (последний код повторяется) и запоминает полученные запросы
+func webhookServer(t *testing.T, statuses ...int) (*httptest.Server, *[]webhookRequest) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		requests []webhookRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		requests = append(requests, webhookRequest{header: r.Header.Clone(), body: body})
+		status := statuses[len(statuses)-1]
+		if len(requests) <= len(statuses) {
+			status = statuses[len(requests)-1]
+		}
+		mu.Unlock()
+
+		w.WriteHeader(status)
+		io.WriteString(w, http.StatusText(status))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// newTestManager создает менеджер, который не ждет между попытками, а запоминает задержки
+func newTestManager(store ExecutionStore) (*Manager, *[]time.Duration) {
+	m := NewManager(nil, store)
+	m.logger.SetOutput(io.Discard)
+
+	var delays []time.Duration
+	m.sleep = func(d time.Duration) { delays = append(delays, d) }
+	return m, &delays
+}
+
+func testWebhookHook(url string) models.Hook {
+	return models.Hook{
+		ID:             "hook-1",
+		Name:           "SIEM",
+		Event:          models.HookEventScanComplete,
+		Type:           models.HookTypeWebhook,
+		URL:            url,
+		Secret:         "s3cr3t",
+		Headers:        map[string]string{"Authorization": "Bearer TOKEN"},
+		TimeoutSeconds: 5,
+		Enabled:        true,
+	}
+}
+
+func testHookEvent() *models.HookEvent {
+	return &models.HookEvent{
+		Version: 1,
+		Event:   models.HookEventScanComplete,
+		Host:    models.HookEventHost{Hostname: "node-1"},
+		Scan:    models.HookEventScan{ID: "scan-1"},
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"on_scan_complete"}`)
+
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	mac.Write(body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("s3cr3t", body); got != want {
+		t.Errorf("Sign = %q, ожидается %q", got, want)
+	}
+	if Sign("other", body) == want {
+		t.Error("подпись не зависит от секрета")
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second}
+	for i, w := range want {
+		if got := webhookBackoff(i + 1); got != w {
+			t.Errorf("webhookBackoff(%d) = %s, ожидается %s", i+1, got, w)
+		}
+	}
+}
+
+func TestDeliverWebhookSuccess(t *testing.T) {
+	server, requests := webhookServer(t, http.StatusOK)
+	m, delays := newTestManager(&memoryStore{})
+	hook, event := testWebhookHook(server.URL), testHookEvent()
+
+	execution := models.HookExecution{ID: "exec-1"}
+	if err := m.deliverWebhook(hook, event, &execution); err != nil {
+		t.Fatalf("deliverWebhook: %v", err)
+	}
+	if execution.ExitCode != http.StatusOK || execution.Output != "OK" {
+		t.Errorf("выполнение: код %d, ответ %q", execution.ExitCode, execution.Output)
+	}
+	if len(*requests) != 1 || len(*delays) != 0 {
+		t.Fatalf("запросов %d, задержек %d, ожидается 1 и 0", len(*requests), len(*delays))
+	}
+
+	req := (*requests)[0]
+	for name, want := range map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": "Bearer TOKEN",
+		EventHeader:     models.HookEventScanComplete,
+		DeliveryHeader:  "exec-1",
+		SignatureHeader: Sign("s3cr3t", req.body),
+	} {
+		if got := req.header.Get(name); got != want {
+			t.Errorf("заголовок %s = %q, ожидается %q", name, got, want)
+		}
+	}
+
+	var received models.HookEvent
+	if err := json.Unmarshal(req.body, &received); err != nil {
+		t.Fatalf("тело запроса не JSON: %v", err)
+	}
+	if received.Event != event.Event || received.Scan.ID != "scan-1" || received.Host.Hostname != "node-1" {
+		t.Errorf("тело запроса: %s", req.body)
+	}
+}
+
+func TestDeliverWebhookRetriesServerErrors(t *testing.T) {
+	server, requests := webhookServer(t, http.StatusBadGateway, http.StatusTooManyRequests, http.StatusRequestTimeout, http.StatusOK)
+	store := &memoryStore{}
+	m, delays := newTestManager(store)
+
+	execution := models.HookExecution{ID: "exec-1"}
+	if err := m.deliverWebhook(testWebhookHook(server.URL), testHookEvent(), &execution); err != nil {
+		t.Fatalf("deliverWebhook: %v", err)
+	}
+	if len(*requests) != 4 {
+		t.Errorf("запросов %d, ожидается 4", len(*requests))
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	if len(*delays) != len(want) {
+		t.Fatalf("задержки %v, ожидается %v", *delays, want)
+	}
+	for i := range want {
+		if (*delays)[i] != want[i] {
+			t.Errorf("задержки %v, ожидается %v", *delays, want)
+			break
+		}
+	}
+
+	// Подпись и ID доставки одинаковы во всех попытках
+	first := (*requests)[0]
+	for _, req := range (*requests)[1:] {
+		if req.header.Get(DeliveryHeader) != first.header.Get(DeliveryHeader) ||
+			req.header.Get(SignatureHeader) != first.header.Get(SignatureHeader) {
+			t.Error("повторная попытка отличается от первой")
+		}
+	}
+	if len(store.deadLetters) != 0 {
+		t.Errorf("доставленное событие попало в недоставленные: %+v", store.deadLetters)
+	}
+}
+
+func TestDeliverWebhookStopsOnClientError(t *testing.T) {
+	server, requests := webhookServer(t, http.StatusBadRequest)
+	store := &memoryStore{}
+	m, delays := newTestManager(store)
+
+	execution := models.HookExecution{ID: "exec-1"}
+	if err := m.deliverWebhook(testWebhookHook(server.URL), testHookEvent(), &execution); err == nil {
+		t.Fatal("ошибка доставки не возвращена")
+	}
+	if len(*requests) != 1 || len(*delays) != 0 {
+		t.Errorf("запросов %d, задержек %d: ответ 4xx не должен повторяться", len(*requests), len(*delays))
+	}
+	if execution.ExitCode != http.StatusBadRequest {
+		t.Errorf("код выполнения %d, ожидается %d", execution.ExitCode, http.StatusBadRequest)
+	}
+	if len(store.deadLetters) != 1 || store.deadLetters[0].Attempts != 1 {
+		t.Fatalf("недоставленные события: %+v", store.deadLetters)
+	}
+}
+
+func TestDeliverWebhookDeadLetter(t *testing.T) {
+	server, requests := webhookServer(t, http.StatusServiceUnavailable)
+	store := &memoryStore{}
+	m, delays := newTestManager(store)
+	hook := testWebhookHook(server.URL)
+
+	execution := models.HookExecution{ID: "exec-1"}
+	if err := m.deliverWebhook(hook, testHookEvent(), &execution); err == nil {
+		t.Fatal("ошибка доставки не возвращена")
+	}
+	if len(*requests) != webhookMaxAttempts {
+		t.Errorf("запросов %d, ожидается %d", len(*requests), webhookMaxAttempts)
+	}
+	// Ожидания после последней попытки нет
+	if len(*delays) != webhookMaxAttempts-1 {
+		t.Errorf("задержки %v", *delays)
+	}
+
+	if len(store.deadLetters) != 1 {
+		t.Fatalf("недоставленных событий %d, ожидается 1", len(store.deadLetters))
+	}
+	letter := store.deadLetters[0]
+	if letter.HookID != hook.ID || letter.HookName != hook.Name || letter.URL != hook.URL ||
+		letter.Event != models.HookEventScanComplete || letter.ScanID != "scan-1" {
+		t.Errorf("недоставленное событие: %+v", letter)
+	}
+	if letter.Attempts != webhookMaxAttempts || letter.StatusCode != http.StatusServiceUnavailable || letter.LastError == "" {
+		t.Errorf("попыток %d, код %d, ошибка %q", letter.Attempts, letter.StatusCode, letter.LastError)
+	}
+	if letter.Payload != string((*requests)[0].body) {
+		t.Errorf("сохраненное тело %q отличается от отправленного %q", letter.Payload, (*requests)[0].body)
+	}
+}
+
+func TestDeliverWebhookPayloadTemplate(t *testing.T) {
+	server, requests := webhookServer(t, http.StatusOK)
+	m, _ := newTestManager(nil)
+	hook := testWebhookHook(server.URL)
+	hook.PayloadTemplate = `{"text":"{{.Event}} на {{.Host.Hostname}}","scan":{{json .Scan.ID}}}`
+
+	if err := m.deliverWebhook(hook, testHookEvent(), &models.HookExecution{ID: "exec-1"}); err != nil {
+		t.Fatalf("deliverWebhook: %v", err)
+	}
+	want := `{"text":"on_scan_complete на node-1","scan":"scan-1"}`
+	if got := string((*requests)[0].body); got != want {
+		t.Errorf("тело %q, ожидается %q", got, want)
+	}
+}
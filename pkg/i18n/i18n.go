@@ -0,0 +1,157 @@
+// Package i18n переводит сообщения для пользователя. Исходный язык сообщений - русский:
+// строка в коде одновременно служит ключом каталога, поэтому непереведенное сообщение
+// выводится по-русски. Каталоги других языков хранятся в locales/<язык>.json и
+// сопоставляют исходной строке ее перевод. Строки формата переводятся целиком,
+// вместе с глаголами %s, %d и т.д., которые должны сохраниться в переводе
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Поддерживаемые языки
+const (
+	Russian = "ru"
+	English = "en"
+)
+
+// DefaultLanguage - язык исходных сообщений, используется без настройки и переменных LANG
+const DefaultLanguage = Russian
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var (
+	mu       sync.RWMutex
+	language = DefaultLanguage
+	catalog  map[string]string // nil - сообщения выводятся без перевода
+)
+
+func init() {
+	// До загрузки конфигурации язык определяется по окружению, чтобы справка
+	// и ошибки чтения конфигурации выводились на языке пользователя
+	SetLanguage(FromEnvironment())
+}
+
+// FromEnvironment определяет язык по переменным LC_ALL, LC_MESSAGES и LANG в порядке
+// приоритета POSIX. Русская локаль или ее отсутствие (C, POSIX) дают русский язык,
+// любая другая - английский
+func FromEnvironment() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		lang := strings.ToLower(value)
+		if i := strings.IndexAny(lang, "_.@-"); i >= 0 {
+			lang = lang[:i]
+		}
+		switch lang {
+		case "c", "posix":
+			return DefaultLanguage
+		case Russian:
+			return Russian
+		default:
+			return English
+		}
+	}
+	return DefaultLanguage
+}
+
+// Languages возвращает поддерживаемые языки
+func Languages() []string {
+	languages := []string{Russian}
+	entries, _ := locales.ReadDir("locales")
+	for _, entry := range entries {
+		languages = append(languages, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	return languages
+}
+
+// SetLanguage выбирает язык сообщений. Допускается код языка (en) или локаль (en_US.UTF-8),
+// пустое значение означает язык окружения
+func SetLanguage(lang string) error {
+	if lang == "" {
+		lang = FromEnvironment()
+	}
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "_.@-"); i >= 0 {
+		lang = lang[:i]
+	}
+
+	var messages map[string]string
+	if lang != DefaultLanguage {
+		data, err := locales.ReadFile("locales/" + lang + ".json")
+		if err != nil {
+			return Errorf("неподдерживаемый язык: %s (доступны: %s)", lang, strings.Join(Languages(), ", "))
+		}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return Errorf("ошибка в каталоге сообщений %s: %w", lang, err)
+		}
+	}
+
+	mu.Lock()
+	language, catalog = lang, messages
+	mu.Unlock()
+	return nil
+}
+
+// Language возвращает текущий язык сообщений
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return language
+}
+
+// T переводит сообщение на текущий язык
+func T(message string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if translated, ok := catalog[message]; ok && translated != "" {
+		return translated
+	}
+	return message
+}
+
+// N отмечает сообщение для каталога, не переводя его. Так хранятся строки, которые попадают
+// в машиночитаемый вывод (JSON) без перевода и переводятся только при показе пользователю
+func N(message string) string {
+	return message
+}
+
+// Sprintf переводит строку формата и подставляет в нее аргументы
+func Sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Errorf создает ошибку с переведенной строкой формата, %w сохраняет цепочку ошибок
+func Errorf(format string, args ...interface{}) error {
+	return fmt.Errorf(T(format), args...)
+}
+
+// New создает ошибку с переведенным сообщением
+func New(message string) error {
+	return errors.New(T(message))
+}
+
+// Printf выводит в stdout переведенную строку формата
+func Printf(format string, args ...interface{}) {
+	fmt.Printf(T(format), args...)
+}
+
+// Println выводит в stdout переведенное сообщение и аргументы через пробел
+func Println(message string, args ...interface{}) {
+	fmt.Println(append([]interface{}{T(message)}, args...)...)
+}
+
+// Fprintf выводит в w переведенную строку формата
+func Fprintf(w io.Writer, format string, args ...interface{}) {
+	fmt.Fprintf(w, T(format), args...)
+}
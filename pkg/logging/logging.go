@@ -0,0 +1,174 @@
+// Package logging настраивает журнал CLI и агента по параметрам log_level, log_format,
+// log_file, log_max_size_mb и log_max_backups из конфигурации
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/sirupsen/logrus"
+)
+
+// Форматы журнала
+const (
+	FormatText     = "text"
+	FormatJSON     = "json"
+	FormatJournald = "journald" // Приоритет syslog в префиксе <N>, без времени: его добавляет journald
+)
+
+// Formats содержит все поддерживаемые форматы
+var Formats = []string{FormatText, FormatJSON, FormatJournald}
+
+// OutputStderr - значение log_file для вывода журнала в stderr
+const OutputStderr = "stderr"
+
+// Options - параметры журнала
+type Options struct {
+	Level      string // trace, debug, info, warn, error
+	Format     string // text, json, journald; пусто - journald под systemd при выводе в stderr, иначе text
+	File       string // Путь к файлу журнала; пусто или stderr - вывод в stderr
+	MaxSizeMB  int    // Размер файла, при превышении которого он ротируется; 0 - без ротации
+	MaxBackups int    // Количество сохраняемых ротированных файлов
+	Verbose    bool   // Уровень debug и копия журнала в stderr
+}
+
+// New создает журнал с указанными параметрами. Файл журнала закрывается функцией Close
+func New(opts Options) (*logrus.Logger, error) {
+	level := logrus.InfoLevel
+	if opts.Level != "" {
+		parsed, err := logrus.ParseLevel(opts.Level)
+		if err != nil {
+			return nil, i18n.Errorf("неизвестный уровень журнала: %s", opts.Level)
+		}
+		level = parsed
+	}
+	if opts.Verbose && level < logrus.DebugLevel {
+		level = logrus.DebugLevel
+	}
+
+	toStderr := opts.File == "" || opts.File == OutputStderr
+	format := opts.Format
+	if format == "" {
+		format = FormatText
+		// systemd передает JOURNAL_STREAM, если stdout и stderr службы подключены к журналу
+		if toStderr && os.Getenv("JOURNAL_STREAM") != "" {
+			format = FormatJournald
+		}
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(level)
+	switch format {
+	case FormatText:
+		logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true, DisableColors: !toStderr})
+	case FormatJSON:
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	case FormatJournald:
+		logger.SetFormatter(&journaldFormatter{})
+	default:
+		return nil, i18n.Errorf("неизвестный формат журнала: %s (допустимо: %s)", format, strings.Join(Formats, ", "))
+	}
+
+	if toStderr {
+		logger.SetOutput(os.Stderr)
+		return logger, nil
+	}
+
+	file, err := OpenRotatingFile(ExpandHome(opts.File), int64(opts.MaxSizeMB)*1024*1024, opts.MaxBackups)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Verbose {
+		logger.SetOutput(&teeWriter{file: file, copy: os.Stderr})
+	} else {
+		logger.SetOutput(file)
+	}
+	return logger, nil
+}
+
+// Close закрывает файл журнала, если журнал ведется в файл
+func Close(logger *logrus.Logger) {
+	if closer, ok := logger.Out.(io.Closer); ok && logger.Out != os.Stderr && logger.Out != os.Stdout {
+		closer.Close()
+	}
+}
+
+// DetachStderr прекращает вывод журнала в stderr, например на время работы TUI.
+// Запись в файл журнала продолжается
+func DetachStderr(logger *logrus.Logger) {
+	switch out := logger.Out.(type) {
+	case *teeWriter:
+		logger.SetOutput(out.file)
+	case *os.File:
+		if out == os.Stderr {
+			logger.SetOutput(io.Discard)
+		}
+	}
+}
+
+// ExpandHome заменяет ~ в начале пути домашним каталогом пользователя
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// teeWriter пишет журнал в файл и копирует его в stderr
+type teeWriter struct {
+	file *RotatingFile
+	copy io.Writer
+}
+
+func (w *teeWriter) Write(p []byte) (int, error) {
+	w.copy.Write(p)
+	return w.file.Write(p)
+}
+
+func (w *teeWriter) Close() error {
+	return w.file.Close()
+}
+
+// journaldFormatter выводит запись одной строкой с приоритетом syslog в префиксе <N>,
+// который journald распознает в выводе служб (sd-daemon). Время не выводится
+type journaldFormatter struct{}
+
+// journaldPriorities - приоритеты syslog для уровней журнала
+var journaldPriorities = map[logrus.Level]int{
+	logrus.PanicLevel: 2,
+	logrus.FatalLevel: 2,
+	logrus.ErrorLevel: 3,
+	logrus.WarnLevel:  4,
+	logrus.InfoLevel:  6,
+	logrus.DebugLevel: 7,
+	logrus.TraceLevel: 7,
+}
+
+func (f *journaldFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<%d>%s", journaldPriorities[entry.Level], strings.ReplaceAll(entry.Message, "\n", " "))
+
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := fmt.Sprint(entry.Data[key])
+		if strings.ContainsAny(value, " \"=\n") {
+			value = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(&b, " %s=%s", key, value)
+	}
+	b.WriteByte('\n')
+	return []byte(b.String()), nil
+}
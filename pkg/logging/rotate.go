@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+)
+
+// RotatingFile - файл журнала с ротацией по размеру. При превышении maxSize файл
+// переименовывается в <путь>.1, предыдущие копии сдвигаются до <путь>.<maxBackups>
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64 // 0 - без ротации
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// OpenRotatingFile открывает файл журнала для дописывания, создавая каталог при необходимости
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, i18n.Errorf("ошибка создания каталога журнала: %w", err)
+	}
+	f := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return i18n.Errorf("ошибка открытия файла журнала: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return i18n.Errorf("ошибка открытия файла журнала: %w", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// Write дописывает запись, предварительно ротируя файл, если запись не помещается.
+// Если ротация не удалась, но файл удалось открыть снова, запись дописывается в него:
+// ротация повторится при следующей записи
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil && f.file == nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate сдвигает копии журнала и начинает новый файл. Если текущий файл не удалось
+// переименовать или удалить, он открывается снова для дописывания
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	var err error
+	if f.maxBackups > 0 {
+		os.Remove(f.backupPath(f.maxBackups))
+		for i := f.maxBackups - 1; i >= 1; i-- {
+			os.Rename(f.backupPath(i), f.backupPath(i+1))
+		}
+		err = os.Rename(f.path, f.backupPath(1))
+	} else {
+		err = os.Remove(f.path)
+	}
+	if openErr := f.open(); openErr != nil {
+		return openErr
+	}
+	if err != nil {
+		return i18n.Errorf("ошибка ротации файла журнала: %w", err)
+	}
+	return nil
+}
+
+func (f *RotatingFile) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", f.path, n)
+}
+
+// Close закрывает файл журнала
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// scrape опрашивает реестр через HTTP так же, как это делает Prometheus
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /metrics: статус %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, ожидается %q", got, ContentType)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("чтение ответа: %v", err)
+	}
+	return string(body)
+}
+
+// expectLines проверяет, что вывод содержит строки в указанном порядке
+func expectLines(t *testing.T, body string, lines ...string) {
+	t.Helper()
+
+	rest := body
+	for _, line := range lines {
+		i := strings.Index(rest, line+"\n")
+		if i < 0 {
+			t.Fatalf("нет строки %q после предыдущих в выводе:\n%s", line, body)
+		}
+		rest = rest[i+len(line)+1:]
+	}
+}
+
+func TestCounterVec(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounterVec("test_requests_total", "Requests.", "method", "code")
+	requests.Inc("GET", "200")
+	requests.Inc("GET", "200")
+	requests.Add(3, "POST", "500")
+	requests.Add(-1, "POST", "500") // Счетчик не убывает
+
+	expectLines(t, scrape(t, r),
+		"# HELP test_requests_total Requests.",
+		"# TYPE test_requests_total counter",
+		`test_requests_total{method="GET",code="200"} 2`,
+		`test_requests_total{method="POST",code="500"} 3`,
+	)
+}
+
+func TestGauge(t *testing.T) {
+	r := NewRegistry()
+	running := r.NewGauge("test_running", "Running.")
+	running.Set(5)
+	running.Inc()
+	running.Dec()
+	running.Dec()
+	running.Add(0.5)
+
+	expectLines(t, scrape(t, r),
+		"# HELP test_running Running.",
+		"# TYPE test_running gauge",
+		"test_running 4.5",
+	)
+}
+
+func TestGaugeFunc(t *testing.T) {
+	r := NewRegistry()
+	r.NewGaugeFunc("test_known", "Known.", func() (float64, bool) { return 1700000000, true })
+	r.NewGaugeFunc("test_unknown", "Unknown.", func() (float64, bool) { return 0, false })
+
+	body := scrape(t, r)
+	expectLines(t, body,
+		"# TYPE test_known gauge",
+		"test_known 1.7e+09",
+		"# HELP test_unknown Unknown.",
+		"# TYPE test_unknown gauge",
+	)
+	if strings.Contains(body, "test_unknown 0") {
+		t.Errorf("неизвестное значение не должно выводиться:\n%s", body)
+	}
+}
+
+func TestHistogramVec(t *testing.T) {
+	r := NewRegistry()
+	duration := r.NewHistogramVec("test_duration_seconds", "Duration.", []float64{0.1, 1, 10}, "route")
+	duration.Observe(0.05, "/scan")
+	duration.Observe(0.1, "/scan") // Граница входит в корзину
+	duration.Observe(5, "/scan")
+	duration.Observe(20, "/scan")
+
+	expectLines(t, scrape(t, r),
+		"# HELP test_duration_seconds Duration.",
+		"# TYPE test_duration_seconds histogram",
+		`test_duration_seconds_bucket{route="/scan",le="0.1"} 2`,
+		`test_duration_seconds_bucket{route="/scan",le="1"} 2`,
+		`test_duration_seconds_bucket{route="/scan",le="10"} 3`,
+		`test_duration_seconds_bucket{route="/scan",le="+Inf"} 4`,
+		`test_duration_seconds_sum{route="/scan"} 25.15`,
+		`test_duration_seconds_count{route="/scan"} 4`,
+	)
+}
+
+func TestEscaping(t *testing.T) {
+	r := NewRegistry()
+	errors := r.NewCounterVec("test_errors_total", "Errors with \\ and\nnewline.", "message")
+	errors.Inc("say \"hi\"\\\n")
+
+	expectLines(t, scrape(t, r),
+		`# HELP test_errors_total Errors with \\ and\nnewline.`,
+		`test_errors_total{message="say \"hi\"\\\n"} 1`,
+	)
+}
+
+func TestMetricsSortedByName(t *testing.T) {
+	r := NewRegistry()
+	r.NewGauge("test_b", "B.").Set(1)
+	r.NewGauge("test_a", "A.").Set(1)
+
+	body := scrape(t, r)
+	if strings.Index(body, "test_a") > strings.Index(body, "test_b") {
+		t.Errorf("метрики должны быть упорядочены по имени:\n%s", body)
+	}
+}
+
+func TestDuplicateRegistrationPanics(t *testing.T) {
+	r := NewRegistry()
+	r.NewGauge("test_dup", "Dup.")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("повторная регистрация метрики должна вызывать панику")
+		}
+	}()
+	r.NewGauge("test_dup", "Dup.")
+}
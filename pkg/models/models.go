@@ -1,7 +1,14 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
 )
 
 // Host представляет хост с агентом
@@ -15,19 +22,73 @@ type Host struct {
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 	Description string    `json:"description" db:"description"`
+	Posture     string    `json:"posture,omitempty" db:"posture"`       // Последний снимок HostPosture в JSON
+	AgentInfo   string    `json:"agent_info,omitempty" db:"agent_info"` // Последние сведения AgentInfo в JSON
 }
 
-// Container представляет Docker-контейнер
+// Container представляет контейнер Docker, Podman или containerd
 type Container struct {
-	ID        string    `json:"id" db:"id"`
-	HostID    string    `json:"host_id" db:"host_id"`
-	Name      string    `json:"name" db:"name"`
-	Image     string    `json:"image" db:"image"`
-	Status    string    `json:"status" db:"status"`
+	ID      string `json:"id" db:"id"`
+	HostID  string `json:"host_id" db:"host_id"`
+	Name    string `json:"name" db:"name"`
+	Image   string `json:"image" db:"image"`
+	Status  string `json:"status" db:"status"`
+	Runtime string `json:"runtime" db:"runtime"` // docker, podman, containerd
+
+	// Контекст Kubernetes, определенный по меткам контейнера (пусто вне Kubernetes)
+	PodName      string `json:"pod_name,omitempty" db:"pod_name"`
+	PodNamespace string `json:"pod_namespace,omitempty" db:"pod_namespace"`
+	WorkloadKind string `json:"workload_kind,omitempty" db:"workload_kind"` // Deployment, StatefulSet, DaemonSet, Job, Pod
+	WorkloadName string `json:"workload_name,omitempty" db:"workload_name"`
+
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// Типы рабочих нагрузок Kubernetes
+const (
+	WorkloadDeployment  = "Deployment"
+	WorkloadStatefulSet = "StatefulSet"
+	WorkloadDaemonSet   = "DaemonSet"
+	WorkloadJob         = "Job"
+	WorkloadPod         = "Pod" // Pod без контроллера (в том числе статический)
+)
+
+// Workload возвращает рабочую нагрузку контейнера в виде namespace/Kind/name
+// или пустую строку, если контейнер запущен вне Kubernetes
+func (c *Container) Workload() string {
+	if c.PodName == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%s", c.PodNamespace, c.WorkloadKind, c.WorkloadName)
+}
+
+// ImageRepository возвращает репозиторий образа без тега и дайджеста
+func ImageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// ImageTag возвращает тег образа. Образ без тега и дайджеста имеет тег latest
+func ImageTag(image string) string {
+	digest := strings.Contains(image, "@")
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	if digest {
+		return ""
+	}
+	return "latest"
+}
+
 // Scan представляет процесс сканирования контейнера
 type Scan struct {
 	ID          string    `json:"id" db:"id"`
@@ -38,6 +99,7 @@ type Scan struct {
 	FinishedAt  time.Time `json:"finished_at,omitempty" db:"finished_at"`
 	ResultPath  string    `json:"result_path,omitempty" db:"result_path"`
 	ErrorMsg    string    `json:"error_msg,omitempty" db:"error_msg"`
+	ImageDigest string    `json:"image_digest,omitempty" db:"image_digest"` // Дайджест манифеста образа, для образов вне реестра - ID образа
 }
 
 // Vulnerability представляет найденную уязвимость
@@ -57,33 +119,495 @@ type Vulnerability struct {
 	DiscoveredAt     time.Time `json:"discovered_at" db:"discovered_at"`
 }
 
+// Finding представляет находку сканирования, не являющуюся уязвимостью пакета:
+// встроенный в образ секрет или ошибку конфигурации
+type Finding struct {
+	ID           string    `json:"id" db:"id"`
+	ScanID       string    `json:"scan_id" db:"scan_id"`
+	ContainerID  string    `json:"container_id" db:"container_id"`
+	HostID       string    `json:"host_id" db:"host_id"`
+	Kind         string    `json:"kind" db:"kind"`       // secret, misconfig
+	RuleID       string    `json:"rule_id" db:"rule_id"` // ID правила Trivy (aws-access-key-id, DS002 и т.д.)
+	Severity     string    `json:"severity" db:"severity"`
+	Title        string    `json:"title" db:"title"`
+	Description  string    `json:"description,omitempty" db:"description"`
+	Target       string    `json:"target" db:"target"`                   // Файл или объект, в котором обнаружена находка
+	Location     string    `json:"location,omitempty" db:"location"`     // Фрагмент файла (секреты маскируются Trivy)
+	StartLine    int       `json:"start_line,omitempty" db:"start_line"` // Строки файла, 0 - неизвестны
+	EndLine      int       `json:"end_line,omitempty" db:"end_line"`
+	Resolution   string    `json:"resolution,omitempty" db:"resolution"` // Рекомендация по исправлению
+	DiscoveredAt time.Time `json:"discovered_at" db:"discovered_at"`
+}
+
+// LocationText возвращает расположение находки для вывода: фрагмент и строки файла
+func (f *Finding) LocationText() string {
+	if f.StartLine <= 0 {
+		return f.Location
+	}
+	lines := i18n.Sprintf("строки %d-%d", f.StartLine, f.EndLine)
+	if f.Location == "" {
+		return lines
+	}
+	return f.Location + " (" + lines + ")"
+}
+
+// Виды находок
+const (
+	FindingKindSecret    = "secret"
+	FindingKindMisconfig = "misconfig"
+)
+
+// AuditFinding представляет нарушение правила аудита конфигурации запущенного контейнера
+type AuditFinding struct {
+	ID            string    `json:"id" db:"id"`
+	HostID        string    `json:"host_id" db:"host_id"`
+	ContainerID   string    `json:"container_id" db:"container_id"`
+	ContainerName string    `json:"container_name" db:"container_name"`
+	RuleID        string    `json:"rule_id" db:"rule_id"` // Номер рекомендации CIS Docker Benchmark (5.4 и т.д.)
+	Severity      string    `json:"severity" db:"severity"`
+	Title         string    `json:"title" db:"title"`
+	Details       string    `json:"details" db:"details"` // Значение параметра, нарушающее правило
+	Remediation   string    `json:"remediation" db:"remediation"`
+	AuditedAt     time.Time `json:"audited_at" db:"audited_at"`
+}
+
+// AuditResponse представляет результат аудита контейнеров агентом
+type AuditResponse struct {
+	AuditedAt  time.Time      `json:"audited_at"`
+	Containers int            `json:"containers"` // Количество проверенных контейнеров
+	Findings   []AuditFinding `json:"findings"`
+}
+
+// Статусы проверок состояния хоста
+const (
+	PostureStatusPass = "pass"
+	PostureStatusWarn = "warn"
+	PostureStatusFail = "fail"
+)
+
+// PostureCheck представляет результат одной проверки настроек Docker на хосте
+type PostureCheck struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Status  string `json:"status"` // pass, warn, fail
+	Details string `json:"details"`
+}
+
+// HostPosture представляет снимок состояния безопасности Docker на хосте
+type HostPosture struct {
+	CheckedAt     time.Time      `json:"checked_at"`
+	DockerVersion string         `json:"docker_version"`
+	APIVersion    string         `json:"api_version"`
+	OS            string         `json:"os"`
+	KernelVersion string         `json:"kernel_version"`
+	Checks        []PostureCheck `json:"checks"`
+}
+
+// AgentInfo описывает агент: версии компонентов, состояние БД уязвимостей trivy,
+// загрузку очереди сканирований и поддерживаемые возможности (GET /info)
+type AgentInfo struct {
+	CollectedAt      time.Time  `json:"collected_at"`
+	Version          string     `json:"version"`
+	GoVersion        string     `json:"go_version"`
+	Runtime          string     `json:"runtime"`                   // docker, podman, containerd
+	RuntimeVersion   string     `json:"runtime_version,omitempty"` // Версия Docker Engine или Podman
+	TrivyVersion     string     `json:"trivy_version,omitempty"`
+	TrivyDBUpdatedAt *time.Time `json:"trivy_db_updated_at,omitempty"` // Время обновления БД уязвимостей
+	ScanConcurrency  int        `json:"scan_concurrency"`
+	ScansRunning     int        `json:"scans_running"`
+	ScansQueued      int        `json:"scans_queued"`
+	Scanners         []string   `json:"scanners"`
+	Features         []string   `json:"features"`
+	Errors           []string   `json:"errors,omitempty"` // Сведения, которые не удалось получить
+}
+
+// TrivyDBAge возвращает возраст БД уязвимостей trivy на момент now; false - время обновления неизвестно
+func (i *AgentInfo) TrivyDBAge(now time.Time) (time.Duration, bool) {
+	if i.TrivyDBUpdatedAt == nil || i.TrivyDBUpdatedAt.IsZero() {
+		return 0, false
+	}
+	return now.Sub(*i.TrivyDBUpdatedAt), true
+}
+
+// Возможности агента в AgentInfo.Features
+const (
+	AgentFeatureSBOM        = "sbom"         // GET /scan/{id}/sbom
+	AgentFeatureAudit       = "audit"        // GET /audit
+	AgentFeatureHostPosture = "host_posture" // GET /host/posture, только Docker-совместимый API
+	AgentFeatureHooks       = "hooks"        // Управление хуками через API
+	AgentFeatureWebhooks    = "webhooks"     // Хуки типа webhook
+	AgentFeatureScanTimeout = "scan_timeout" // Таймаут сканирования и событие on_scan_timeout
+	AgentFeatureMetrics     = "metrics"      // GET /metrics
+)
+
 // Hook представляет пользовательский хук
 type Hook struct {
-	ID             string    `json:"id" db:"id"`
-	Name           string    `json:"name" db:"name"`
-	Event          string    `json:"event" db:"event"` // on_scan_start, on_scan_complete, on_error
-	ScriptPath     string    `json:"script_path" db:"script_path"`
-	TimeoutSeconds int       `json:"timeout_seconds" db:"timeout_seconds"`
-	Enabled        bool      `json:"enabled" db:"enabled"`
-	CreatedAt      time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+	ID             string `json:"id" db:"id" mapstructure:"id"`
+	Name           string `json:"name" db:"name" mapstructure:"name"`
+	Type           string `json:"type" db:"type" mapstructure:"type"`    // script (по умолчанию), webhook
+	Event          string `json:"event" db:"event" mapstructure:"event"` // Одно из HookEvents
+	ScriptPath     string `json:"script_path" db:"script_path" mapstructure:"script_path"`
+	TimeoutSeconds int    `json:"timeout_seconds" db:"timeout_seconds" mapstructure:"timeout_seconds"`
+	Enabled        bool   `json:"enabled" db:"enabled" mapstructure:"enabled"`
+	Payload        string `json:"payload" db:"payload" mapstructure:"payload"` // full (по умолчанию), summary
+
+	// Фильтры событий: пустое значение не ограничивает
+	HostFilter   string `json:"host_filter,omitempty" db:"host_filter" mapstructure:"host_filter"`       // Имя или ID хоста
+	ImagePattern string `json:"image_pattern,omitempty" db:"image_pattern" mapstructure:"image_pattern"` // Шаблон образа, например registry.local/*
+	MinSeverity  string `json:"min_severity,omitempty" db:"min_severity" mapstructure:"min_severity"`    // CRITICAL, HIGH, MEDIUM, LOW
+	Threshold    int    `json:"threshold,omitempty" db:"threshold" mapstructure:"threshold"`             // Минимум находок не ниже min_severity (по умолчанию 1)
+
+	// Ограничения выполнения скрипта: 0 и пустое значение - значение по умолчанию
+	RunAsUser       string      `json:"run_as_user,omitempty" db:"run_as_user" mapstructure:"run_as_user"`
+	RunAsGroup      string      `json:"run_as_group,omitempty" db:"run_as_group" mapstructure:"run_as_group"`
+	WorkDir         string      `json:"work_dir,omitempty" db:"work_dir" mapstructure:"work_dir"`
+	EnvAllowlist    HookEnvList `json:"env_allowlist,omitempty" db:"env_allowlist" mapstructure:"env_allowlist"`             // Переменные окружения агента, доступные скрипту
+	MaxOutputBytes  int         `json:"max_output_bytes,omitempty" db:"max_output_bytes" mapstructure:"max_output_bytes"`    // По умолчанию 64 КиБ
+	CPULimitSeconds int         `json:"cpu_limit_seconds,omitempty" db:"cpu_limit_seconds" mapstructure:"cpu_limit_seconds"` // RLIMIT_CPU
+	MemoryLimitMB   int         `json:"memory_limit_mb,omitempty" db:"memory_limit_mb" mapstructure:"memory_limit_mb"`       // RLIMIT_AS
+
+	// Параметры хука типа webhook
+	URL             string      `json:"url,omitempty" db:"url" mapstructure:"url"`
+	Headers         HookHeaders `json:"headers,omitempty" db:"headers" mapstructure:"headers"`
+	PayloadTemplate string      `json:"payload_template,omitempty" db:"payload_template" mapstructure:"payload_template"` // text/template; пусто - событие в JSON
+	Secret          string      `json:"secret,omitempty" db:"secret" mapstructure:"secret"`                               // Ключ подписи HMAC-SHA256
+
+	CreatedAt time.Time `json:"created_at" db:"created_at" mapstructure:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at" mapstructure:"updated_at"`
+
+	Source string `json:"source,omitempty" db:"-" mapstructure:"-"` // Только в ответе агента: config или api
 }
 
+// Источники хуков агента
+const (
+	HookSourceConfig = "config" // Задан в конфигурации агента
+	HookSourceAPI    = "api"    // Добавлен через API, например командой aegis hook push
+)
+
+// События, на которые можно подписать хук
+const (
+	HookEventScanStart          = "on_scan_start"
+	HookEventScanComplete       = "on_scan_complete"
+	HookEventError              = "on_error"
+	HookEventScanTimeout        = "on_scan_timeout"
+	HookEventCriticalFound      = "on_critical_found"
+	HookEventNewVulnerability   = "on_new_vulnerability"
+	HookEventHostOffline        = "on_host_offline"
+	HookEventHostOnline         = "on_host_online"
+	HookEventRemediationApplied = "on_remediation_applied"
+)
+
+// HookEvents содержит все поддерживаемые события хуков
+var HookEvents = []string{
+	HookEventScanStart,
+	HookEventScanComplete,
+	HookEventError,
+	HookEventScanTimeout,
+	HookEventCriticalFound,
+	HookEventNewVulnerability,
+	HookEventHostOffline,
+	HookEventHostOnline,
+	HookEventRemediationApplied,
+}
+
+// IsValidHookEvent проверяет, поддерживается ли событие хука
+func IsValidHookEvent(event string) bool {
+	for _, e := range HookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCLIHookEvent проверяет, формирует ли событие CLI, а не агент.
+// Хуки на такие события выполняются CLI и не передаются агентам
+func IsCLIHookEvent(event string) bool {
+	switch event {
+	case HookEventHostOffline, HookEventHostOnline, HookEventRemediationApplied:
+		return true
+	default:
+		return false
+	}
+}
+
+// Типы хуков
+const (
+	HookTypeScript  = "script"
+	HookTypeWebhook = "webhook"
+)
+
+// IsWebhook проверяет, доставляется ли хук HTTP-запросом
+func (h *Hook) IsWebhook() bool {
+	return h.Type == HookTypeWebhook
+}
+
+// HookHeaders содержит дополнительные HTTP-заголовки webhook. В БД хранятся в JSON
+type HookHeaders map[string]string
+
+// Value сериализует заголовки для записи в БД
+func (h HookHeaders) Value() (driver.Value, error) {
+	if len(h) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan читает заголовки из БД
+func (h *HookHeaders) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return i18n.Errorf("неподдерживаемый тип заголовков хука: %T", src)
+	}
+
+	if len(data) == 0 {
+		*h = nil
+		return nil
+	}
+	return json.Unmarshal(data, h)
+}
+
+// HookEnvList содержит имена переменных окружения. Имя с * на конце задает префикс,
+// одиночная * - все переменные. В БД хранится через запятую
+type HookEnvList []string
+
+// Value сериализует список для записи в БД
+func (l HookEnvList) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+// Scan читает список из БД
+func (l *HookEnvList) Scan(src interface{}) error {
+	var data string
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		data = v
+	case []byte:
+		data = string(v)
+	default:
+		return i18n.Errorf("неподдерживаемый тип списка переменных окружения: %T", src)
+	}
+
+	*l = ParseHookEnvList(data)
+	return nil
+}
+
+// ParseHookEnvList разбирает список имен переменных окружения через запятую
+func ParseHookEnvList(value string) HookEnvList {
+	var list HookEnvList
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			list = append(list, name)
+		}
+	}
+	return list
+}
+
+// HookDeadLetter представляет событие webhook, которое не удалось доставить после всех попыток
+type HookDeadLetter struct {
+	ID         string    `json:"id" db:"id"`
+	HookID     string    `json:"hook_id" db:"hook_id"`
+	HookName   string    `json:"hook_name" db:"hook_name"`
+	Event      string    `json:"event" db:"event"`
+	ScanID     string    `json:"scan_id" db:"scan_id"`
+	URL        string    `json:"url" db:"url"`
+	Payload    string    `json:"payload" db:"payload"`
+	Attempts   int       `json:"attempts" db:"attempts"`
+	StatusCode int       `json:"status_code" db:"status_code"` // Код ответа последней попытки, 0 - ошибка соединения
+	LastError  string    `json:"last_error" db:"last_error"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// HookListResponse представляет ответ агента со списком хуков
+type HookListResponse struct {
+	Hooks []Hook `json:"hooks"`
+}
+
+// HookDeadLetterListResponse представляет ответ агента со списком недоставленных событий
+type HookDeadLetterListResponse struct {
+	DeadLetters []HookDeadLetter `json:"dead_letters"`
+}
+
+// Объем данных события, передаваемого хуку
+const (
+	HookPayloadFull    = "full"    // Сводка и все найденные уязвимости, секреты и ошибки конфигурации
+	HookPayloadSummary = "summary" // Только сводка по серьезности
+)
+
 // HookExecution представляет выполнение хука
 type HookExecution struct {
 	ID         string    `json:"id" db:"id"`
 	HookID     string    `json:"hook_id" db:"hook_id"`
+	HookName   string    `json:"hook_name" db:"hook_name"`
+	Event      string    `json:"event" db:"event"`
+	HostID     string    `json:"host_id,omitempty" db:"host_id"` // Хост агента (заполняется CLI при загрузке истории) или хост события CLI
 	ScanID     string    `json:"scan_id" db:"scan_id"`
-	Status     string    `json:"status" db:"status"` // success, failure
+	Status     string    `json:"status" db:"status"`       // success, failure
+	ExitCode   int       `json:"exit_code" db:"exit_code"` // Для webhook - HTTP-код ответа последней попытки
+	DurationMs int64     `json:"duration_ms" db:"duration_ms"`
 	Output     string    `json:"output" db:"output"`
 	ErrorMsg   string    `json:"error_msg" db:"error_msg"`
 	StartedAt  time.Time `json:"started_at" db:"started_at"`
 	FinishedAt time.Time `json:"finished_at" db:"finished_at"`
 }
 
+// Статусы выполнения хука
+const (
+	HookStatusSuccess = "success"
+	HookStatusFailure = "failure"
+)
+
+// HookExecutionListResponse представляет ответ агента со списком выполнений хуков
+type HookExecutionListResponse struct {
+	Executions []HookExecution `json:"executions"`
+}
+
+// HookEventVersion содержит версию формата события, передаваемого хукам.
+// Увеличивается при несовместимых изменениях структуры HookEvent
+const HookEventVersion = 1
+
+// HookEvent представляет событие, которое хук получает в stdin в формате JSON
+type HookEvent struct {
+	Version         int             `json:"version"`
+	Event           string          `json:"event"`
+	Timestamp       time.Time       `json:"timestamp"`
+	Host            HookEventHost   `json:"host"`
+	Container       *Container      `json:"container,omitempty"`
+	Scan            HookEventScan   `json:"scan"`
+	Summary         ScanSummary     `json:"summary"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+	Secrets         []Finding       `json:"secrets,omitempty"`
+	Misconfigs      []Finding       `json:"misconfigurations,omitempty"`
+
+	Remediation *HookEventRemediation `json:"remediation,omitempty"` // Только для on_remediation_applied
+}
+
+// HookEventHost описывает хост агента, на котором произошло событие
+type HookEventHost struct {
+	ID       string `json:"id,omitempty"` // ID хоста в БД CLI, только для событий, которые формирует CLI
+	Hostname string `json:"hostname"`
+	Address  string `json:"address,omitempty"`
+	Runtime  string `json:"runtime,omitempty"`
+}
+
+// HookEventRemediation описывает примененную стратегию исправления
+type HookEventRemediation struct {
+	StrategyID string `json:"strategy_id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Command    string `json:"command"`
+	Output     string `json:"output,omitempty"`
+}
+
+// HookEventScan описывает сканирование, к которому относится событие
+type HookEventScan struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Scanners    []string   `json:"scanners"`
+	ImageDigest string     `json:"image_digest,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	DurationMs  int64      `json:"duration_ms"`
+
+	PreviousScanID string `json:"previous_scan_id,omitempty"` // Только для on_new_vulnerability
+}
+
+// SeverityCounts содержит количество находок по уровням серьезности
+type SeverityCounts struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
+	Unknown  int `json:"unknown"`
+	Total    int `json:"total"`
+}
+
+// Add учитывает находку с указанной серьезностью
+func (c *SeverityCounts) Add(severity string) {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		c.Critical++
+	case "HIGH":
+		c.High++
+	case "MEDIUM":
+		c.Medium++
+	case "LOW":
+		c.Low++
+	default:
+		c.Unknown++
+	}
+	c.Total++
+}
+
+// Merge прибавляет статистику other
+func (c *SeverityCounts) Merge(other *SeverityCounts) {
+	if other == nil {
+		return
+	}
+	c.Critical += other.Critical
+	c.High += other.High
+	c.Medium += other.Medium
+	c.Low += other.Low
+	c.Unknown += other.Unknown
+	c.Total += other.Total
+}
+
+// AtLeast возвращает количество находок с серьезностью не ниже указанной
+func (c SeverityCounts) AtLeast(severity string) int {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return c.Critical
+	case "HIGH":
+		return c.Critical + c.High
+	case "MEDIUM":
+		return c.Critical + c.High + c.Medium
+	case "LOW":
+		return c.Critical + c.High + c.Medium + c.Low
+	default:
+		return c.Total
+	}
+}
+
+// ScanSummary содержит сводку результатов сканирования
+type ScanSummary struct {
+	Vulnerabilities SeverityCounts `json:"vulnerabilities"`
+	Secrets         SeverityCounts `json:"secrets"`
+	Misconfigs      SeverityCounts `json:"misconfigurations"`
+}
+
+// AtLeast возвращает общее количество уязвимостей, секретов и ошибок конфигурации
+// с серьезностью не ниже указанной
+func (s ScanSummary) AtLeast(severity string) int {
+	return s.Vulnerabilities.AtLeast(severity) + s.Secrets.AtLeast(severity) + s.Misconfigs.AtLeast(severity)
+}
+
+// Типы сканеров Trivy, которые можно запросить у агента
+const (
+	ScannerVuln      = "vuln"
+	ScannerSecret    = "secret"
+	ScannerMisconfig = "misconfig"
+)
+
 // ScanRequest представляет запрос на сканирование
 type ScanRequest struct {
-	ContainerID string `json:"container_id"`
+	ContainerID string   `json:"container_id"`
+	Scanners    []string `json:"scanners,omitempty"` // vuln, secret, misconfig (по умолчанию vuln)
 }
 
 // ScanResponse представляет ответ на запрос сканирования
@@ -103,7 +627,34 @@ type ScanStatusResponse struct {
 	StartedAt       time.Time       `json:"started_at"`
 	FinishedAt      *time.Time      `json:"finished_at,omitempty"`
 	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+	Secrets         []Finding       `json:"secrets,omitempty"`
+	Misconfigs      []Finding       `json:"misconfigurations,omitempty"`
 	ErrorMsg        string          `json:"error_msg,omitempty"`
+	ImageDigest     string          `json:"image_digest,omitempty"` // Дайджест манифеста образа, для образов вне реестра - ID образа
+	SBOMFormats     []string        `json:"sbom_formats,omitempty"` // Форматы SBOM, доступные через /scan/{id}/sbom
+}
+
+// SBOM представляет спецификацию программного обеспечения (SBOM) образа
+type SBOM struct {
+	ID          string    `json:"id" db:"id"`
+	ImageDigest string    `json:"image_digest" db:"image_digest"`
+	Image       string    `json:"image" db:"image"`
+	Format      string    `json:"format" db:"format"` // cyclonedx, spdx
+	ScanID      string    `json:"scan_id" db:"scan_id"`
+	HostID      string    `json:"host_id" db:"host_id"`
+	ContainerID string    `json:"container_id" db:"container_id"`
+	Content     string    `json:"content,omitempty" db:"content"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// SBOMPackage представляет пакет, входящий в состав образа
+type SBOMPackage struct {
+	ID          string `json:"id" db:"id"`
+	ImageDigest string `json:"image_digest" db:"image_digest"`
+	Name        string `json:"name" db:"name"`
+	Version     string `json:"version" db:"version"`
+	Type        string `json:"type" db:"type"` // deb, apk, npm, gomod и т.д.
+	PURL        string `json:"purl,omitempty" db:"purl"`
 }
 
 // RemediationStrategy представляет стратегию исправления уязвимостей
@@ -117,10 +668,195 @@ type RemediationStrategy struct {
 	CreatedAt         time.Time `json:"created_at" db:"created_at"`
 }
 
+// RenderCommand подставляет в команду стратегии параметры контейнера:
+// {{container_id}}, {{container_name}}, {{pod_name}}, {{namespace}} и {{deployment_name}}.
+// Неизвестные контейнеру параметры остаются в команде без изменений
+func (s *RemediationStrategy) RenderCommand(c *Container) string {
+	if c == nil {
+		return s.Command
+	}
+
+	replacements := []string{
+		"{{container_id}}", c.ID,
+		"{{container_name}}", c.Name,
+	}
+	if c.PodName != "" {
+		replacements = append(replacements,
+			"{{pod_name}}", c.PodName,
+			"{{namespace}}", c.PodNamespace,
+		)
+	}
+	if c.WorkloadKind == WorkloadDeployment {
+		replacements = append(replacements, "{{deployment_name}}", c.WorkloadName)
+	}
+
+	return strings.NewReplacer(replacements...).Replace(s.Command)
+}
+
+var (
+	// dns1123Name - имя объекта Kubernetes (RFC 1123): namespace, под, Deployment, контейнер
+	dns1123Name = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	// containerIDPattern - полный или сокращенный ID контейнера
+	containerIDPattern = regexp.MustCompile(`^[0-9a-f]{12,64}$`)
+)
+
+// RenderArgs разбивает команду стратегии на аргументы по пробелам и подставляет в них
+// параметры контейнера. Команда выполняется без оболочки, поэтому значения не могут
+// добавить к ней свои команды; кроме того, отклоняются значения, не являющиеся ID
+// контейнера или именем DNS-1123: имена и метки контейнеров приходят от агента
+func (s *RemediationStrategy) RenderArgs(c *Container) ([]string, error) {
+	if c == nil {
+		return nil, i18n.Errorf("контейнер не указан")
+	}
+
+	values := []struct {
+		param string
+		value string
+		set   bool
+	}{
+		{"{{container_id}}", c.ID, true},
+		{"{{container_name}}", c.Name, true},
+		{"{{pod_name}}", c.PodName, c.PodName != ""},
+		{"{{namespace}}", c.PodNamespace, c.PodName != ""},
+		{"{{deployment_name}}", c.WorkloadName, c.WorkloadKind == WorkloadDeployment},
+	}
+
+	var replacements []string
+	for _, v := range values {
+		if !v.set || !strings.Contains(s.Command, v.param) {
+			continue
+		}
+		if !containerIDPattern.MatchString(v.value) && (len(v.value) > 253 || !dns1123Name.MatchString(v.value)) {
+			return nil, i18n.Errorf("недопустимое значение параметра %s: %q", v.param, v.value)
+		}
+		replacements = append(replacements, v.param, v.value)
+	}
+	replacer := strings.NewReplacer(replacements...)
+
+	args := strings.Fields(s.Command)
+	if len(args) == 0 {
+		return nil, i18n.Errorf("пустая команда стратегии %s", s.ID)
+	}
+	for i, arg := range args {
+		args[i] = replacer.Replace(arg)
+	}
+	if command := strings.Join(args, " "); strings.Contains(command, "{{") {
+		return nil, i18n.Errorf("в команде остались незаполненные параметры: %s", command)
+	}
+	return args, nil
+}
+
 // NotificationConfig представляет конфигурацию уведомлений
 type NotificationConfig struct {
-	Enabled        bool   `json:"enabled" db:"enabled"`
-	TelegramBot    bool   `json:"telegram_bot" db:"telegram_bot"`
-	TelegramToken  string `json:"telegram_token,omitempty" db:"telegram_token"`
-	TelegramChatID string `json:"telegram_chat_id,omitempty" db:"telegram_chat_id"`
+	Enabled        bool   `json:"enabled" db:"enabled" mapstructure:"enabled"`
+	TelegramBot    bool   `json:"telegram_bot" db:"telegram_bot" mapstructure:"telegram_bot"`
+	TelegramToken  string `json:"telegram_token,omitempty" db:"telegram_token" mapstructure:"telegram_token"`
+	TelegramChatID string `json:"telegram_chat_id,omitempty" db:"telegram_chat_id" mapstructure:"telegram_chat_id"`
+
+	// Каналы уведомлений. Если список пуст, используются системные уведомления
+	// и Telegram из полей выше
+	Channels []NotificationChannel `json:"channels,omitempty" mapstructure:"channels"`
+
+	// SendReports отправляет экспортированные отчеты в каналы, которые поддерживают файлы
+	SendReports bool `json:"send_reports,omitempty" mapstructure:"send_reports"`
+
+	// Route применяется ко всем каналам до их собственных правил
+	Route NotificationRoute `json:"route,omitempty" mapstructure:"route"`
+
+	// TemplatesDir - каталог шаблонов сообщений, переопределяющих встроенные
+	TemplatesDir string `json:"templates_dir,omitempty" mapstructure:"templates_dir"`
+
+	// Группировка и ограничение частоты. Очередь и история отправки хранятся в базе данных,
+	// 0 отключает ограничение
+	BatchWindowSeconds int `json:"batch_window_seconds,omitempty" mapstructure:"batch_window_seconds"` // События окна отправляются одной сводкой
+	DedupWindowHours   int `json:"dedup_window_hours,omitempty" mapstructure:"dedup_window_hours"`     // Повтор тех же находок в течение окна не отправляется
+	RateLimitPerHour   int `json:"rate_limit_per_hour,omitempty" mapstructure:"rate_limit_per_hour"`   // Сообщений в час на канал, сверх лимита события ждут в очереди
+
+	// Digest - периодическая сводка по хостам
+	Digest NotificationDigest `json:"digest,omitempty" mapstructure:"digest"`
+}
+
+// NotificationRoute задает правила, по которым уведомление направляется в канал.
+// Пустые поля не ограничивают отправку
+type NotificationRoute struct {
+	MinSeverity  string   `json:"min_severity,omitempty" mapstructure:"min_severity"`     // Только сканирования с находками не ниже CRITICAL, HIGH, MEDIUM, LOW
+	Hosts        []string `json:"hosts,omitempty" mapstructure:"hosts"`                   // Имена или ID хостов, допускаются шаблоны
+	Images       []string `json:"images,omitempty" mapstructure:"images"`                 // Шаблоны репозитория образа без тега, например registry.local/*
+	Tags         []string `json:"tags,omitempty" mapstructure:"tags"`                     // Шаблоны тега образа, например release-*
+	QuietHours   string   `json:"quiet_hours,omitempty" mapstructure:"quiet_hours"`       // Местное время без уведомлений, например 22:00-07:00
+	OnlyOnChange bool     `json:"only_on_change,omitempty" mapstructure:"only_on_change"` // Только при новых или исправленных уязвимостях
+}
+
+// Типы каналов уведомлений
+const (
+	NotificationChannelDesktop    = "desktop"
+	NotificationChannelTelegram   = "telegram"
+	NotificationChannelSlack      = "slack"
+	NotificationChannelMattermost = "mattermost"
+	NotificationChannelTeams      = "teams"
+	NotificationChannelWebhook    = "webhook"
+	NotificationChannelEmail      = "email"
+)
+
+// NotificationChannel представляет канал, в который отправляются уведомления
+type NotificationChannel struct {
+	Type     string            `json:"type" mapstructure:"type"`                   // desktop, telegram, slack, mattermost, teams, webhook, email
+	Name     string            `json:"name,omitempty" mapstructure:"name"`         // Имя канала в журнале, по умолчанию тип
+	URL      string            `json:"url,omitempty" mapstructure:"url"`           // Входящий webhook (slack, mattermost, teams, webhook) или адрес Bot API (telegram)
+	Token    string            `json:"token,omitempty" mapstructure:"token"`       // Токен бота (telegram)
+	ChatID   string            `json:"chat_id,omitempty" mapstructure:"chat_id"`   // ID чата (telegram)
+	Channel  string            `json:"channel,omitempty" mapstructure:"channel"`   // Переопределение канала (slack, mattermost)
+	Username string            `json:"username,omitempty" mapstructure:"username"` // Имя отправителя (slack, mattermost) или пользователь SMTP (email)
+	Headers  map[string]string `json:"headers,omitempty" mapstructure:"headers"`   // Дополнительные HTTP-заголовки (webhook)
+	Disabled bool              `json:"disabled,omitempty" mapstructure:"disabled"`
+
+	// Правила маршрутизации канала
+	Route NotificationRoute `json:"route,omitempty" mapstructure:"route"`
+
+	// RateLimitPerHour переопределяет notification.rate_limit_per_hour для канала, -1 снимает ограничение
+	RateLimitPerHour int `json:"rate_limit_per_hour,omitempty" mapstructure:"rate_limit_per_hour"`
+
+	// Параметры канала email
+	Host     string   `json:"host,omitempty" mapstructure:"host"`         // SMTP-сервер
+	Port     int      `json:"port,omitempty" mapstructure:"port"`         // По умолчанию 587, для tls: implicit - 465
+	TLS      string   `json:"tls,omitempty" mapstructure:"tls"`           // starttls (по умолчанию), implicit или none
+	Password string   `json:"password,omitempty" mapstructure:"password"` // Аутентификация выполняется, если задан username
+	From     string   `json:"from,omitempty" mapstructure:"from"`
+	To       []string `json:"to,omitempty" mapstructure:"to"`
+}
+
+// DisplayName возвращает имя канала для журнала
+func (c NotificationChannel) DisplayName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Type
+}
+
+// NotificationDigest задает расписание дайджеста: новые и исправленные уязвимости
+// по хостам за сутки или неделю
+type NotificationDigest struct {
+	Schedule string   `json:"schedule,omitempty" mapstructure:"schedule"` // daily или weekly, пустое значение отключает дайджест
+	Time     string   `json:"time,omitempty" mapstructure:"time"`         // Местное время отправки ЧЧ:ММ, по умолчанию 09:00
+	Weekday  string   `json:"weekday,omitempty" mapstructure:"weekday"`   // День недели для weekly, по умолчанию monday
+	Channels []string `json:"channels,omitempty" mapstructure:"channels"` // Имена каналов, по умолчанию все каналы
+}
+
+// NotificationQueueItem представляет уведомление, ожидающее отправки в канал сводкой
+type NotificationQueueItem struct {
+	ID          string    `json:"id" db:"id"`
+	Channel     string    `json:"channel" db:"channel"`
+	Event       string    `json:"event" db:"event"`
+	Fingerprint string    `json:"fingerprint" db:"fingerprint"`
+	Payload     string    `json:"payload" db:"payload"` // Уведомление в формате JSON
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// NotificationDelivery представляет сообщение, отправленное в канал уведомлений
+type NotificationDelivery struct {
+	ID      string    `json:"id" db:"id"`
+	Channel string    `json:"channel" db:"channel"`
+	Event   string    `json:"event" db:"event"`
+	Events  int       `json:"events" db:"events"` // Число событий, объединенных в сообщение
+	SentAt  time.Time `json:"sent_at" db:"sent_at"`
 }
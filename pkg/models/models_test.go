@@ -0,0 +1,64 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderArgs(t *testing.T) {
+	container := &Container{
+		ID:           "3f4e5d6c7b8a9f0e1d2c3b4a5f6e7d8c9b0a1f2e3d4c5b6a7f8e9d0c1b2a3f4e",
+		Name:         "k8s_api_api-7d9c6b5f4-x2x9z_shop_1",
+		PodName:      "api-7d9c6b5f4-x2x9z",
+		PodNamespace: "shop",
+		WorkloadKind: WorkloadDeployment,
+		WorkloadName: "api",
+	}
+
+	strategy := &RemediationStrategy{ID: "strategy-3", Command: "kubectl rollout restart deployment/{{deployment_name}} -n {{namespace}}"}
+	args, err := strategy.RenderArgs(container)
+	if err != nil {
+		t.Fatalf("RenderArgs: %v", err)
+	}
+	want := []string{"kubectl", "rollout", "restart", "deployment/api", "-n", "shop"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("RenderArgs = %q, ожидается %q", args, want)
+	}
+
+	// Имя контейнера не используется в команде, поэтому не проверяется
+	strategy = &RemediationStrategy{ID: "strategy-2", Command: "docker restart {{container_id}}"}
+	if _, err := strategy.RenderArgs(container); err != nil {
+		t.Errorf("RenderArgs: %v", err)
+	}
+}
+
+func TestRenderArgsRejectsUnsafeValues(t *testing.T) {
+	for _, name := range []string{
+		"api; rm -rf ~",
+		"$(id)",
+		"api name",
+		"-n=kube-system",
+		"API",
+		"",
+	} {
+		container := &Container{
+			ID:           "3f4e5d6c7b8a",
+			PodName:      "api-0",
+			PodNamespace: "shop",
+			WorkloadKind: WorkloadDeployment,
+			WorkloadName: name,
+		}
+		strategy := &RemediationStrategy{Command: "kubectl rollout restart deployment/{{deployment_name}} -n {{namespace}}"}
+		if args, err := strategy.RenderArgs(container); err == nil {
+			t.Errorf("значение %q принято: %q", name, args)
+		}
+	}
+}
+
+func TestRenderArgsUnfilledParameter(t *testing.T) {
+	strategy := &RemediationStrategy{Command: "kubectl rollout restart deployment/{{deployment_name}}"}
+	// Контейнер вне Kubernetes: имя Deployment неизвестно
+	if _, err := strategy.RenderArgs(&Container{ID: "3f4e5d6c7b8a", Name: "api"}); err == nil {
+		t.Error("команда с незаполненным параметром принята")
+	}
+}
@@ -0,0 +1,289 @@
+// Package output выводит результаты команд CLI в формате, выбранном флагами --output,
+// --template и --quiet: таблицей для человека или машиночитаемыми JSON, YAML и CSV.
+// Машиночитаемые форматы не переводятся: имена полей берутся из JSON-тегов моделей
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Форматы вывода
+const (
+	FormatTable = "table" // Таблица с сокращенными значениями
+	FormatWide  = "wide"  // Таблица с полными значениями и дополнительными столбцами
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+	FormatCSV   = "csv"
+)
+
+// Formats содержит все поддерживаемые форматы
+var Formats = []string{FormatTable, FormatWide, FormatJSON, FormatYAML, FormatCSV}
+
+// Options - параметры вывода команды
+type Options struct {
+	Format   string
+	Template string // Шаблон Go text/template, заменяет формат
+	Quiet    bool   // Только идентификаторы, по одному в строке
+}
+
+// AddFlags добавляет в набор флагов флаги вывода
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	if o.Format == "" {
+		o.Format = FormatTable
+	}
+	fs.StringVarP(&o.Format, "output", "o", o.Format, i18n.Sprintf("Формат вывода: %s", strings.Join(Formats, ", ")))
+	fs.StringVar(&o.Template, "template", o.Template, i18n.T("Шаблон Go text/template для вывода, например '{{range .}}{{.id}}{{\"\\n\"}}{{end}}'"))
+	fs.BoolVarP(&o.Quiet, "quiet", "q", o.Quiet, i18n.T("Выводить только идентификаторы"))
+}
+
+// Validate проверяет формат вывода
+func (o Options) Validate() error {
+	for _, format := range Formats {
+		if o.Format == format {
+			return nil
+		}
+	}
+	return i18n.Errorf("неизвестный формат вывода: %s (допустимо: %s)", o.Format, strings.Join(Formats, ", "))
+}
+
+// Human сообщает, предназначен ли вывод для человека. В остальных режимах команды
+// не выводят в stdout ничего, кроме результата
+func (o Options) Human() bool {
+	return !o.Quiet && o.Template == "" && (o.Format == FormatTable || o.Format == FormatWide)
+}
+
+// Column описывает столбец таблицы
+type Column struct {
+	Key    string // Заголовок в формате csv, не переводится
+	Header string
+	Width  int  // Ширина столбца в формате table; 0 - без выравнивания
+	Max    int  // Значения длиннее сокращаются в формате table с многоточием
+	Prefix int  // Значения длиннее сокращаются в формате table до префикса (ID, дайджесты)
+	Wide   bool // Столбец выводится только в форматах wide и csv
+}
+
+// Table - табличное представление результата команды
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+	IDs     []string // Идентификаторы строк для --quiet
+	Empty   string   // Сообщение для таблицы без строк
+}
+
+// Row добавляет строку таблицы с идентификатором id
+func (t *Table) Row(id string, values ...string) {
+	t.IDs = append(t.IDs, id)
+	t.Rows = append(t.Rows, values)
+}
+
+// Render выводит результат команды: data - в форматах json, yaml и в шаблон,
+// table - в форматах table, wide, csv и для --quiet
+func Render(w io.Writer, opts Options, data interface{}, table *Table) error {
+	// Пустой список выводится как [], а не null
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice && v.IsNil() {
+		data = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+
+	switch {
+	case opts.Quiet:
+		for _, id := range table.IDs {
+			fmt.Fprintln(w, id)
+		}
+		return nil
+	case opts.Template != "":
+		return renderTemplate(w, opts.Template, data)
+	}
+
+	switch opts.Format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(data)
+	case FormatYAML:
+		return renderYAML(w, data)
+	case FormatCSV:
+		return renderCSV(w, table)
+	case FormatWide:
+		return renderTable(w, table, true)
+	default:
+		return renderTable(w, table, false)
+	}
+}
+
+// renderTable выводит таблицу с выравниванием столбцов
+func renderTable(w io.Writer, table *Table, wide bool) error {
+	if len(table.Rows) == 0 {
+		if table.Empty != "" {
+			fmt.Fprintln(w, table.Empty)
+		}
+		return nil
+	}
+
+	var columns []int
+	widths := make(map[int]int)
+	for i, column := range table.Columns {
+		if column.Wide && !wide {
+			continue
+		}
+		columns = append(columns, i)
+		widths[i] = column.Width
+		if n := len([]rune(column.Header)); wide && n >= widths[i] {
+			widths[i] = n + 1
+		}
+	}
+
+	rows := make([][]string, len(table.Rows))
+	for r, row := range table.Rows {
+		for _, i := range columns {
+			value := ""
+			if i < len(row) {
+				value = row[i]
+			}
+			if !wide {
+				value = shorten(value, table.Columns[i])
+			}
+			// В формате wide столбцы расширяются до самого длинного значения
+			if n := len([]rune(value)); wide && n >= widths[i] {
+				widths[i] = n + 1
+			}
+			rows[r] = append(rows[r], value)
+		}
+	}
+
+	line := func(values []string) {
+		var b strings.Builder
+		for n, i := range columns {
+			if n > 0 {
+				b.WriteByte(' ')
+			}
+			if widths[i] > 0 && n < len(columns)-1 {
+				fmt.Fprintf(&b, "%-*s", widths[i], values[n])
+			} else {
+				b.WriteString(values[n])
+			}
+		}
+		fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+	}
+
+	headers := make([]string, 0, len(columns))
+	total := 0
+	for _, i := range columns {
+		headers = append(headers, table.Columns[i].Header)
+		total += widths[i] + 1
+	}
+	line(headers)
+	fmt.Fprintln(w, strings.Repeat("-", total))
+	for _, row := range rows {
+		line(row)
+	}
+	return nil
+}
+
+// shorten сокращает значение по правилам столбца для формата table
+func shorten(value string, column Column) string {
+	runes := []rune(value)
+	switch {
+	case column.Prefix > 0 && len(runes) > column.Prefix:
+		return string(runes[:column.Prefix])
+	case column.Max > 3 && len(runes) > column.Max:
+		return string(runes[:column.Max-3]) + "..."
+	}
+	return value
+}
+
+// renderCSV выводит все столбцы таблицы с полными значениями и строкой ключей столбцов
+func renderCSV(w io.Writer, table *Table) error {
+	writer := csv.NewWriter(w)
+	headers := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		headers[i] = column.Key
+	}
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+	for _, row := range table.Rows {
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// renderYAML выводит данные в YAML с теми же именами и порядком полей, что и в JSON
+func renderYAML(w io.Writer, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	// JSON - подмножество YAML: разбор в узлы сохраняет порядок полей
+	var node yaml.Node
+	if err := yaml.Unmarshal(raw, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// blockStyle заменяет стиль JSON (фигурные скобки и кавычки) на блочный стиль YAML.
+// Кавычки сохраняются у строк, которые без них читались бы как число или логическое значение
+func blockStyle(node *yaml.Node) {
+	switch {
+	case node.Kind == yaml.ScalarNode && node.Tag == "!!str":
+		var value interface{}
+		if yaml.Unmarshal([]byte(node.Value), &value) == nil {
+			if s, ok := value.(string); ok && s == node.Value {
+				node.Style = 0
+			}
+		}
+	case node.Kind != yaml.ScalarNode:
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// renderTemplate выполняет шаблон над данными в том виде, в каком они выводятся в JSON,
+// поэтому в шаблоне используются имена полей JSON: {{.id}}, {{.name}}
+func renderTemplate(w io.Writer, text string, data interface{}) error {
+	tmpl, err := template.New("output").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return i18n.Errorf("ошибка в шаблоне --template: %w", err)
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	if err := tmpl.Execute(w, value); err != nil {
+		return i18n.Errorf("ошибка выполнения шаблона --template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package sbom
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/google/uuid"
+)
+
+// Поддерживаемые форматы SBOM
+const (
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+)
+
+// Formats содержит все поддерживаемые форматы в порядке предпочтения
+var Formats = []string{FormatCycloneDX, FormatSPDX}
+
+// IsValidFormat проверяет, поддерживается ли формат SBOM
+func IsValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// TrivyFormat возвращает имя формата для параметра --format утилиты Trivy
+func TrivyFormat(format string) (string, error) {
+	switch format {
+	case FormatCycloneDX:
+		return "cyclonedx", nil
+	case FormatSPDX:
+		return "spdx-json", nil
+	default:
+		return "", i18n.Errorf("неподдерживаемый формат SBOM: %s", format)
+	}
+}
+
+// ContentType возвращает MIME-тип документа SBOM
+func ContentType(format string) string {
+	switch format {
+	case FormatCycloneDX:
+		return "application/vnd.cyclonedx+json"
+	case FormatSPDX:
+		return "application/spdx+json"
+	default:
+		return "application/json"
+	}
+}
+
+// cycloneDXDocument описывает используемую часть документа CycloneDX
+type cycloneDXDocument struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Name       string               `json:"name"`
+	Version    string               `json:"version"`
+	PURL       string               `json:"purl"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+// spdxDocument описывает используемую часть документа SPDX (JSON)
+type spdxDocument struct {
+	Packages []struct {
+		Name         string `json:"name"`
+		VersionInfo  string `json:"versionInfo"`
+		ExternalRefs []struct {
+			ReferenceType    string `json:"referenceType"`
+			ReferenceLocator string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+}
+
+// ParsePackages извлекает список пакетов из документа SBOM.
+// Учитываются только пакеты с Package URL: это отсекает записи об ОС и самом образе
+// и делает результат одинаковым для обоих форматов.
+func ParsePackages(format string, data []byte, imageDigest string) ([]models.SBOMPackage, error) {
+	var packages []models.SBOMPackage
+
+	add := func(name, version, purl string) {
+		if purl == "" {
+			return
+		}
+		packages = append(packages, models.SBOMPackage{
+			ID:          uuid.New().String(),
+			ImageDigest: imageDigest,
+			Name:        name,
+			Version:     version,
+			Type:        purlType(purl),
+			PURL:        purl,
+		})
+	}
+
+	switch format {
+	case FormatCycloneDX:
+		var doc cycloneDXDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, i18n.Errorf("ошибка разбора CycloneDX: %w", err)
+		}
+
+		var walk func(components []cycloneDXComponent)
+		walk = func(components []cycloneDXComponent) {
+			for _, c := range components {
+				add(c.Name, c.Version, c.PURL)
+				walk(c.Components)
+			}
+		}
+		walk(doc.Components)
+
+	case FormatSPDX:
+		var doc spdxDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, i18n.Errorf("ошибка разбора SPDX: %w", err)
+		}
+
+		for _, p := range doc.Packages {
+			purl := ""
+			for _, ref := range p.ExternalRefs {
+				if ref.ReferenceType == "purl" {
+					purl = ref.ReferenceLocator
+					break
+				}
+			}
+			add(p.Name, p.VersionInfo, purl)
+		}
+
+	default:
+		return nil, i18n.Errorf("неподдерживаемый формат SBOM: %s", format)
+	}
+
+	return packages, nil
+}
+
+// purlType возвращает тип пакета из Package URL (pkg:deb/debian/curl@7.88 -> deb)
+func purlType(purl string) string {
+	rest := strings.TrimPrefix(purl, "pkg:")
+	if i := strings.Index(rest, "/"); i > 0 {
+		return rest[:i]
+	}
+	return ""
+}
+
+// PackageChange описывает пакет, версия которого отличается в двух SBOM
+type PackageChange struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	OldVersion string `json:"old_version"`
+	NewVersion string `json:"new_version"`
+}
+
+// Diff представляет разницу между двумя наборами пакетов
+type Diff struct {
+	Added   []models.SBOMPackage `json:"added"`
+	Removed []models.SBOMPackage `json:"removed"`
+	Changed []PackageChange      `json:"changed"`
+}
+
+// Compare сравнивает пакеты двух образов
+func Compare(oldPackages, newPackages []models.SBOMPackage) *Diff {
+	oldIndex := indexPackages(oldPackages)
+	newIndex := indexPackages(newPackages)
+
+	diff := &Diff{}
+
+	for key, newVersions := range newIndex {
+		oldVersions, exists := oldIndex[key]
+		if !exists {
+			diff.Added = append(diff.Added, newVersions...)
+			continue
+		}
+
+		oldVersion := joinVersions(oldVersions)
+		newVersion := joinVersions(newVersions)
+		if oldVersion != newVersion {
+			diff.Changed = append(diff.Changed, PackageChange{
+				Name:       newVersions[0].Name,
+				Type:       newVersions[0].Type,
+				OldVersion: oldVersion,
+				NewVersion: newVersion,
+			})
+		}
+	}
+
+	for key, oldVersions := range oldIndex {
+		if _, exists := newIndex[key]; !exists {
+			diff.Removed = append(diff.Removed, oldVersions...)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Name < diff.Added[j].Name })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Name < diff.Removed[j].Name })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Name < diff.Changed[j].Name })
+
+	return diff
+}
+
+// indexPackages группирует пакеты по типу и имени
+func indexPackages(packages []models.SBOMPackage) map[string][]models.SBOMPackage {
+	index := make(map[string][]models.SBOMPackage)
+	for _, p := range packages {
+		key := p.Type + "/" + p.Name
+		index[key] = append(index[key], p)
+	}
+	return index
+}
+
+// joinVersions возвращает отсортированный список версий пакета в виде строки
+func joinVersions(packages []models.SBOMPackage) string {
+	versions := make([]string, 0, len(packages))
+	seen := make(map[string]bool)
+	for _, p := range packages {
+		if !seen[p.Version] {
+			seen[p.Version] = true
+			versions = append(versions, p.Version)
+		}
+	}
+	sort.Strings(versions)
+	return strings.Join(versions, ", ")
+}
+
+// NewRecord готовит SBOM, полученный от агента, к сохранению в БД
+func NewRecord(scan *models.Scan, image, format string, data []byte) (*models.SBOM, []models.SBOMPackage, error) {
+	if scan.ImageDigest == "" {
+		return nil, nil, i18n.Errorf("для сканирования %s неизвестен дайджест образа", scan.ID)
+	}
+
+	packages, err := ParsePackages(format, data, scan.ImageDigest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record := &models.SBOM{
+		ID:          uuid.New().String(),
+		ImageDigest: scan.ImageDigest,
+		Image:       image,
+		Format:      format,
+		ScanID:      scan.ID,
+		HostID:      scan.HostID,
+		ContainerID: scan.ContainerID,
+		Content:     string(data),
+		CreatedAt:   time.Now(),
+	}
+
+	return record, packages, nil
+}
@@ -0,0 +1,340 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/containerd/containerd/api/services/tasks/v1"
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/containers"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+)
+
+// defaultDockerCapabilities содержит capabilities, которые Docker выдает контейнеру по умолчанию.
+// Остальные capabilities из OCI-спецификации считаются добавленными (аналог --cap-add)
+var defaultDockerCapabilities = map[string]bool{
+	"CAP_CHOWN": true, "CAP_DAC_OVERRIDE": true, "CAP_FSETID": true, "CAP_FOWNER": true,
+	"CAP_MKNOD": true, "CAP_NET_RAW": true, "CAP_SETGID": true, "CAP_SETUID": true,
+	"CAP_SETFCAP": true, "CAP_SETPCAP": true, "CAP_NET_BIND_SERVICE": true, "CAP_SYS_CHROOT": true,
+	"CAP_KILL": true, "CAP_AUDIT_WRITE": true,
+}
+
+// containerdRuntime работает с containerd через его сокет с помощью Go-клиента containerd
+type containerdRuntime struct {
+	client     *containerd.Client
+	socketPath string
+	namespace  string
+}
+
+func newContainerdRuntime(socketPath, namespace string) (*containerdRuntime, error) {
+	cli, err := containerd.New(socketPath, containerd.WithDefaultNamespace(namespace))
+	if err != nil {
+		return nil, i18n.Errorf("ошибка подключения к containerd (%s): %w", socketPath, err)
+	}
+
+	return &containerdRuntime{
+		client:     cli,
+		socketPath: socketPath,
+		namespace:  namespace,
+	}, nil
+}
+
+// ociSpec описывает используемую часть OCI-спецификации контейнера
+type ociSpec struct {
+	Process *struct {
+		User struct {
+			UID uint32 `json:"uid"`
+			GID uint32 `json:"gid"`
+		} `json:"user"`
+		Capabilities *struct {
+			Bounding []string `json:"bounding"`
+		} `json:"capabilities"`
+	} `json:"process"`
+	Root *struct {
+		Readonly bool `json:"readonly"`
+	} `json:"root"`
+	Mounts []struct {
+		Destination string   `json:"destination"`
+		Type        string   `json:"type"`
+		Source      string   `json:"source"`
+		Options     []string `json:"options"`
+	} `json:"mounts"`
+	Linux *struct {
+		Namespaces []struct {
+			Type string `json:"type"`
+		} `json:"namespaces"`
+		Resources *struct {
+			Memory *struct {
+				Limit *int64 `json:"limit"`
+			} `json:"memory"`
+			Devices []struct {
+				Allow bool   `json:"allow"`
+				Type  string `json:"type"`
+			} `json:"devices"`
+		} `json:"resources"`
+	} `json:"linux"`
+}
+
+// Name возвращает имя среды выполнения
+func (r *containerdRuntime) Name() string {
+	return RuntimeContainerd
+}
+
+// ListContainers возвращает список контейнеров пространства имен containerd.
+// Контейнеры и статусы их задач запрашиваются двумя вызовами API
+func (r *containerdRuntime) ListContainers(ctx context.Context, all bool) ([]models.Container, error) {
+	ctx = namespaces.WithNamespace(ctx, r.namespace)
+
+	list, err := r.client.ContainerService().List(ctx)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка получения списка контейнеров: %w", err)
+	}
+
+	statuses, err := r.taskStatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []*containers.Container
+	podLabels := make(map[string]map[string]string)
+	for i := range list {
+		info := &list[i]
+		// Метки подов Kubernetes хранятся на sandbox-контейнерах
+		if isSandboxContainer(info.Labels) {
+			podLabels[info.ID] = info.Labels
+			continue
+		}
+		infos = append(infos, info)
+	}
+
+	var result []models.Container
+	for _, info := range infos {
+		status := statuses[info.ID]
+		if !all && status != "RUNNING" {
+			continue
+		}
+
+		container := models.Container{
+			ID:        info.ID,
+			HostID:    "local", // Временно используем "local" как ID хоста
+			Name:      containerdName(info),
+			Image:     info.Image,
+			Status:    dockerStatus(status),
+			Runtime:   RuntimeContainerd,
+			CreatedAt: info.CreatedAt,
+			UpdatedAt: time.Now(),
+		}
+		applyKubernetesContext(&container, info.Labels, podLabels[info.SandboxID])
+
+		result = append(result, container)
+	}
+
+	return result, nil
+}
+
+// InspectContainer преобразует OCI-спецификацию контейнера в формат Docker API,
+// чтобы правила аудита работали одинаково для всех сред выполнения
+func (r *containerdRuntime) InspectContainer(ctx context.Context, id string) (*container.InspectResponse, error) {
+	ctx = namespaces.WithNamespace(ctx, r.namespace)
+
+	info, err := r.client.ContainerService().Get(ctx, id)
+	if err != nil {
+		return nil, i18n.Errorf("контейнер не найден: %s: %w", id, err)
+	}
+
+	statuses, err := r.taskStatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hostConfig := &container.HostConfig{}
+	response := &container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:         info.ID,
+			Name:       "/" + containerdName(&info),
+			Created:    info.CreatedAt.Format(time.RFC3339Nano),
+			Image:      info.Image,
+			State:      &container.State{Status: stateStatus(statuses[info.ID])},
+			HostConfig: hostConfig,
+		},
+		Config:          &container.Config{Image: info.Image, Labels: containerdLabels(&info)},
+		NetworkSettings: &container.NetworkSettings{Networks: map[string]*network.EndpointSettings{}},
+	}
+
+	spec, err := containerdSpec(&info)
+	if err != nil {
+		return nil, err
+	}
+	if spec == nil {
+		return response, nil
+	}
+
+	if spec.Process != nil {
+		response.Config.User = fmt.Sprintf("%d:%d", spec.Process.User.UID, spec.Process.User.GID)
+	}
+	if spec.Root != nil {
+		hostConfig.ReadonlyRootfs = spec.Root.Readonly
+	}
+
+	if spec.Linux != nil {
+		// Отсутствие пространства имен в спецификации означает использование пространства хоста
+		hostConfig.NetworkMode = "host"
+		hostConfig.PidMode = "host"
+		for _, ns := range spec.Linux.Namespaces {
+			switch ns.Type {
+			case "network":
+				hostConfig.NetworkMode = "default"
+			case "pid":
+				hostConfig.PidMode = ""
+			}
+		}
+
+		if res := spec.Linux.Resources; res != nil {
+			if res.Memory != nil && res.Memory.Limit != nil && *res.Memory.Limit > 0 {
+				hostConfig.Memory = *res.Memory.Limit
+			}
+			// Разрешение доступа ко всем устройствам выдается только привилегированным контейнерам
+			for _, device := range res.Devices {
+				if device.Allow && (device.Type == "" || device.Type == "a") {
+					hostConfig.Privileged = true
+				}
+			}
+		}
+	}
+
+	if spec.Process != nil && spec.Process.Capabilities != nil && !hostConfig.Privileged {
+		for _, capability := range spec.Process.Capabilities.Bounding {
+			if !defaultDockerCapabilities[capability] {
+				hostConfig.CapAdd = append(hostConfig.CapAdd, strings.TrimPrefix(capability, "CAP_"))
+			}
+		}
+	}
+
+	for _, m := range spec.Mounts {
+		if m.Type != "bind" && !containsOption(m.Options, "bind") && !containsOption(m.Options, "rbind") {
+			continue
+		}
+		response.Mounts = append(response.Mounts, container.MountPoint{
+			Type:        mount.TypeBind,
+			Source:      m.Source,
+			Destination: m.Destination,
+			RW:          !containsOption(m.Options, "ro"),
+		})
+	}
+
+	return response, nil
+}
+
+// TrivyArgs возвращает аргументы trivy для сканирования образов из хранилища containerd
+func (r *containerdRuntime) TrivyArgs() []string {
+	return []string{"--image-src", "containerd"}
+}
+
+// TrivyEnv возвращает адрес и пространство имен containerd для trivy
+func (r *containerdRuntime) TrivyEnv() []string {
+	return []string{
+		"CONTAINERD_ADDRESS=" + r.socketPath,
+		"CONTAINERD_NAMESPACE=" + r.namespace,
+	}
+}
+
+// taskStatuses возвращает статусы задач (процессов) контейнеров: RUNNING, STOPPED, PAUSED и т.д.
+func (r *containerdRuntime) taskStatuses(ctx context.Context) (map[string]string, error) {
+	resp, err := r.client.TaskService().List(ctx, &tasks.ListTasksRequest{})
+	if err != nil {
+		return nil, i18n.Errorf("ошибка получения списка задач: %w", err)
+	}
+
+	statuses := make(map[string]string, len(resp.Tasks))
+	for _, t := range resp.Tasks {
+		statuses[t.ContainerID] = t.Status.String()
+	}
+	return statuses, nil
+}
+
+// containerdSpec разбирает OCI-спецификацию контейнера; containerd хранит ее в формате JSON
+func containerdSpec(info *containers.Container) (*ociSpec, error) {
+	if info.Spec == nil || len(info.Spec.GetValue()) == 0 {
+		return nil, nil
+	}
+
+	var spec ociSpec
+	if err := json.Unmarshal(info.Spec.GetValue(), &spec); err != nil {
+		return nil, i18n.Errorf("ошибка разбора описания контейнера %s: %w", info.ID, err)
+	}
+	return &spec, nil
+}
+
+// containerdName возвращает имя контейнера из меток nerdctl или Kubernetes
+func containerdName(info *containers.Container) string {
+	for _, label := range []string{"nerdctl/name", "io.kubernetes.container.name"} {
+		if name := info.Labels[label]; name != "" {
+			return name
+		}
+	}
+	if len(info.ID) > 12 {
+		return info.ID[:12]
+	}
+	return info.ID
+}
+
+// containerdLabels возвращает метки контейнера, дополненные ID sandbox-контейнера пода
+// в формате cri-dockerd, чтобы контекст Kubernetes определялся одинаково для всех сред
+func containerdLabels(info *containers.Container) map[string]string {
+	if info.SandboxID == "" {
+		return info.Labels
+	}
+
+	labels := make(map[string]string, len(info.Labels)+1)
+	for k, v := range info.Labels {
+		labels[k] = v
+	}
+	labels[labelSandboxID] = info.SandboxID
+	return labels
+}
+
+// dockerStatus приводит статус задачи containerd к формату Docker, используемому CLI и TUI
+func dockerStatus(taskStatus string) string {
+	switch taskStatus {
+	case "RUNNING":
+		return "Up"
+	case "PAUSED":
+		return "Paused"
+	case "STOPPED":
+		return "Exited"
+	default:
+		return "Created"
+	}
+}
+
+// stateStatus приводит статус задачи containerd к значению State.Status Docker API
+func stateStatus(taskStatus string) string {
+	switch taskStatus {
+	case "RUNNING":
+		return "running"
+	case "PAUSED":
+		return "paused"
+	case "STOPPED":
+		return "exited"
+	default:
+		return "created"
+	}
+}
+
+// containsOption проверяет наличие параметра монтирования
+func containsOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+)
+
+// trivyVersionTimeout ограничивает запрос версии trivy для GET /info
+const trivyVersionTimeout = 10 * time.Second
+
+// TrivyVersionInfo содержит версию trivy и время обновления БД уязвимостей
+type TrivyVersionInfo struct {
+	Version     string
+	DBUpdatedAt *time.Time // nil - БД еще не загружена
+}
+
+// QueueState возвращает лимит параллельных сканирований, число выполняющихся
+// и ожидающих в очереди сканирований
+func (s *Scanner) QueueState() (concurrency, running, queued int) {
+	return s.concurrency, len(s.sem), int(s.queued.Load())
+}
+
+// SupportsHostPosture сообщает, доступна ли проверка настроек daemon (HostPosture)
+func (s *Scanner) SupportsHostPosture() bool {
+	_, ok := s.runtime.(DaemonInfoProvider)
+	return ok
+}
+
+// RuntimeVersion возвращает версию Docker Engine или Podman. Для сред без
+// Docker-совместимого API возвращается пустая строка
+func (s *Scanner) RuntimeVersion(ctx context.Context) (string, error) {
+	daemon, ok := s.runtime.(DaemonInfoProvider)
+	if !ok {
+		return "", nil
+	}
+	version, err := daemon.ServerVersion(ctx)
+	if err != nil {
+		return "", i18n.Errorf("ошибка получения версии %s: %w", s.runtime.Name(), err)
+	}
+	return version.Version, nil
+}
+
+// TrivyVersion запрашивает версию trivy и сведения о БД уязвимостей (trivy version --format json)
+func (s *Scanner) TrivyVersion(ctx context.Context) (*TrivyVersionInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, trivyVersionTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "trivy", "version", "--format", "json")
+	span := startTrivySpan(ctx, "version", cmd)
+	start := time.Now()
+	output, err := cmd.Output()
+	observeTrivy("version", cmd, start)
+	endTrivySpan(span, cmd, err)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка получения версии trivy: %w", err)
+	}
+
+	var version struct {
+		Version         string `json:"Version"`
+		VulnerabilityDB *struct {
+			UpdatedAt time.Time `json:"UpdatedAt"`
+		} `json:"VulnerabilityDB"`
+	}
+	if err := json.Unmarshal(output, &version); err != nil {
+		return nil, i18n.Errorf("ошибка разбора версии trivy: %w", err)
+	}
+
+	info := &TrivyVersionInfo{Version: version.Version}
+	if version.VulnerabilityDB != nil && !version.VulnerabilityDB.UpdatedAt.IsZero() {
+		updatedAt := version.VulnerabilityDB.UpdatedAt
+		info.DBUpdatedAt = &updatedAt
+	}
+	return info, nil
+}
@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aegis/aegis-cli/pkg/models"
+)
+
+// Метки, которые kubelet (через cri-dockerd или CRI containerd) добавляет контейнерам пода
+const (
+	labelPodName       = "io.kubernetes.pod.name"
+	labelPodNamespace  = "io.kubernetes.pod.namespace"
+	labelContainerName = "io.kubernetes.container.name"
+	labelSandboxID     = "io.kubernetes.sandbox.id"
+)
+
+// Метки пода, по которым определяется контроллер рабочей нагрузки.
+// Kubernetes выставляет их на поде, а среда выполнения копирует на sandbox-контейнер
+const (
+	labelPodTemplateHash       = "pod-template-hash"
+	labelControllerRevision    = "controller-revision-hash"
+	labelPodTemplateGeneration = "pod-template-generation"
+	labelStatefulSetPodName    = "statefulset.kubernetes.io/pod-name"
+	labelJobName               = "batch.kubernetes.io/job-name"
+	labelLegacyJobName         = "job-name"
+)
+
+// sandboxContainerName содержит имя служебного pause-контейнера пода
+const sandboxContainerName = "POD"
+
+var (
+	// deploymentPodName соответствует имени пода Deployment: <deployment>-<хеш ReplicaSet>-<суффикс>
+	deploymentPodName = regexp.MustCompile(`^(.+)-[bcdfghjklmnpqrstvwxz2456789]{6,10}-[bcdfghjklmnpqrstvwxz2456789]{5}$`)
+	// statefulSetPodName соответствует имени пода StatefulSet: <statefulset>-<порядковый номер>
+	statefulSetPodName = regexp.MustCompile(`^(.+)-\d+$`)
+	// generatedSuffix соответствует случайному суффиксу имени пода DaemonSet или Job
+	generatedSuffix = regexp.MustCompile(`^(.+)-[bcdfghjklmnpqrstvwxz2456789]{5}$`)
+)
+
+// isSandboxContainer проверяет, является ли контейнер служебным pause-контейнером пода
+func isSandboxContainer(labels map[string]string) bool {
+	return labels[labelContainerName] == sandboxContainerName ||
+		labels["io.kubernetes.docker.type"] == "podsandbox" ||
+		labels["io.cri-containerd.kind"] == "sandbox"
+}
+
+// applyKubernetesContext заполняет pod, namespace и рабочую нагрузку контейнера по его меткам.
+// podLabels содержит метки пода (с sandbox-контейнера), если их удалось получить.
+// Живое подключение к кластеру не требуется: при отсутствии меток пода контроллер
+// определяется по формату имени пода
+func applyKubernetesContext(c *models.Container, labels, podLabels map[string]string) {
+	podName := labels[labelPodName]
+	if podName == "" {
+		return
+	}
+
+	c.PodName = podName
+	c.PodNamespace = labels[labelPodNamespace]
+	c.WorkloadKind, c.WorkloadName = detectWorkload(podName, podLabels)
+}
+
+// detectWorkload определяет тип и имя контроллера, управляющего подом
+func detectWorkload(podName string, podLabels map[string]string) (string, string) {
+	if job := podLabels[labelJobName]; job != "" {
+		return models.WorkloadJob, job
+	}
+	if job := podLabels[labelLegacyJobName]; job != "" {
+		return models.WorkloadJob, job
+	}
+
+	// Под ReplicaSet, созданного Deployment, называется <deployment>-<pod-template-hash>-<суффикс>
+	if hash := podLabels[labelPodTemplateHash]; hash != "" {
+		if i := strings.LastIndex(podName, "-"+hash+"-"); i > 0 {
+			return models.WorkloadDeployment, podName[:i]
+		}
+	}
+
+	if podLabels[labelStatefulSetPodName] != "" {
+		if m := statefulSetPodName.FindStringSubmatch(podName); m != nil {
+			return models.WorkloadStatefulSet, m[1]
+		}
+	}
+
+	if podLabels[labelPodTemplateGeneration] != "" || podLabels[labelControllerRevision] != "" {
+		if m := generatedSuffix.FindStringSubmatch(podName); m != nil {
+			return models.WorkloadDaemonSet, m[1]
+		}
+	}
+
+	// Метки пода недоступны: используем формат имени, однозначный только для Deployment
+	if len(podLabels) == 0 {
+		if m := deploymentPodName.FindStringSubmatch(podName); m != nil {
+			return models.WorkloadDeployment, m[1]
+		}
+	}
+
+	return models.WorkloadPod, podName
+}
@@ -0,0 +1,111 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/metrics"
+	"github.com/aegis/aegis-cli/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Метрики очереди сканирований, запусков trivy и обращений к Docker API.
+// Здесь же span трассировки для trivy и Docker API
+var (
+	scansRunning    = metrics.NewGauge("aegis_agent_scans_running", "Scans currently running trivy.")
+	scansQueued     = metrics.NewGauge("aegis_agent_scans_queued", "Scans waiting for a free scan_concurrency slot.")
+	scanConcurrency = metrics.NewGauge("aegis_agent_scan_concurrency", "Maximum number of concurrent scans (scan_concurrency).")
+
+	trivyRuns = metrics.NewCounterVec("aegis_agent_trivy_runs_total",
+		"Trivy invocations by command and exit code (-1 if trivy did not start or was killed).", "command", "exit_code")
+	trivyDuration = metrics.NewHistogramVec("aegis_agent_trivy_duration_seconds",
+		"Trivy run time by command.", []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800}, "command")
+
+	dockerAPIDuration = metrics.NewHistogramVec("aegis_agent_docker_api_duration_seconds",
+		"Latency of Docker-compatible API calls by runtime and operation.", metrics.DefBuckets, "runtime", "operation")
+	dockerAPIErrors = metrics.NewCounterVec("aegis_agent_docker_api_errors_total",
+		"Failed Docker-compatible API calls by runtime and operation.", "runtime", "operation")
+)
+
+func init() {
+	metrics.NewGaugeFunc("aegis_agent_trivy_db_updated_timestamp_seconds",
+		"Unix time of the last trivy vulnerability DB update.", trivyDBUpdatedAt)
+}
+
+// observeTrivy учитывает завершившийся запуск trivy
+func observeTrivy(command string, cmd *exec.Cmd, start time.Time) {
+	code := -1
+	if cmd.ProcessState != nil {
+		code = cmd.ProcessState.ExitCode()
+	}
+	trivyRuns.Inc(command, strconv.Itoa(code))
+	trivyDuration.Observe(time.Since(start).Seconds(), command)
+}
+
+// trivyDBUpdatedAt читает время обновления БД уязвимостей из metadata.json в кэше trivy.
+// Каталог кэша задается TRIVY_CACHE_DIR, по умолчанию - ~/.cache/trivy
+func trivyDBUpdatedAt() (float64, bool) {
+	cacheDir := os.Getenv("TRIVY_CACHE_DIR")
+	if cacheDir == "" {
+		userCache, err := os.UserCacheDir()
+		if err != nil {
+			return 0, false
+		}
+		cacheDir = filepath.Join(userCache, "trivy")
+	}
+
+	data, err := os.ReadFile(filepath.Join(cacheDir, "db", "metadata.json"))
+	if err != nil {
+		return 0, false
+	}
+	var metadata struct {
+		UpdatedAt time.Time `json:"UpdatedAt"`
+	}
+	if err := json.Unmarshal(data, &metadata); err != nil || metadata.UpdatedAt.IsZero() {
+		return 0, false
+	}
+	return float64(metadata.UpdatedAt.Unix()), true
+}
+
+// startDockerAPI начинает обращение к Docker-совместимому API: открывает span и возвращает
+// функцию, которая завершает его и учитывает обращение в метриках
+func (r *dockerRuntime) startDockerAPI(ctx context.Context, operation string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "docker "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("aegis.runtime", r.name),
+			attribute.String("aegis.docker.operation", operation),
+		),
+	)
+	return ctx, func(err error) {
+		dockerAPIDuration.Observe(time.Since(start).Seconds(), r.name, operation)
+		if err != nil {
+			dockerAPIErrors.Inc(r.name, operation)
+		}
+		tracing.End(span, err)
+	}
+}
+
+// startTrivySpan открывает span запуска trivy
+func startTrivySpan(ctx context.Context, command string, cmd *exec.Cmd) trace.Span {
+	_, span := tracing.Start(ctx, "trivy "+command, trace.WithAttributes(
+		attribute.String("aegis.trivy.command", command),
+		attribute.StringSlice("process.command_args", cmd.Args),
+	))
+	return span
+}
+
+// endTrivySpan завершает span запуска trivy с кодом завершения
+func endTrivySpan(span trace.Span, cmd *exec.Cmd, err error) {
+	if cmd.ProcessState != nil {
+		span.SetAttributes(attribute.Int("process.exit.code", cmd.ProcessState.ExitCode()))
+	}
+	tracing.End(span, err)
+}
@@ -0,0 +1,211 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/client"
+)
+
+// Поддерживаемые среды выполнения контейнеров
+const (
+	RuntimeDocker     = "docker"
+	RuntimePodman     = "podman"
+	RuntimeContainerd = "containerd"
+)
+
+// ContainerRuntime представляет среду выполнения, из которой агент получает контейнеры
+type ContainerRuntime interface {
+	// Name возвращает имя среды выполнения (docker, podman, containerd)
+	Name() string
+	// ListContainers возвращает контейнеры; при all=false только запущенные
+	ListContainers(ctx context.Context, all bool) ([]models.Container, error)
+	// InspectContainer возвращает конфигурацию контейнера в формате Docker API
+	InspectContainer(ctx context.Context, id string) (*container.InspectResponse, error)
+	// TrivyArgs возвращает дополнительные аргументы trivy image для образов этой среды
+	TrivyArgs() []string
+	// TrivyEnv возвращает дополнительные переменные окружения для trivy
+	TrivyEnv() []string
+}
+
+// DaemonInfoProvider реализуют среды выполнения с Docker-совместимым API,
+// для которых доступна проверка настроек daemon
+type DaemonInfoProvider interface {
+	Info(ctx context.Context) (system.Info, error)
+	ServerVersion(ctx context.Context) (types.Version, error)
+}
+
+// RuntimeOptions содержит параметры подключения к среде выполнения
+type RuntimeOptions struct {
+	Name                 string // docker, podman, containerd
+	DockerSocketPath     string
+	PodmanSocketPath     string // Пустое значение включает автоопределение
+	ContainerdSocketPath string
+	ContainerdNamespace  string
+}
+
+// NewRuntime создает среду выполнения, выбранную в конфигурации агента
+func NewRuntime(opts RuntimeOptions) (ContainerRuntime, error) {
+	switch opts.Name {
+	case "", RuntimeDocker:
+		return newDockerRuntime(RuntimeDocker, opts.DockerSocketPath, nil)
+	case RuntimePodman:
+		socketPath := opts.PodmanSocketPath
+		if socketPath == "" {
+			detected, err := detectPodmanSocket()
+			if err != nil {
+				return nil, err
+			}
+			socketPath = detected
+		}
+		// Образы trivy получает через тот же Docker-совместимый API Podman, что и агент:
+		// собственный источник podman trivy ищет сокет только по пути по умолчанию
+		return newDockerRuntime(RuntimePodman, socketPath, []string{"--image-src", "docker"})
+	case RuntimeContainerd:
+		return newContainerdRuntime(opts.ContainerdSocketPath, opts.ContainerdNamespace)
+	default:
+		return nil, i18n.Errorf("неизвестная среда выполнения: %s (допустимо: docker, podman, containerd)", opts.Name)
+	}
+}
+
+// detectPodmanSocket ищет сокет Docker-совместимого API Podman (системный или rootless)
+func detectPodmanSocket() (string, error) {
+	candidates := []string{"/run/podman/podman.sock"}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		candidates = append(candidates, filepath.Join(runtimeDir, "podman", "podman.sock"))
+	}
+	candidates = append(candidates, fmt.Sprintf("/run/user/%d/podman/podman.sock", os.Getuid()))
+
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+			return path, nil
+		}
+	}
+
+	return "", i18n.Errorf("сокет Podman не найден (проверены: %s); включите podman.socket или укажите podman_socket_path",
+		strings.Join(candidates, ", "))
+}
+
+// dockerRuntime работает с Docker Engine или Podman через Docker-совместимый API
+type dockerRuntime struct {
+	name       string
+	client     *client.Client
+	socketPath string
+	trivyArgs  []string
+}
+
+func newDockerRuntime(name, socketPath string, trivyArgs []string) (*dockerRuntime, error) {
+	cli, err := client.NewClientWithOpts(
+		client.WithHost(fmt.Sprintf("unix://%s", socketPath)),
+		client.WithAPIVersionNegotiation(),
+	)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка создания клиента %s: %w", name, err)
+	}
+
+	return &dockerRuntime{
+		name:       name,
+		client:     cli,
+		socketPath: socketPath,
+		trivyArgs:  trivyArgs,
+	}, nil
+}
+
+// Name возвращает имя среды выполнения
+func (r *dockerRuntime) Name() string {
+	return r.name
+}
+
+// ListContainers возвращает список контейнеров
+func (r *dockerRuntime) ListContainers(ctx context.Context, all bool) ([]models.Container, error) {
+	ctx, done := r.startDockerAPI(ctx, "container_list")
+	containers, err := r.client.ContainerList(ctx, container.ListOptions{All: all})
+	done(err)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка получения списка контейнеров: %w", err)
+	}
+
+	// Метки подов Kubernetes хранятся на sandbox-контейнерах
+	podLabels := make(map[string]map[string]string)
+	for _, c := range containers {
+		if isSandboxContainer(c.Labels) {
+			podLabels[c.ID] = c.Labels
+		}
+	}
+
+	var result []models.Container
+	for _, c := range containers {
+		if isSandboxContainer(c.Labels) {
+			continue
+		}
+
+		name := ""
+		if len(c.Names) > 0 {
+			// Docker API возвращает имена с префиксом "/", удаляем его
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+
+		container := models.Container{
+			ID:        c.ID,
+			HostID:    "local", // Временно используем "local" как ID хоста
+			Name:      name,
+			Image:     c.Image,
+			Status:    c.Status,
+			Runtime:   r.name,
+			CreatedAt: time.Unix(c.Created, 0),
+			UpdatedAt: time.Now(),
+		}
+		applyKubernetesContext(&container, c.Labels, podLabels[c.Labels[labelSandboxID]])
+
+		result = append(result, container)
+	}
+
+	return result, nil
+}
+
+// InspectContainer возвращает конфигурацию контейнера
+func (r *dockerRuntime) InspectContainer(ctx context.Context, id string) (*container.InspectResponse, error) {
+	ctx, done := r.startDockerAPI(ctx, "container_inspect")
+	info, err := r.client.ContainerInspect(ctx, id)
+	done(err)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Info возвращает сведения о daemon
+func (r *dockerRuntime) Info(ctx context.Context) (system.Info, error) {
+	ctx, done := r.startDockerAPI(ctx, "info")
+	info, err := r.client.Info(ctx)
+	done(err)
+	return info, err
+}
+
+// ServerVersion возвращает версию daemon
+func (r *dockerRuntime) ServerVersion(ctx context.Context) (types.Version, error) {
+	ctx, done := r.startDockerAPI(ctx, "version")
+	version, err := r.client.ServerVersion(ctx)
+	done(err)
+	return version, err
+}
+
+// TrivyArgs возвращает дополнительные аргументы trivy
+func (r *dockerRuntime) TrivyArgs() []string {
+	return r.trivyArgs
+}
+
+// TrivyEnv возвращает адрес сокета, через который агент получает контейнеры, чтобы trivy
+// искал образы в том же daemon, а не на сокете по умолчанию
+func (r *dockerRuntime) TrivyEnv() []string {
+	return []string{"DOCKER_HOST=unix://" + r.socketPath}
+}
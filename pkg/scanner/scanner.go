@@ -9,14 +9,17 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/aegis/aegis-cli/pkg/audit"
+	"github.com/aegis/aegis-cli/pkg/i18n"
 	"github.com/aegis/aegis-cli/pkg/models"
-	"github.com/docker/docker/api/types"
+	"github.com/aegis/aegis-cli/pkg/sbom"
 	"github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/client"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // TrivyVulnerability представляет уязвимость, найденную Trivy
@@ -35,12 +38,61 @@ type TrivyVulnerability struct {
 	} `json:"Layer,omitempty"`
 }
 
+// TrivySecret представляет секрет, найденный Trivy
+type TrivySecret struct {
+	RuleID    string `json:"RuleID"`
+	Category  string `json:"Category"`
+	Severity  string `json:"Severity"`
+	Title     string `json:"Title"`
+	StartLine int    `json:"StartLine"`
+	EndLine   int    `json:"EndLine"`
+	Match     string `json:"Match"` // Строка с секретом, замаскированным Trivy
+}
+
+// TrivyMisconfiguration представляет ошибку конфигурации, найденную Trivy
+type TrivyMisconfiguration struct {
+	Type          string `json:"Type"`
+	ID            string `json:"ID"`
+	AVDID         string `json:"AVDID,omitempty"`
+	Title         string `json:"Title"`
+	Description   string `json:"Description,omitempty"`
+	Message       string `json:"Message,omitempty"`
+	Resolution    string `json:"Resolution,omitempty"`
+	Severity      string `json:"Severity"`
+	Status        string `json:"Status"` // FAIL, PASS, EXCEPTION
+	CauseMetadata struct {
+		StartLine int `json:"StartLine,omitempty"`
+		EndLine   int `json:"EndLine,omitempty"`
+	} `json:"CauseMetadata,omitempty"`
+}
+
 // TrivyResult представляет результат сканирования для одного компонента
 type TrivyResult struct {
-	Target          string               `json:"Target"`
-	Class           string               `json:"Class"`
-	Type            string               `json:"Type"`
-	Vulnerabilities []TrivyVulnerability `json:"Vulnerabilities,omitempty"`
+	Target            string                  `json:"Target"`
+	Class             string                  `json:"Class"`
+	Type              string                  `json:"Type"`
+	Vulnerabilities   []TrivyVulnerability    `json:"Vulnerabilities,omitempty"`
+	Secrets           []TrivySecret           `json:"Secrets,omitempty"`
+	Misconfigurations []TrivyMisconfiguration `json:"Misconfigurations,omitempty"`
+}
+
+// TrivyMetadata представляет метаданные образа из отчета Trivy
+type TrivyMetadata struct {
+	ImageID     string   `json:"ImageID,omitempty"`
+	RepoTags    []string `json:"RepoTags,omitempty"`
+	RepoDigests []string `json:"RepoDigests,omitempty"`
+}
+
+// ImageDigest возвращает дайджест манифеста образа из RepoDigests (имя@sha256:...).
+// У образов, собранных локально и не загруженных в реестр, RepoDigests пуст -
+// тогда возвращается ID образа (дайджест конфигурации)
+func (m TrivyMetadata) ImageDigest() string {
+	for _, repoDigest := range m.RepoDigests {
+		if i := strings.LastIndex(repoDigest, "@"); i >= 0 && i < len(repoDigest)-1 {
+			return repoDigest[i+1:]
+		}
+	}
+	return m.ImageID
 }
 
 // TrivyReport представляет полный отчет сканирования Trivy
@@ -48,113 +100,107 @@ type TrivyReport struct {
 	SchemaVersion int           `json:"SchemaVersion"`
 	ArtifactName  string        `json:"ArtifactName"`
 	ArtifactType  string        `json:"ArtifactType"`
-	Metadata      interface{}   `json:"Metadata"`
+	Metadata      TrivyMetadata `json:"Metadata"`
 	Results       []TrivyResult `json:"Results"`
 }
 
-// Scanner представляет сканер контейнеров
-type Scanner struct {
-	dockerClient     *client.Client
-	logger           *logrus.Logger
-	resultsDir       string
-	concurrency      int
-	dockerSocketPath string
-	sem              chan struct{} // Семафор для ограничения параллелизма
+// ScanResult представляет результат сканирования контейнера
+type ScanResult struct {
+	Vulnerabilities []models.Vulnerability
+	Secrets         []models.Finding
+	Misconfigs      []models.Finding
+	ReportPath      string // Путь к JSON-отчету Trivy
+	ImageDigest     string // Дайджест манифеста образа, для образов вне реестра - ID образа
 }
 
-// NewScanner создает новый сканер
-func NewScanner(concurrency int, dockerSocketPath string) *Scanner {
-	logger := logrus.New()
-	logger.SetFormatter(&logrus.JSONFormatter{})
+// Типы сканеров Trivy
+const (
+	ScannerVuln      = models.ScannerVuln
+	ScannerSecret    = models.ScannerSecret
+	ScannerMisconfig = models.ScannerMisconfig
+)
 
-	// Настройка Docker клиента
-	cli, err := client.NewClientWithOpts(
-		client.WithHost(fmt.Sprintf("unix://%s", dockerSocketPath)),
-		client.WithAPIVersionNegotiation(),
-	)
-	if err != nil {
-		logger.WithError(err).Fatal("Failed to create Docker client")
+// DefaultScanners используется, если в запросе не указаны типы сканеров
+var DefaultScanners = []string{ScannerVuln}
+
+// ValidateScanners проверяет список типов сканеров
+func ValidateScanners(scanners []string) error {
+	for _, name := range scanners {
+		switch name {
+		case ScannerVuln, ScannerSecret, ScannerMisconfig:
+		default:
+			return i18n.Errorf("неизвестный тип сканера: %s (допустимо: vuln, secret, misconfig)", name)
+		}
+	}
+	return nil
+}
+
+// HasScanner проверяет, включен ли тип сканера
+func HasScanner(scanners []string, name string) bool {
+	for _, s := range scanners {
+		if s == name {
+			return true
+		}
 	}
+	return false
+}
 
+// Scanner представляет сканер контейнеров
+type Scanner struct {
+	runtime     ContainerRuntime
+	logger      *logrus.Logger
+	resultsDir  string
+	concurrency int
+	sem         chan struct{} // Семафор для ограничения параллелизма
+	queued      atomic.Int32  // Сканирования, ожидающие семафор
+}
+
+// NewScanner создает новый сканер для указанной среды выполнения контейнеров,
+// который пишет в журнал агента logger
+func NewScanner(concurrency int, runtime ContainerRuntime, logger *logrus.Logger) *Scanner {
 	// Создаем каталог для результатов сканирования
 	resultsDir := "/var/lib/aegis-agent/results"
 	if err := os.MkdirAll(resultsDir, 0755); err != nil {
 		logger.WithError(err).Fatal("Failed to create results directory")
 	}
+	scanConcurrency.Set(float64(concurrency))
 
 	return &Scanner{
-		dockerClient:     cli,
-		logger:           logger,
-		resultsDir:       resultsDir,
-		concurrency:      concurrency,
-		dockerSocketPath: dockerSocketPath,
-		sem:              make(chan struct{}, concurrency),
+		runtime:     runtime,
+		logger:      logger,
+		resultsDir:  resultsDir,
+		concurrency: concurrency,
+		sem:         make(chan struct{}, concurrency),
 	}
 }
 
-// ListContainers возвращает список контейнеров
-func (s *Scanner) ListContainers() ([]models.Container, error) {
-	ctx := context.Background()
-	containers, err := s.dockerClient.ContainerList(ctx, container.ListOptions{All: true})
-	if err != nil {
-		return nil, fmt.Errorf("ошибка получения списка контейнеров: %w", err)
-	}
-
-	var result []models.Container
-	for _, c := range containers {
-		name := ""
-		if len(c.Names) > 0 {
-			// Docker API возвращает имена с префиксом "/", удаляем его
-			name = strings.TrimPrefix(c.Names[0], "/")
-		}
-
-		container := models.Container{
-			ID:        c.ID,
-			HostID:    "local", // Временно используем "local" как ID хоста
-			Name:      name,
-			Image:     c.Image,
-			Status:    c.Status,
-			CreatedAt: time.Unix(c.Created, 0),
-			UpdatedAt: time.Now(),
-		}
-		result = append(result, container)
-	}
+// RuntimeName возвращает имя используемой среды выполнения контейнеров
+func (s *Scanner) RuntimeName() string {
+	return s.runtime.Name()
+}
 
-	return result, nil
+// ListContainers возвращает список контейнеров
+func (s *Scanner) ListContainers(ctx context.Context) ([]models.Container, error) {
+	return s.runtime.ListContainers(ctx, true)
 }
 
 // GetContainer возвращает информацию о контейнере по ID
-func (s *Scanner) GetContainer(id string) (*models.Container, error) {
-	ctx := context.Background()
-
+func (s *Scanner) GetContainer(ctx context.Context, id string) (*models.Container, error) {
 	// Сначала пробуем найти контейнер по точному ID
-	c, err := s.dockerClient.ContainerInspect(ctx, id)
+	c, err := s.runtime.InspectContainer(ctx, id)
 	if err == nil {
-		// Контейнер найден, возвращаем информацию
-		createdTime, _ := time.Parse(time.RFC3339, c.Created)
-
-		container := &models.Container{
-			ID:        c.ID,
-			HostID:    "local", // Временно используем "local" как ID хоста
-			Name:      strings.TrimPrefix(c.Name, "/"),
-			Image:     c.Config.Image,
-			Status:    c.State.Status,
-			CreatedAt: createdTime,
-			UpdatedAt: time.Now(),
-		}
-
-		return container, nil
+		return s.containerFromInspect(ctx, c), nil
 	}
 
 	// Если точное совпадение не найдено, пробуем найти по частичному ID
 	// Получаем список всех контейнеров
-	containers, err := s.dockerClient.ContainerList(ctx, container.ListOptions{All: true})
+	containers, err := s.runtime.ListContainers(ctx, true)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка получения списка контейнеров: %w", err)
+		return nil, err
 	}
 
 	// Ищем контейнеры, ID которых начинается с указанного префикса
-	var matchingContainers []types.Container
+	var matchingContainers []models.Container
 	for _, cont := range containers {
 		if strings.HasPrefix(cont.ID, id) {
 			matchingContainers = append(matchingContainers, cont)
@@ -163,68 +209,219 @@ func (s *Scanner) GetContainer(id string) (*models.Container, error) {
 
 	// Проверяем результаты поиска
 	if len(matchingContainers) == 0 {
-		return nil, fmt.Errorf("контейнер не найден: %s", id)
+		return nil, i18n.Errorf("контейнер не найден: %s", id)
 	}
 
 	if len(matchingContainers) > 1 {
 		// Найдено несколько совпадений, возвращаем ошибку с перечислением
 		var foundIDs []string
 		for _, cont := range matchingContainers {
-			name := ""
-			if len(cont.Names) > 0 {
-				name = strings.TrimPrefix(cont.Names[0], "/")
-			}
 			shortID := cont.ID
 			if len(cont.ID) > 12 {
 				shortID = cont.ID[:12]
 			}
-			foundIDs = append(foundIDs, fmt.Sprintf("%s (%s)", shortID, name))
+			foundIDs = append(foundIDs, fmt.Sprintf("%s (%s)", shortID, cont.Name))
 		}
-		return nil, fmt.Errorf("найдено несколько контейнеров с ID, начинающимся с %s: %s",
+		return nil, i18n.Errorf("найдено несколько контейнеров с ID, начинающимся с %s: %s",
 			id, strings.Join(foundIDs, ", "))
 	}
 
 	// Найден ровно один контейнер, получаем детальную информацию о нём
-	matchedContainer := matchingContainers[0]
-	c, err = s.dockerClient.ContainerInspect(ctx, matchedContainer.ID)
+	c, err = s.runtime.InspectContainer(ctx, matchingContainers[0].ID)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка получения информации о контейнере: %w", err)
+		return nil, i18n.Errorf("ошибка получения информации о контейнере: %w", err)
 	}
 
+	return s.containerFromInspect(ctx, c), nil
+}
+
+// containerFromInspect преобразует подробное описание контейнера в модель
+func (s *Scanner) containerFromInspect(ctx context.Context, c *container.InspectResponse) *models.Container {
 	// Преобразуем время создания из строки в time.Time
 	createdTime, _ := time.Parse(time.RFC3339, c.Created)
 
-	container := &models.Container{
+	result := &models.Container{
 		ID:        c.ID,
 		HostID:    "local", // Временно используем "local" как ID хоста
 		Name:      strings.TrimPrefix(c.Name, "/"),
-		Image:     c.Config.Image,
-		Status:    c.State.Status,
+		Runtime:   s.runtime.Name(),
 		CreatedAt: createdTime,
 		UpdatedAt: time.Now(),
 	}
+	if c.Config != nil {
+		result.Image = c.Config.Image
+	}
+	if c.State != nil {
+		result.Status = c.State.Status
+	}
+
+	if c.Config != nil && c.Config.Labels[labelPodName] != "" {
+		var podLabels map[string]string
+		if sandboxID := c.Config.Labels[labelSandboxID]; sandboxID != "" {
+			if sandbox, err := s.runtime.InspectContainer(ctx, sandboxID); err == nil && sandbox.Config != nil {
+				podLabels = sandbox.Config.Labels
+			}
+		}
+		applyKubernetesContext(result, c.Config.Labels, podLabels)
+	}
+
+	return result
+}
+
+// AuditContainers проверяет конфигурацию запущенных контейнеров по правилам аудита.
+// Если containerID пуст, проверяются все запущенные контейнеры
+func (s *Scanner) AuditContainers(ctx context.Context, containerID string) (*models.AuditResponse, error) {
+	var ids []string
+	if containerID != "" {
+		c, err := s.GetContainer(ctx, containerID)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, c.ID)
+	} else {
+		containers, err := s.runtime.ListContainers(ctx, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range containers {
+			ids = append(ids, c.ID)
+		}
+	}
+
+	response := &models.AuditResponse{
+		AuditedAt: time.Now(),
+		Findings:  []models.AuditFinding{},
+	}
+
+	for _, id := range ids {
+		info, err := s.runtime.InspectContainer(ctx, id)
+		if err != nil {
+			s.logger.WithError(err).WithField("container_id", id).Error("Failed to inspect container for audit")
+			continue
+		}
+
+		response.Containers++
+		response.Findings = append(response.Findings, audit.Check(info, "local")...)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"containers": response.Containers,
+		"findings":   len(response.Findings),
+	}).Info("Runtime audit completed")
+
+	return response, nil
+}
+
+// HostPosture собирает сведения о Docker daemon и проверяет настройки безопасности хоста.
+// Доступно для сред выполнения с Docker-совместимым API (Docker, Podman)
+func (s *Scanner) HostPosture(ctx context.Context) (*models.HostPosture, error) {
+	daemon, ok := s.runtime.(DaemonInfoProvider)
+	if !ok {
+		return nil, i18n.Errorf("проверка настроек daemon недоступна для среды выполнения %s", s.runtime.Name())
+	}
+
+	info, err := daemon.Info(ctx)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка получения информации о Docker daemon: %w", err)
+	}
+
+	version, err := daemon.ServerVersion(ctx)
+	if err != nil {
+		return nil, i18n.Errorf("ошибка получения версии Docker daemon: %w", err)
+	}
+
+	// Отсутствие daemon.json означает настройки по умолчанию
+	daemonConfig, err := os.ReadFile(audit.DaemonConfigPath)
+	if err != nil && !os.IsNotExist(err) {
+		s.logger.WithError(err).WithField("path", audit.DaemonConfigPath).Warn("Failed to read Docker daemon config")
+	}
 
-	return container, nil
+	return audit.CheckPosture(audit.PostureInput{
+		Info:         info,
+		Version:      version,
+		DaemonConfig: daemonConfig,
+		ContentTrust: os.Getenv("DOCKER_CONTENT_TRUST") == "1",
+	}), nil
 }
 
-// ScanContainer сканирует контейнер
-func (s *Scanner) ScanContainer(container *models.Container) ([]models.Vulnerability, error) {
+// ScanContainer сканирует контейнер указанными сканерами Trivy
+func (s *Scanner) ScanContainer(container *models.Container, scanners []string) (*ScanResult, error) {
+	return s.ScanContainerContext(context.Background(), container, scanners)
+}
+
+// ScanContainerContext сканирует контейнер, прерывая ожидание очереди и Trivy при отмене ctx.
+// Время ожидания в очереди входит в таймаут ctx
+func (s *Scanner) ScanContainerContext(ctx context.Context, container *models.Container, scanners []string) (*ScanResult, error) {
+	if len(scanners) == 0 {
+		scanners = DefaultScanners
+	}
+	if err := ValidateScanners(scanners); err != nil {
+		return nil, err
+	}
+
 	// Получаем семафор для ограничения параллелизма
-	s.sem <- struct{}{}
-	defer func() { <-s.sem }()
+	scansQueued.Inc()
+	s.queued.Add(1)
+	select {
+	case s.sem <- struct{}{}:
+		scansQueued.Dec()
+		s.queued.Add(-1)
+	case <-ctx.Done():
+		scansQueued.Dec()
+		s.queued.Add(-1)
+		return nil, ctx.Err()
+	}
+	scansRunning.Inc()
+	defer func() {
+		scansRunning.Dec()
+		<-s.sem
+	}()
 
 	s.logger.WithFields(logrus.Fields{
 		"container_id": container.ID,
 		"image":        container.Image,
+		"scanners":     scanners,
 	}).Info("Starting container scan")
 
 	// Генерируем уникальный ID для результатов сканирования
 	scanID := uuid.New().String()
 	resultsFile := filepath.Join(s.resultsDir, fmt.Sprintf("%s.json", scanID))
 
-	// Запускаем Trivy для сканирования образа контейнера
-	cmd := exec.Command("trivy", "image", "--format", "json", "--output", resultsFile, container.Image)
+	// Запускаем Trivy для сканирования образа контейнера.
+	// --list-all-pkgs сохраняет в отчете полный список пакетов, из которого затем строится SBOM
+	args := []string{"image", "--format", "json", "--list-all-pkgs", "--scanners", strings.Join(scanners, ",")}
+
+	// Секреты и ошибки конфигурации ищем также в конфигурации образа (ENV, история слоев)
+	var configScanners []string
+	for _, name := range []string{ScannerMisconfig, ScannerSecret} {
+		if HasScanner(scanners, name) {
+			configScanners = append(configScanners, name)
+		}
+	}
+	if len(configScanners) > 0 {
+		args = append(args, "--image-config-scanners", strings.Join(configScanners, ","))
+	}
+
+	// Источник образов и адрес сокета trivy берет у среды выполнения агента
+	args = append(args, s.runtime.TrivyArgs()...)
+
+	args = append(args, "--output", resultsFile, container.Image)
+	cmd := exec.CommandContext(ctx, "trivy", args...)
+	if env := s.runtime.TrivyEnv(); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	span := startTrivySpan(ctx, "image", cmd)
+	span.SetAttributes(
+		attribute.String("container.image.name", container.Image),
+		attribute.StringSlice("aegis.scanners", scanners),
+	)
+	start := time.Now()
 	output, err := cmd.CombinedOutput()
+	observeTrivy("image", cmd, start)
+	endTrivySpan(span, cmd, err)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		s.logger.WithFields(logrus.Fields{
 			"container_id": container.ID,
@@ -232,37 +429,52 @@ func (s *Scanner) ScanContainer(container *models.Container) ([]models.Vulnerabi
 			"error":        err,
 			"output":       string(output),
 		}).Error("Scan failed")
-		return nil, fmt.Errorf("ошибка сканирования: %w: %s", err, string(output))
+		return nil, i18n.Errorf("ошибка сканирования: %w: %s", err, string(output))
 	}
 
 	// Парсим результаты сканирования
-	vulnerabilities, err := s.parseResults(resultsFile, container.ID, container.HostID)
+	report, err := s.readReport(resultsFile)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка парсинга результатов: %w", err)
+		return nil, i18n.Errorf("ошибка парсинга результатов: %w", err)
 	}
+	vulnerabilities := s.parseResults(report, resultsFile, container.ID, container.HostID)
+	secrets, misconfigs := s.parseFindings(report, container.ID, container.HostID)
 
 	s.logger.WithFields(logrus.Fields{
 		"container_id":    container.ID,
 		"image":           container.Image,
 		"vulnerabilities": len(vulnerabilities),
+		"secrets":         len(secrets),
+		"misconfigs":      len(misconfigs),
 		"results_file":    resultsFile,
 	}).Info("Scan completed")
 
-	return vulnerabilities, nil
+	return &ScanResult{
+		Vulnerabilities: vulnerabilities,
+		Secrets:         secrets,
+		Misconfigs:      misconfigs,
+		ReportPath:      resultsFile,
+		ImageDigest:     report.Metadata.ImageDigest(),
+	}, nil
 }
 
-// parseResults парсит результаты сканирования Trivy
-func (s *Scanner) parseResults(resultsFile, containerID, hostID string) ([]models.Vulnerability, error) {
+// readReport читает JSON-отчет Trivy
+func (s *Scanner) readReport(resultsFile string) (*TrivyReport, error) {
 	data, err := os.ReadFile(resultsFile)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка чтения файла результатов: %w", err)
+		return nil, i18n.Errorf("ошибка чтения файла результатов: %w", err)
 	}
 
 	var report TrivyReport
 	if err := json.Unmarshal(data, &report); err != nil {
-		return nil, fmt.Errorf("ошибка разбора JSON: %w", err)
+		return nil, i18n.Errorf("ошибка разбора JSON: %w", err)
 	}
 
+	return &report, nil
+}
+
+// parseResults извлекает уязвимости из отчета Trivy
+func (s *Scanner) parseResults(report *TrivyReport, resultsFile, containerID, hostID string) []models.Vulnerability {
 	var vulnerabilities []models.Vulnerability
 	for _, result := range report.Results {
 		if result.Vulnerabilities == nil {
@@ -313,14 +525,69 @@ func (s *Scanner) parseResults(resultsFile, containerID, hostID string) ([]model
 		}
 	}
 
-	return vulnerabilities, nil
+	return vulnerabilities
+}
+
+// parseFindings извлекает секреты и ошибки конфигурации из отчета Trivy
+func (s *Scanner) parseFindings(report *TrivyReport, containerID, hostID string) ([]models.Finding, []models.Finding) {
+	var secrets, misconfigs []models.Finding
+
+	for _, result := range report.Results {
+		for _, secret := range result.Secrets {
+			secrets = append(secrets, models.Finding{
+				ID:           uuid.New().String(),
+				ContainerID:  containerID,
+				HostID:       hostID,
+				Kind:         models.FindingKindSecret,
+				RuleID:       secret.RuleID,
+				Severity:     secret.Severity,
+				Title:        secret.Title,
+				Description:  secret.Category,
+				Target:       result.Target,
+				Location:     secret.Match,
+				StartLine:    secret.StartLine,
+				EndLine:      secret.EndLine,
+				DiscoveredAt: time.Now(),
+			})
+		}
+
+		for _, misconfig := range result.Misconfigurations {
+			// Trivy включает в отчет и успешно пройденные проверки
+			if misconfig.Status != "" && misconfig.Status != "FAIL" {
+				continue
+			}
+
+			description := misconfig.Message
+			if description == "" {
+				description = misconfig.Description
+			}
+
+			misconfigs = append(misconfigs, models.Finding{
+				ID:           uuid.New().String(),
+				ContainerID:  containerID,
+				HostID:       hostID,
+				Kind:         models.FindingKindMisconfig,
+				RuleID:       misconfig.ID,
+				Severity:     misconfig.Severity,
+				Title:        misconfig.Title,
+				Description:  description,
+				Target:       result.Target,
+				StartLine:    misconfig.CauseMetadata.StartLine,
+				EndLine:      misconfig.CauseMetadata.EndLine,
+				Resolution:   misconfig.Resolution,
+				DiscoveredAt: time.Now(),
+			})
+		}
+	}
+
+	return secrets, misconfigs
 }
 
 // ScanAllContainers сканирует все контейнеры на хосте
 func (s *Scanner) ScanAllContainers() (map[string][]models.Vulnerability, error) {
-	containers, err := s.ListContainers()
+	containers, err := s.ListContainers(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("ошибка получения списка контейнеров: %w", err)
+		return nil, i18n.Errorf("ошибка получения списка контейнеров: %w", err)
 	}
 
 	results := make(map[string][]models.Vulnerability)
@@ -332,7 +599,7 @@ func (s *Scanner) ScanAllContainers() (map[string][]models.Vulnerability, error)
 		go func(c models.Container) {
 			defer wg.Done()
 
-			vulnerabilities, err := s.ScanContainer(&c)
+			result, err := s.ScanContainer(&c, DefaultScanners)
 			if err != nil {
 				s.logger.WithFields(logrus.Fields{
 					"container_id": c.ID,
@@ -343,7 +610,7 @@ func (s *Scanner) ScanAllContainers() (map[string][]models.Vulnerability, error)
 			}
 
 			mu.Lock()
-			results[c.ID] = vulnerabilities
+			results[c.ID] = result.Vulnerabilities
 			mu.Unlock()
 		}(container)
 	}
@@ -352,25 +619,70 @@ func (s *Scanner) ScanAllContainers() (map[string][]models.Vulnerability, error)
 	return results, nil
 }
 
+// GenerateSBOM строит SBOM в указанном формате из JSON-отчета Trivy.
+// ctx используется только для трассировки: конвертация не прерывается
+func (s *Scanner) GenerateSBOM(ctx context.Context, reportPath, format string) (string, error) {
+	trivyFormat, err := sbom.TrivyFormat(format)
+	if err != nil {
+		return "", err
+	}
+
+	sbomFile := strings.TrimSuffix(reportPath, ".json") + "." + format + ".json"
+
+	// Trivy конвертирует собственный отчет в CycloneDX/SPDX без повторного сканирования образа
+	cmd := exec.Command("trivy", "convert", "--format", trivyFormat, "--output", sbomFile, reportPath)
+	span := startTrivySpan(ctx, "convert", cmd)
+	span.SetAttributes(attribute.String("aegis.sbom_format", format))
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	observeTrivy("convert", cmd, start)
+	endTrivySpan(span, cmd, err)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"report": reportPath,
+			"format": format,
+			"error":  err,
+			"output": string(output),
+		}).Error("SBOM generation failed")
+		return "", i18n.Errorf("ошибка генерации SBOM: %w: %s", err, string(output))
+	}
+
+	return sbomFile, nil
+}
+
+// GenerateSBOMs строит SBOM во всех поддерживаемых форматах.
+// Ошибки отдельных форматов не прерывают генерацию остальных
+func (s *Scanner) GenerateSBOMs(ctx context.Context, reportPath string) map[string]string {
+	files := make(map[string]string)
+	for _, format := range sbom.Formats {
+		path, err := s.GenerateSBOM(ctx, reportPath, format)
+		if err != nil {
+			continue
+		}
+		files[format] = path
+	}
+	return files
+}
+
 // ExportResultsToCSV экспортирует результаты сканирования в CSV
 func (s *Scanner) ExportResultsToCSV(vulnerabilities []models.Vulnerability, outputFile string) error {
 	// Создаем каталог для файла, если он не существует
 	dir := filepath.Dir(outputFile)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("ошибка создания каталога: %w", err)
+		return i18n.Errorf("ошибка создания каталога: %w", err)
 	}
 
 	// Открываем файл для записи
 	file, err := os.Create(outputFile)
 	if err != nil {
-		return fmt.Errorf("ошибка создания файла: %w", err)
+		return i18n.Errorf("ошибка создания файла: %w", err)
 	}
 	defer file.Close()
 
 	// Записываем заголовок CSV
 	header := "ID,VulnerabilityID,Severity,Title,Package,InstalledVersion,FixedVersion,Description\n"
 	if _, err := file.WriteString(header); err != nil {
-		return fmt.Errorf("ошибка записи заголовка: %w", err)
+		return i18n.Errorf("ошибка записи заголовка: %w", err)
 	}
 
 	// Записываем данные
@@ -382,7 +694,7 @@ func (s *Scanner) ExportResultsToCSV(vulnerabilities []models.Vulnerability, out
 		line := fmt.Sprintf("\"%s\",\"%s\",\"%s\",\"%s\",\"%s\",\"%s\",\"%s\",\"%s\"\n",
 			v.ID, v.VulnerabilityID, v.Severity, title, v.Package, v.InstalledVersion, v.FixedVersion, description)
 		if _, err := file.WriteString(line); err != nil {
-			return fmt.Errorf("ошибка записи данных: %w", err)
+			return i18n.Errorf("ошибка записи данных: %w", err)
 		}
 	}
 
@@ -0,0 +1,25 @@
+package scanner
+
+import "testing"
+
+func TestTrivyMetadataImageDigest(t *testing.T) {
+	const (
+		imageID        = "sha256:5a1b7d3c9e8f"
+		manifestDigest = "sha256:0d5f8e2a4b6c"
+	)
+
+	for _, tc := range []struct {
+		name     string
+		metadata TrivyMetadata
+		want     string
+	}{
+		{"образ из реестра", TrivyMetadata{ImageID: imageID, RepoDigests: []string{"docker.io/library/nginx@" + manifestDigest}}, manifestDigest},
+		{"реестр с портом", TrivyMetadata{ImageID: imageID, RepoDigests: []string{"registry.local:5000/shop/api@" + manifestDigest}}, manifestDigest},
+		{"локально собранный образ", TrivyMetadata{ImageID: imageID}, imageID},
+		{"некорректный RepoDigests", TrivyMetadata{ImageID: imageID, RepoDigests: []string{"nginx@"}}, imageID},
+	} {
+		if got := tc.metadata.ImageDigest(); got != tc.want {
+			t.Errorf("%s: ImageDigest() = %q, ожидается %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+)
+
+// DefaultAPIURL - адрес Telegram Bot API по умолчанию
+const DefaultAPIURL = "https://api.telegram.org"
+
+// Client выполняет запросы к Telegram Bot API
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// Update представляет входящее обновление Telegram
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message,omitempty"`
+}
+
+// Message представляет входящее сообщение
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	From      *User  `json:"from,omitempty"`
+	Text      string `json:"text"`
+}
+
+// Chat представляет чат, из которого пришло сообщение
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// User представляет отправителя сообщения
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username,omitempty"`
+}
+
+// apiResponse - общий формат ответа Bot API
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	Description string          `json:"description"`
+}
+
+// NewClient создает клиент Bot API. Пустой baseURL означает api.telegram.org
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		// Таймаут задается контекстом запроса: long polling держит соединение дольше обычного
+		httpClient: &http.Client{},
+	}
+}
+
+// GetUpdates ожидает новые обновления не дольше timeout (long polling)
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	params := url.Values{}
+	params.Set("offset", strconv.FormatInt(offset, 10))
+	params.Set("timeout", strconv.Itoa(int(timeout.Seconds())))
+	params.Set("allowed_updates", `["message"]`)
+
+	// Запас в 10 секунд на ответ сервера после истечения таймаута ожидания
+	ctx, cancel := context.WithTimeout(ctx, timeout+10*time.Second)
+	defer cancel()
+
+	var updates []Update
+	if err := c.call(ctx, "getUpdates", "application/x-www-form-urlencoded", strings.NewReader(params.Encode()), &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// SendMessage отправляет сообщение с HTML-разметкой
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return i18n.Errorf("ошибка маршалинга JSON: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	return c.call(ctx, "sendMessage", "application/json", bytes.NewReader(data), nil)
+}
+
+// SendDocument отправляет файл с подписью в HTML-разметке
+func (c *Client) SendDocument(ctx context.Context, chatID int64, filePath, caption string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return i18n.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("chat_id", strconv.FormatInt(chatID, 10))
+	if caption != "" {
+		form.WriteField("caption", caption)
+		form.WriteField("parse_mode", "HTML")
+	}
+	part, err := form.CreateFormFile("document", filepath.Base(filePath))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return i18n.Errorf("ошибка чтения файла: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	return c.call(ctx, "sendDocument", form.FormDataContentType(), &body, nil)
+}
+
+// call выполняет метод Bot API и декодирует поле result в out
+func (c *Client) call(ctx context.Context, method, contentType string, body io.Reader, out interface{}) error {
+	endpoint := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return i18n.Errorf("ошибка создания HTTP запроса: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Не выводим URL запроса: он содержит токен бота
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return i18n.Errorf("ошибка запроса %s к Telegram API: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return i18n.Errorf("ошибка чтения ответа Telegram API: %w", err)
+	}
+
+	var result apiResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return i18n.Errorf("некорректный ответ Telegram API (статус %d)", resp.StatusCode)
+	}
+	if !result.OK {
+		return i18n.Errorf("ошибка API Telegram (%s): %s", method, result.Description)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(result.Result, out); err != nil {
+			return i18n.Errorf("ошибка декодирования ответа Telegram API: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,788 @@
+package telegram
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/agentclient"
+	"github.com/aegis/aegis-cli/pkg/config"
+	"github.com/aegis/aegis-cli/pkg/db"
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/sbom"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultPollTimeout - время ожидания обновлений в одном запросе getUpdates
+const DefaultPollTimeout = 30 * time.Second
+
+// Ограничения размера ответов, чтобы не превысить лимит сообщения Telegram (4096 символов)
+const (
+	topLimit        = 10
+	scanReplyLimit  = 30
+	errorRetryDelay = 5 * time.Second
+)
+
+// helpText - список команд бота
+const helpText = `<b>Aegis</b>: команды бота
+
+/hosts - список хостов
+/scan ХОСТ КОНТЕЙНЕР|all - запуск сканирования
+/status SCAN_ID - статус и результаты сканирования
+/top ХОСТ - самые опасные уязвимости хоста
+/report КОНТЕЙНЕР - CSV-отчет по последнему сканированию
+
+Хост указывается по имени или ID, контейнер - по имени или префиксу ID.`
+
+// Bot отвечает на команды в Telegram, используя локальную БД и API агентов
+type Bot struct {
+	client      *Client
+	store       *db.Store
+	logger      *logrus.Logger
+	allowed     map[int64]bool
+	pollTimeout time.Duration
+	wg          sync.WaitGroup
+}
+
+// command обрабатывает команду и возвращает текст ответа в HTML-разметке.
+// Пустой ответ означает, что команда ответила сама (например, отправила файл)
+type command func(ctx context.Context, chatID int64, args []string) (string, error)
+
+// NewBot создает бота по конфигурации CLI. Токен берется из telegram_bot_token
+// или notification.telegram_token, список разрешенных чатов - из telegram_allowed_chats
+// и чатов, настроенных для уведомлений
+func NewBot(cfg *config.CliConfig, store *db.Store, logger *logrus.Logger) (*Bot, error) {
+	token := cfg.TelegramBotToken
+	if token == "" {
+		token = cfg.Notification.TelegramToken
+	}
+	if token == "" {
+		return nil, i18n.Errorf("не указан токен бота (telegram_bot_token)")
+	}
+
+	allowed := make(map[int64]bool)
+	for _, id := range cfg.TelegramAllowed {
+		allowed[id] = true
+	}
+	for _, id := range []string{cfg.TelegramChatID, cfg.Notification.TelegramChatID} {
+		if id == "" {
+			continue
+		}
+		chatID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			// Чат может быть задан по имени канала (@channel) - такие чаты бот не обслуживает
+			logger.WithField("chat_id", id).Warn("ID чата не является числом и не добавлен в список разрешенных")
+			continue
+		}
+		allowed[chatID] = true
+	}
+	if len(allowed) == 0 {
+		return nil, i18n.Errorf("не задан ни один разрешенный чат (telegram_allowed_chats)")
+	}
+
+	return &Bot{
+		client:      NewClient(cfg.TelegramAPIURL, token),
+		store:       store,
+		logger:      logger,
+		allowed:     allowed,
+		pollTimeout: DefaultPollTimeout,
+	}, nil
+}
+
+// SetPollTimeout задает время ожидания обновлений в одном запросе getUpdates
+func (b *Bot) SetPollTimeout(timeout time.Duration) {
+	if timeout >= time.Second {
+		b.pollTimeout = timeout
+	}
+}
+
+// AllowedChats возвращает отсортированный список разрешенных чатов
+func (b *Bot) AllowedChats() []int64 {
+	chats := make([]int64, 0, len(b.allowed))
+	for id := range b.allowed {
+		chats = append(chats, id)
+	}
+	sort.Slice(chats, func(i, j int) bool { return chats[i] < chats[j] })
+	return chats
+}
+
+// Run получает обновления методом long polling и обрабатывает команды до отмены контекста.
+// Перед возвратом дожидается завершения уже начатых команд
+func (b *Bot) Run(ctx context.Context) error {
+	defer b.wg.Wait()
+
+	// Начатые команды доводятся до конца и после остановки бота
+	handlerCtx := context.WithoutCancel(ctx)
+
+	var offset int64
+	for {
+		updates, err := b.client.GetUpdates(ctx, offset, b.pollTimeout)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			b.logger.WithError(err).Error("Ошибка получения обновлений Telegram")
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(errorRetryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message == nil || update.Message.Text == "" {
+				continue
+			}
+
+			message := update.Message
+			b.wg.Add(1)
+			go func() {
+				defer b.wg.Done()
+				b.handleMessage(handlerCtx, message)
+			}()
+		}
+	}
+}
+
+// handleMessage проверяет доступ к боту и выполняет команду из сообщения
+func (b *Bot) handleMessage(ctx context.Context, message *Message) {
+	chatID := message.Chat.ID
+	logger := b.logger.WithFields(logrus.Fields{
+		"chat_id": chatID,
+		"text":    message.Text,
+	})
+
+	if !b.allowed[chatID] {
+		logger.Warn("Команда из неразрешенного чата отклонена")
+		b.reply(ctx, chatID, i18n.Sprintf("Доступ запрещен. ID этого чата: <code>%d</code>", chatID))
+		return
+	}
+
+	fields := strings.Fields(message.Text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return
+	}
+
+	// В группах команда может быть адресована боту: /hosts@aegis_bot
+	name := fields[0]
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+
+	commands := map[string]command{
+		"/hosts":  b.cmdHosts,
+		"/scan":   b.cmdScan,
+		"/status": b.cmdStatus,
+		"/top":    b.cmdTop,
+		"/report": b.cmdReport,
+	}
+
+	var text string
+	switch cmd, ok := commands[name]; {
+	case name == "/start" || name == "/help":
+		text = i18n.T(helpText)
+	case !ok:
+		text = i18n.T("Неизвестная команда. Список команд: /help")
+	default:
+		logger.Info("Выполнение команды Telegram-бота")
+		reply, err := cmd(ctx, chatID, fields[1:])
+		if err != nil {
+			logger.WithError(err).Error("Ошибка выполнения команды Telegram-бота")
+			reply = i18n.T("Ошибка: ") + html.EscapeString(err.Error())
+		}
+		text = reply
+	}
+
+	if text != "" {
+		b.reply(ctx, chatID, text)
+	}
+}
+
+// reply отправляет ответ в чат
+func (b *Bot) reply(ctx context.Context, chatID int64, text string) {
+	if err := b.client.SendMessage(ctx, chatID, text); err != nil {
+		b.logger.WithError(err).WithField("chat_id", chatID).Error("Ошибка отправки ответа в Telegram")
+	}
+}
+
+// cmdHosts выводит список хостов
+func (b *Bot) cmdHosts(ctx context.Context, chatID int64, args []string) (string, error) {
+	hosts, err := b.store.ListHosts()
+	if err != nil {
+		return "", i18n.Errorf("ошибка получения списка хостов: %w", err)
+	}
+	if len(hosts) == 0 {
+		return i18n.T("Хосты не добавлены. Используйте <code>aegis hosts add</code>"), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(i18n.T("<b>Хосты</b>\n"))
+	for _, host := range hosts {
+		icon := "🔴"
+		if host.Status == "online" {
+			icon = "🟢"
+		}
+		fmt.Fprintf(&sb, "\n%s <b>%s</b> %s:%d\n<code>%s</code>", icon, html.EscapeString(host.Name),
+			html.EscapeString(host.Address), host.Port, host.ID)
+		if !host.LastSeen.IsZero() {
+			i18n.Fprintf(&sb, ", на связи %s", host.LastSeen.Format("2006-01-02 15:04"))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// cmdScan запускает сканирование одного или всех контейнеров хоста
+func (b *Bot) cmdScan(ctx context.Context, chatID int64, args []string) (string, error) {
+	if len(args) < 2 {
+		return i18n.T("Использование: <code>/scan ХОСТ КОНТЕЙНЕР|all</code>"), nil
+	}
+
+	host, err := b.findHost(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	client := agentclient.New(host)
+	containers, err := client.ListContainers()
+	if err != nil {
+		return "", i18n.Errorf("агент %s недоступен: %w", host.Name, err)
+	}
+	b.syncContainers(host, containers)
+
+	targets := containers
+	if args[1] != "all" {
+		container, err := matchContainer(containers, args[1])
+		if err != nil {
+			return "", err
+		}
+		targets = []models.Container{*container}
+	}
+	if len(targets) == 0 {
+		return i18n.Sprintf("На хосте <b>%s</b> нет контейнеров", html.EscapeString(host.Name)), nil
+	}
+
+	var started, failed []string
+	for _, container := range targets {
+		scanID, err := client.StartScan(models.ScanRequest{ContainerID: container.ID})
+		if err == nil {
+			err = b.store.AddScan(&models.Scan{
+				ID:          scanID,
+				HostID:      host.ID,
+				ContainerID: container.ID,
+				Status:      "pending",
+				StartedAt:   time.Now(),
+			})
+		}
+		if err != nil {
+			b.logger.WithError(err).WithFields(logrus.Fields{
+				"host_id":      host.ID,
+				"container_id": container.ID,
+			}).Error("Ошибка запуска сканирования из Telegram")
+			failed = append(failed, fmt.Sprintf("%s: %s", html.EscapeString(container.Name), html.EscapeString(err.Error())))
+			continue
+		}
+		started = append(started, fmt.Sprintf("%s - <code>%s</code>", html.EscapeString(container.Name), scanID))
+	}
+
+	var sb strings.Builder
+	i18n.Fprintf(&sb, "<b>Сканирование на хосте %s</b>\n", html.EscapeString(host.Name))
+	if len(started) > 0 {
+		i18n.Fprintf(&sb, "\nЗапущено: %d\n", len(started))
+		writeLimited(&sb, started, scanReplyLimit)
+	}
+	if len(failed) > 0 {
+		i18n.Fprintf(&sb, "\nНе удалось запустить: %d\n", len(failed))
+		writeLimited(&sb, failed, scanReplyLimit)
+	}
+	if len(started) > 0 {
+		sb.WriteString(i18n.T("\nСтатус: <code>/status SCAN_ID</code>"))
+	}
+	return sb.String(), nil
+}
+
+// cmdStatus выводит статус сканирования. Незавершенное сканирование запрашивается у агента,
+// а результаты завершенного сохраняются в БД так же, как в aegis scan status
+func (b *Bot) cmdStatus(ctx context.Context, chatID int64, args []string) (string, error) {
+	if len(args) < 1 {
+		return i18n.T("Использование: <code>/status SCAN_ID</code>"), nil
+	}
+
+	scan, err := b.store.GetScan(args[0])
+	if err != nil {
+		return "", err
+	}
+	host, err := b.store.GetHost(scan.HostID)
+	if err != nil {
+		return "", i18n.Errorf("хост сканирования не найден: %w", err)
+	}
+	container, _ := b.store.GetContainer(scan.ContainerID)
+
+	if scan.Status != "completed" && scan.Status != "failed" {
+		if err := b.refreshScan(host, scan, container); err != nil {
+			return "", err
+		}
+	}
+
+	containerName := scan.ContainerID
+	if container != nil {
+		containerName = container.Name
+	}
+
+	var sb strings.Builder
+	i18n.Fprintf(&sb, "<b>Сканирование</b> <code>%s</code>\n", scan.ID)
+	i18n.Fprintf(&sb, "Хост: %s\n", html.EscapeString(host.Name))
+	i18n.Fprintf(&sb, "Контейнер: %s\n", html.EscapeString(containerName))
+	i18n.Fprintf(&sb, "Статус: <b>%s</b>\n", scan.Status)
+	i18n.Fprintf(&sb, "Начало: %s\n", scan.StartedAt.Format("2006-01-02 15:04:05"))
+	if scan.FinishedAt.After(scan.StartedAt) && (scan.Status == "completed" || scan.Status == "failed") {
+		i18n.Fprintf(&sb, "Длительность: %s\n", scan.FinishedAt.Sub(scan.StartedAt).Round(time.Second))
+	}
+
+	switch scan.Status {
+	case "failed":
+		if scan.ErrorMsg != "" {
+			i18n.Fprintf(&sb, "Ошибка: %s\n", html.EscapeString(scan.ErrorMsg))
+		}
+	case "completed":
+		vulns, err := b.store.ListVulnerabilities("", "", scan.ID, "")
+		if err != nil {
+			return "", i18n.Errorf("ошибка получения уязвимостей: %w", err)
+		}
+		var counts models.SeverityCounts
+		for _, vuln := range vulns {
+			counts.Add(vuln.Severity)
+		}
+		i18n.Fprintf(&sb, "\n<b>Уязвимости: %d</b>\n%s\n", counts.Total, formatCounts(counts))
+
+		if findings, err := b.store.ListFindings("", "", "", scan.ID, ""); err == nil && len(findings) > 0 {
+			var secrets, misconfigs int
+			for _, finding := range findings {
+				if finding.Kind == models.FindingKindSecret {
+					secrets++
+				} else {
+					misconfigs++
+				}
+			}
+			i18n.Fprintf(&sb, "Секретов: %d, ошибок конфигурации: %d\n", secrets, misconfigs)
+		}
+		if container != nil {
+			i18n.Fprintf(&sb, "\nОтчет: <code>/report %s</code>", html.EscapeString(container.Name))
+		}
+	default:
+		sb.WriteString(i18n.T("\nСканирование выполняется, повторите команду позже"))
+	}
+	return sb.String(), nil
+}
+
+// refreshScan запрашивает статус сканирования у агента, обновляет его в БД
+// и сохраняет результаты завершенного сканирования
+func (b *Bot) refreshScan(host *models.Host, scan *models.Scan, container *models.Container) error {
+	client := agentclient.New(host)
+	status, err := client.GetScanStatus(scan.ID)
+	if err != nil {
+		return i18n.Errorf("не удалось получить статус у агента %s: %w", host.Name, err)
+	}
+
+	scan.Status = status.Status
+	if status.FinishedAt != nil {
+		scan.FinishedAt = *status.FinishedAt
+	}
+	if status.ErrorMsg != "" {
+		scan.ErrorMsg = status.ErrorMsg
+	}
+	if status.ImageDigest != "" {
+		scan.ImageDigest = status.ImageDigest
+	}
+	if err := b.store.UpdateScan(scan); err != nil {
+		b.logger.WithError(err).WithField("scan_id", scan.ID).Error("Ошибка обновления информации о сканировании")
+	}
+
+	if scan.Status != "completed" {
+		return nil
+	}
+
+	logger := b.logger.WithFields(logrus.Fields{
+		"scan_id": scan.ID,
+		"host_id": host.ID,
+	})
+
+	for _, vuln := range status.Vulnerabilities {
+		vuln.ID = uuid.New().String()
+		vuln.ScanID = scan.ID
+		vuln.ContainerID = scan.ContainerID
+		vuln.HostID = scan.HostID
+		vuln.DiscoveredAt = time.Now()
+
+		if err := b.store.AddVulnerability(&vuln); err != nil {
+			logger.WithError(err).WithField("vulnerability_id", vuln.VulnerabilityID).Error("Ошибка сохранения информации об уязвимости")
+		}
+	}
+
+	findings := append(append([]models.Finding{}, status.Secrets...), status.Misconfigs...)
+	for _, finding := range findings {
+		finding.ID = uuid.New().String()
+		finding.ScanID = scan.ID
+		finding.ContainerID = scan.ContainerID
+		finding.HostID = scan.HostID
+		finding.DiscoveredAt = time.Now()
+
+		if err := b.store.AddFinding(&finding); err != nil {
+			logger.WithError(err).WithField("rule_id", finding.RuleID).Error("Ошибка сохранения находки")
+		}
+	}
+
+	// Сохраняем SBOM образа
+	image := ""
+	if container != nil {
+		image = container.Image
+	}
+	for _, format := range status.SBOMFormats {
+		data, err := client.GetSBOM(scan.ID, format)
+		if err != nil {
+			logger.WithError(err).WithField("format", format).Error("Ошибка загрузки SBOM")
+			continue
+		}
+		record, packages, err := sbom.NewRecord(scan, image, format, data)
+		if err != nil {
+			logger.WithError(err).WithField("format", format).Error("Ошибка разбора SBOM")
+			continue
+		}
+		if err := b.store.SaveSBOM(record, packages); err != nil {
+			logger.WithError(err).WithField("format", format).Error("Ошибка сохранения SBOM")
+		}
+	}
+
+	return nil
+}
+
+// topEntry - уязвимость хоста, объединенная по всем контейнерам
+type topEntry struct {
+	vuln       models.Vulnerability
+	containers map[string]bool
+}
+
+// cmdTop выводит самые опасные уязвимости хоста по последним завершенным
+// сканированиям его контейнеров
+func (b *Bot) cmdTop(ctx context.Context, chatID int64, args []string) (string, error) {
+	if len(args) < 1 {
+		return i18n.T("Использование: <code>/top ХОСТ</code>"), nil
+	}
+
+	host, err := b.findHost(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	scans, err := b.store.ListScans(host.ID, "")
+	if err != nil {
+		return "", i18n.Errorf("ошибка получения сканирований: %w", err)
+	}
+
+	// Сканирования отсортированы от новых к старым: берем первое завершенное для каждого контейнера
+	entries := make(map[string]*topEntry)
+	seen := make(map[string]bool)
+	for _, scan := range scans {
+		if scan.Status != "completed" || seen[scan.ContainerID] {
+			continue
+		}
+		seen[scan.ContainerID] = true
+
+		vulns, err := b.store.ListVulnerabilities("", "", scan.ID, "")
+		if err != nil {
+			return "", i18n.Errorf("ошибка получения уязвимостей: %w", err)
+		}
+		for _, vuln := range vulns {
+			key := vuln.VulnerabilityID + "|" + vuln.Package
+			entry, ok := entries[key]
+			if !ok {
+				entry = &topEntry{vuln: vuln, containers: make(map[string]bool)}
+				entries[key] = entry
+			}
+			entry.containers[scan.ContainerID] = true
+		}
+	}
+
+	if len(seen) == 0 {
+		return i18n.Sprintf("Для хоста <b>%s</b> нет завершенных сканирований", html.EscapeString(host.Name)), nil
+	}
+	if len(entries) == 0 {
+		return i18n.Sprintf("На хосте <b>%s</b> уязвимости не найдены", html.EscapeString(host.Name)), nil
+	}
+
+	top := make([]*topEntry, 0, len(entries))
+	for _, entry := range entries {
+		top = append(top, entry)
+	}
+	sort.Slice(top, func(i, j int) bool {
+		ri, rj := severityRank(top[i].vuln.Severity), severityRank(top[j].vuln.Severity)
+		if ri != rj {
+			return ri > rj
+		}
+		if len(top[i].containers) != len(top[j].containers) {
+			return len(top[i].containers) > len(top[j].containers)
+		}
+		return top[i].vuln.VulnerabilityID < top[j].vuln.VulnerabilityID
+	})
+
+	var sb strings.Builder
+	i18n.Fprintf(&sb, "<b>Уязвимости хоста %s</b> (всего %d)\n", html.EscapeString(host.Name), len(top))
+	for i, entry := range top {
+		if i == topLimit {
+			break
+		}
+		vuln := entry.vuln
+		fmt.Fprintf(&sb, "\n%d. %s <b>%s</b> %s\n%s %s", i+1, severityIcon(vuln.Severity),
+			html.EscapeString(vuln.VulnerabilityID), strings.ToUpper(vuln.Severity),
+			html.EscapeString(vuln.Package), html.EscapeString(vuln.InstalledVersion))
+		if vuln.FixedVersion != "" {
+			fmt.Fprintf(&sb, " → %s", html.EscapeString(vuln.FixedVersion))
+		}
+		i18n.Fprintf(&sb, ", контейнеров: %d\n", len(entry.containers))
+	}
+	return sb.String(), nil
+}
+
+// cmdReport отправляет CSV-отчет по последнему завершенному сканированию контейнера
+func (b *Bot) cmdReport(ctx context.Context, chatID int64, args []string) (string, error) {
+	if len(args) < 1 {
+		return i18n.T("Использование: <code>/report КОНТЕЙНЕР</code>"), nil
+	}
+
+	container, err := b.findContainer(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	scans, err := b.store.ListScans("", container.ID)
+	if err != nil {
+		return "", i18n.Errorf("ошибка получения сканирований: %w", err)
+	}
+	var scan *models.Scan
+	for i := range scans {
+		if scans[i].Status == "completed" {
+			scan = &scans[i]
+			break
+		}
+	}
+	if scan == nil {
+		return i18n.Sprintf("Для контейнера <b>%s</b> нет завершенных сканирований", html.EscapeString(container.Name)), nil
+	}
+
+	vulns, err := b.store.ListVulnerabilities("", "", scan.ID, "")
+	if err != nil {
+		return "", i18n.Errorf("ошибка получения уязвимостей: %w", err)
+	}
+	if len(vulns) == 0 {
+		return i18n.Sprintf("В контейнере <b>%s</b> уязвимости не найдены (сканирование <code>%s</code>)",
+			html.EscapeString(container.Name), scan.ID), nil
+	}
+
+	dir, err := os.MkdirTemp("", "aegis-report-")
+	if err != nil {
+		return "", i18n.Errorf("ошибка создания временной директории: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := fmt.Sprintf("aegis-%s-%s.csv", reportFileName(container.Name), scan.StartedAt.Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	if err := writeReport(path, vulns); err != nil {
+		return "", err
+	}
+
+	var counts models.SeverityCounts
+	for _, vuln := range vulns {
+		counts.Add(vuln.Severity)
+	}
+	caption := i18n.Sprintf("<b>%s</b>, сканирование от %s\n%s", html.EscapeString(container.Name),
+		scan.StartedAt.Format("2006-01-02 15:04"), formatCounts(counts))
+
+	if err := b.client.SendDocument(ctx, chatID, path, caption); err != nil {
+		return "", i18n.Errorf("не удалось отправить отчет: %w", err)
+	}
+	return "", nil
+}
+
+// findHost ищет хост по ID, имени или префиксу ID
+func (b *Bot) findHost(ref string) (*models.Host, error) {
+	hosts, err := b.store.ListHosts()
+	if err != nil {
+		return nil, i18n.Errorf("ошибка получения списка хостов: %w", err)
+	}
+
+	var matches []models.Host
+	for _, host := range hosts {
+		if host.ID == ref || strings.EqualFold(host.Name, ref) {
+			return &host, nil
+		}
+		if strings.HasPrefix(host.ID, ref) {
+			matches = append(matches, host)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, i18n.Errorf("хост не найден: %s", ref)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, i18n.Errorf("найдено несколько хостов с ID, начинающимся с %s", ref)
+	}
+}
+
+// findContainer ищет сохраненный в БД контейнер по ID, префиксу ID или имени
+func (b *Bot) findContainer(ref string) (*models.Container, error) {
+	if container, err := b.store.GetContainer(ref); err == nil {
+		return container, nil
+	}
+
+	hosts, err := b.store.ListHosts()
+	if err != nil {
+		return nil, i18n.Errorf("ошибка получения списка хостов: %w", err)
+	}
+	var all []models.Container
+	for _, host := range hosts {
+		containers, err := b.store.ListContainers(host.ID)
+		if err != nil {
+			return nil, i18n.Errorf("ошибка получения контейнеров: %w", err)
+		}
+		all = append(all, containers...)
+	}
+	return matchContainer(all, ref)
+}
+
+// syncContainers сохраняет в БД контейнеры, полученные от агента, чтобы
+// на них могли ссылаться сканирования
+func (b *Bot) syncContainers(host *models.Host, containers []models.Container) {
+	for _, container := range containers {
+		container.HostID = host.ID
+		container.UpdatedAt = time.Now()
+
+		var err error
+		if existing, getErr := b.store.GetContainer(container.ID); getErr == nil && existing.ID == container.ID {
+			container.CreatedAt = existing.CreatedAt
+			err = b.store.UpdateContainer(&container)
+		} else {
+			container.CreatedAt = time.Now()
+			err = b.store.AddContainer(&container)
+		}
+		if err != nil {
+			b.logger.WithError(err).WithFields(logrus.Fields{
+				"host_id":      host.ID,
+				"container_id": container.ID,
+			}).Error("Ошибка сохранения контейнера в БД")
+		}
+	}
+}
+
+// matchContainer ищет контейнер по ID, имени или префиксу ID
+func matchContainer(containers []models.Container, ref string) (*models.Container, error) {
+	var matches []models.Container
+	for _, container := range containers {
+		if container.ID == ref || strings.TrimPrefix(container.Name, "/") == strings.TrimPrefix(ref, "/") {
+			return &container, nil
+		}
+		if len(ref) >= 3 && strings.HasPrefix(container.ID, ref) {
+			matches = append(matches, container)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, i18n.Errorf("контейнер не найден: %s", ref)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, i18n.Errorf("найдено несколько контейнеров с ID, начинающимся с %s", ref)
+	}
+}
+
+// writeReport сохраняет уязвимости в CSV в том же формате, что и экспорт в TUI
+func writeReport(path string, vulns []models.Vulnerability) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return i18n.Errorf("ошибка создания отчета: %w", err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"ID", "VulnerabilityID", "Severity", "Title", "Package", "InstalledVersion", "FixedVersion", "Description"})
+	for _, v := range vulns {
+		w.Write([]string{v.ID, v.VulnerabilityID, v.Severity, v.Title, v.Package, v.InstalledVersion, v.FixedVersion, v.Description})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return i18n.Errorf("ошибка записи отчета: %w", err)
+	}
+	return file.Close()
+}
+
+// reportFileName заменяет в имени контейнера символы, недопустимые в имени файла
+func reportFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, strings.TrimPrefix(name, "/"))
+}
+
+// writeLimited выводит не более limit строк списка
+func writeLimited(sb *strings.Builder, lines []string, limit int) {
+	for i, line := range lines {
+		if i == limit {
+			i18n.Fprintf(sb, "... и еще %d\n", len(lines)-limit)
+			return
+		}
+		fmt.Fprintf(sb, "• %s\n", line)
+	}
+}
+
+// formatCounts форматирует количество находок по уровням серьезности
+func formatCounts(c models.SeverityCounts) string {
+	return fmt.Sprintf("🔴 %d  🟠 %d  🟡 %d  🟢 %d", c.Critical, c.High, c.Medium, c.Low)
+}
+
+// severityRank возвращает вес уровня серьезности для сортировки
+func severityRank(severity string) int {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return 4
+	case "HIGH":
+		return 3
+	case "MEDIUM":
+		return 2
+	case "LOW":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// severityIcon возвращает значок уровня серьезности
+func severityIcon(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return "🔴"
+	case "HIGH":
+		return "🟠"
+	case "MEDIUM":
+		return "🟡"
+	case "LOW":
+		return "🟢"
+	default:
+		return "⚪"
+	}
+}
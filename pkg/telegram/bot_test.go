@@ -0,0 +1,248 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/config"
+	"github.com/aegis/aegis-cli/pkg/db"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	testToken       = "123:test"
+	allowedChat     = int64(42)
+	otherChat       = int64(999)
+	testScanID      = "7f1c2b3a-scan"
+	testContainerID = "3f4e5d6c7b8a9f0e"
+)
+
+// sentMessage - сообщение, отправленное ботом через sendMessage
+type sentMessage struct {
+	ChatID int64  `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+// botAPI - заглушка Bot API: getUpdates отдает пакеты обновлений из updates,
+// sendMessage передает сообщения в sent
+type botAPI struct {
+	updates chan []Update
+	sent    chan sentMessage
+	nextID  int64
+}
+
+func newBotAPI(t *testing.T) (*botAPI, *httptest.Server) {
+	t.Helper()
+
+	api := &botAPI{updates: make(chan []Update, 4), sent: make(chan sentMessage, 16)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bot" + testToken + "/getUpdates":
+			var result []Update
+			select {
+			case result = <-api.updates:
+			case <-r.Context().Done():
+				return
+			case <-time.After(200 * time.Millisecond):
+			}
+			writeResult(w, result)
+		case "/bot" + testToken + "/sendMessage":
+			var message sentMessage
+			if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			api.sent <- message
+			writeResult(w, map[string]int64{"message_id": 1})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"ok":false,"description":"Not Found"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return api, server
+}
+
+func writeResult(w http.ResponseWriter, result interface{}) {
+	data, _ := json.Marshal(result)
+	json.NewEncoder(w).Encode(apiResponse{OK: true, Result: data})
+}
+
+// send ставит в очередь сообщения от чата chatID
+func (a *botAPI) send(chatID int64, texts ...string) {
+	var updates []Update
+	for _, text := range texts {
+		a.nextID++
+		updates = append(updates, Update{
+			UpdateID: a.nextID,
+			Message:  &Message{MessageID: a.nextID, Chat: Chat{ID: chatID, Type: "private"}, Text: text},
+		})
+	}
+	a.updates <- updates
+}
+
+// replies ожидает n сообщений бота
+func (a *botAPI) replies(t *testing.T, n int) []sentMessage {
+	t.Helper()
+
+	var messages []sentMessage
+	for len(messages) < n {
+		select {
+		case message := <-a.sent:
+			messages = append(messages, message)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("получено %d ответов из %d: %+v", len(messages), n, messages)
+		}
+	}
+	return messages
+}
+
+// agentStandIn - заглушка API агента с одним контейнером и сканированием testScanID
+func agentStandIn(t *testing.T) (string, int) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/containers":
+			json.NewEncoder(w).Encode(models.ContainerListResponse{Containers: []models.Container{{ID: testContainerID, Name: "api", Image: "nginx:1.25", Status: "Up"}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/scan":
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(map[string]string{"scan_id": testScanID})
+		case r.Method == http.MethodGet && r.URL.Path == "/scan/"+testScanID:
+			finished := time.Now()
+			json.NewEncoder(w).Encode(models.ScanStatusResponse{
+				ScanID:     testScanID,
+				Status:     "completed",
+				StartedAt:  finished.Add(-time.Minute),
+				FinishedAt: &finished,
+				Vulnerabilities: []models.Vulnerability{
+					{VulnerabilityID: "CVE-2024-0001", Package: "openssl", Severity: "CRITICAL"},
+					{VulnerabilityID: "CVE-2024-0002", Package: "zlib", Severity: "HIGH"},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	host, port, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	portNumber, _ := strconv.Atoi(port)
+	return host, portNumber
+}
+
+func TestBotCommands(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	cfg := &config.CliConfig{DatabaseType: "sqlite", SQLitePath: filepath.Join(t.TempDir(), "aegis.db")}
+	store, err := db.NewStore(cfg, logger)
+	if err != nil {
+		t.Fatalf("db.NewStore: %v", err)
+	}
+	defer store.Close()
+
+	agentHost, agentPort := agentStandIn(t)
+	if err := store.AddHost(&models.Host{ID: "host-1", Name: "node-1", Address: agentHost, Port: agentPort, Status: "online"}); err != nil {
+		t.Fatalf("AddHost: %v", err)
+	}
+
+	api, server := newBotAPI(t)
+	cfg.TelegramBotToken = testToken
+	cfg.TelegramAPIURL = server.URL
+	cfg.TelegramAllowed = []int64{allowedChat}
+	bot, err := NewBot(cfg, store, logger)
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+	bot.SetPollTimeout(time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- bot.Run(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run: %v", err)
+		}
+	}()
+
+	// Команда из чата вне списка разрешенных не выполняется
+	api.send(otherChat, "/hosts")
+	denied := api.replies(t, 1)[0]
+	if denied.ChatID != otherChat || !strings.Contains(denied.Text, "Доступ запрещен") ||
+		!strings.Contains(denied.Text, strconv.FormatInt(otherChat, 10)) || strings.Contains(denied.Text, "node-1") {
+		t.Errorf("ответ неразрешенному чату: %+v", denied)
+	}
+
+	api.send(allowedChat, "/hosts", "/scan node-1 api")
+	byCommand := make(map[string]string)
+	for _, message := range api.replies(t, 2) {
+		if message.ChatID != allowedChat {
+			t.Errorf("ответ в чат %d, ожидается %d", message.ChatID, allowedChat)
+		}
+		if strings.Contains(message.Text, "<b>Хосты</b>") {
+			byCommand["/hosts"] = message.Text
+		} else {
+			byCommand["/scan"] = message.Text
+		}
+	}
+	if hosts := byCommand["/hosts"]; !strings.Contains(hosts, "node-1") || !strings.Contains(hosts, "host-1") {
+		t.Errorf("ответ /hosts: %q", hosts)
+	}
+	if scan := byCommand["/scan"]; !strings.Contains(scan, "Запущено: 1") || !strings.Contains(scan, testScanID) {
+		t.Errorf("ответ /scan: %q", scan)
+	}
+	if _, err := store.GetScan(testScanID); err != nil {
+		t.Errorf("сканирование не сохранено: %v", err)
+	}
+
+	// Незавершенное сканирование запрашивается у агента, результаты сохраняются в БД
+	api.send(allowedChat, "/status "+testScanID)
+	status := api.replies(t, 1)[0].Text
+	for _, want := range []string{testScanID, "node-1", "api", "<b>completed</b>", "Уязвимости: 2", "/report api"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("в ответе /status нет %q: %q", want, status)
+		}
+	}
+	if vulns, err := store.ListVulnerabilities("", "", testScanID, ""); err != nil || len(vulns) != 2 {
+		t.Errorf("сохранено уязвимостей %d (%v), ожидается 2", len(vulns), err)
+	}
+
+	api.send(allowedChat, "/unknown")
+	if reply := api.replies(t, 1)[0].Text; !strings.Contains(reply, "/help") {
+		t.Errorf("ответ на неизвестную команду: %q", reply)
+	}
+}
+
+func TestNewBotRequiresAllowedChats(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	if _, err := NewBot(&config.CliConfig{TelegramBotToken: testToken}, nil, logger); err == nil {
+		t.Error("бот без разрешенных чатов создан")
+	}
+	// Чат уведомлений разрешается автоматически, имя канала пропускается
+	bot, err := NewBot(&config.CliConfig{
+		TelegramBotToken: testToken,
+		TelegramChatID:   "@aegis_channel",
+		Notification:     models.NotificationConfig{TelegramChatID: "-100500"},
+	}, nil, logger)
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+	if chats := bot.AllowedChats(); len(chats) != 1 || chats[0] != -100500 {
+		t.Errorf("разрешенные чаты: %v", chats)
+	}
+}
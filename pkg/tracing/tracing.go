@@ -0,0 +1,196 @@
+// Package tracing настраивает трассировку OpenTelemetry для CLI и агента: экспорт span
+// по OTLP/HTTP, в stdout или файл и передачу контекста трассировки W3C (traceparent)
+// в запросах CLI к агенту
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/logging"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Экспортеры span
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"   // OTLP/HTTP, например в OpenTelemetry Collector или Jaeger
+	ExporterStdout = "stdout" // JSON в stdout, для отладки
+	ExporterFile   = "file"   // JSON в файл, по одному span на строку
+)
+
+// Exporters содержит все поддерживаемые экспортеры
+var Exporters = []string{ExporterNone, ExporterOTLP, ExporterStdout, ExporterFile}
+
+// instrumentationName - имя, под которым Aegis создает span
+const instrumentationName = "github.com/aegis/aegis-cli"
+
+// Options - параметры трассировки
+type Options struct {
+	Exporter       string  // none, otlp, stdout, file; пусто - трассировка выключена
+	Endpoint       string  // Адрес OTLP/HTTP (host:port или URL); пусто - OTEL_EXPORTER_OTLP_ENDPOINT или localhost:4318
+	Insecure       bool    // OTLP по HTTP без TLS
+	File           string  // Файл для экспортера file
+	SampleRatio    float64 // Доля трассировок, начатых в этом процессе; 0 - все
+	ServiceName    string
+	ServiceVersion string
+	Logger         *logrus.Logger // Журнал ошибок экспорта span; nil - стандартный вывод OpenTelemetry в stderr
+}
+
+// Shutdown отправляет накопленные span и останавливает экспортер
+type Shutdown func(ctx context.Context) error
+
+// Setup настраивает глобальный TracerProvider и распространение контекста W3C.
+// При выключенной трассировке span не создаются, но контекст входящих запросов
+// по-прежнему передается дальше
+func Setup(ctx context.Context, opts Options) (Shutdown, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var file io.Closer
+	switch opts.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var clientOpts []otlptracehttp.Option
+		switch {
+		case strings.Contains(opts.Endpoint, "://"):
+			clientOpts = append(clientOpts, otlptracehttp.WithEndpointURL(opts.Endpoint))
+		case opts.Endpoint != "":
+			clientOpts = append(clientOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+		otlp, err := otlptracehttp.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, i18n.Errorf("ошибка создания экспортера OTLP: %w", err)
+		}
+		exporter = otlp
+	case ExporterStdout:
+		stdout, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, i18n.Errorf("ошибка создания экспортера трассировки: %w", err)
+		}
+		exporter = stdout
+	case ExporterFile:
+		if opts.File == "" {
+			return nil, i18n.Errorf("для экспортера трассировки file не указан tracing.file")
+		}
+		out, err := logging.OpenRotatingFile(logging.ExpandHome(opts.File), 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		stdout, err := stdouttrace.New(stdouttrace.WithWriter(out))
+		if err != nil {
+			out.Close()
+			return nil, i18n.Errorf("ошибка создания экспортера трассировки: %w", err)
+		}
+		exporter = stdout
+		file = out
+	default:
+		return nil, i18n.Errorf("неизвестный экспортер трассировки: %s (допустимо: %s)", opts.Exporter, strings.Join(Exporters, ", "))
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+		semconv.ServiceVersion(opts.ServiceVersion),
+	)
+
+	// Решение о записи трассировки, начатой в CLI, агент берет из traceparent
+	sampler := sdktrace.AlwaysSample()
+	if opts.SampleRatio > 0 && opts.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(opts.SampleRatio)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	)
+	otel.SetTracerProvider(provider)
+	if opts.Logger != nil {
+		logger := opts.Logger
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+			logger.WithError(err).Warn("Ошибка экспорта трассировки")
+		}))
+	}
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			file.Close()
+		}
+		return err
+	}, nil
+}
+
+// defaultParent - span, к которому привязываются span без родителя в контексте
+var (
+	defaultParentMu sync.RWMutex
+	defaultParent   trace.SpanContext
+)
+
+// SetDefaultParent делает span из ctx родителем span, контекст которых не содержит span.
+// CLI задает так span выполняемой команды: большинство запросов к агенту (http.Get, http.Post)
+// и хуки CLI выполняются без контекста
+func SetDefaultParent(ctx context.Context) {
+	defaultParentMu.Lock()
+	defaultParent = trace.SpanContextFromContext(ctx)
+	defaultParentMu.Unlock()
+}
+
+// Start начинает span с указанным именем
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		defaultParentMu.RLock()
+		parent := defaultParent
+		defaultParentMu.RUnlock()
+		if parent.IsValid() {
+			ctx = trace.ContextWithSpanContext(ctx, parent)
+		}
+	}
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End завершает span, отмечая ошибку, если она есть
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceID возвращает ID трассировки из контекста или пустую строку, если трассировки нет
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
+
+// Extract восстанавливает контекст трассировки из заголовков входящего запроса
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Inject добавляет контекст трассировки в заголовки исходящего запроса
+func Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
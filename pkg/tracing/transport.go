@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// transport создает клиентский span для каждого запроса и передает контекст
+// трассировки в заголовках traceparent и tracestate
+type transport struct {
+	base http.RoundTripper
+}
+
+// NewTransport оборачивает base (nil - http.DefaultTransport) трассировкой запросов
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.Redacted()),
+			attribute.String("server.address", req.URL.Hostname()),
+		),
+	)
+	req = req.Clone(ctx)
+	Inject(ctx, req.Header)
+
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= http.StatusInternalServerError {
+			err = httpStatusError(resp.StatusCode)
+			End(span, err)
+			return resp, nil
+		}
+	}
+	End(span, err)
+	return resp, err
+}
+
+// httpStatusError отмечает span ответа с кодом 5xx как ошибочный
+type httpStatusError int
+
+func (e httpStatusError) Error() string {
+	return "HTTP " + strconv.Itoa(int(e))
+}
@@ -11,15 +11,21 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aegis/aegis-cli/pkg/agentclient"
 	"github.com/aegis/aegis-cli/pkg/config"
 	"github.com/aegis/aegis-cli/pkg/db"
+	"github.com/aegis/aegis-cli/pkg/i18n"
 	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/aegis/aegis-cli/pkg/sbom"
 	"github.com/aegis/aegis-cli/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/jroimartin/gocui"
 	"github.com/sirupsen/logrus"
 )
 
+// failedHooksLimit ограничивает число неудачных выполнений хуков в панели исправлений
+const failedHooksLimit = 10
+
 // TUI представляет терминальный пользовательский интерфейс
 type TUI struct {
 	g                   *gocui.Gui
@@ -30,12 +36,16 @@ type TUI struct {
 	containers          []models.Container
 	vulns               []models.Vulnerability
 	activeHost          *models.Host
+	activeContainer     *models.Container // Выбранный контейнер для подстановки в команды стратегий
 	activePanel         string
 	notificationManager *utils.NotificationManager
 	logs                []string                     // Добавлено хранилище для логов
 	strategies          []models.RemediationStrategy // Добавлено хранилище для стратегий решения
 	hooks               []models.Hook                // Добавлено хранилище для хуков
+	failedHooks         []models.HookExecution       // Последние неудачные выполнения хуков
 	telegramConnected   bool                         // Статус подключения Telegram-бота
+	auditTab            bool                         // Нижняя левая панель показывает результаты аудита
+	auditFindings       []models.AuditFinding        // Результаты аудита конфигурации контейнеров
 	modalWindows        []string                     // Стек для отслеживания модальных окон
 }
 
@@ -62,7 +72,7 @@ func (t *TUI) Run() error {
 	if err != nil {
 		// Логируем ошибку и выводим дополнительную информацию
 		t.logger.WithError(err).Error("Ошибка инициализации gocui")
-		return fmt.Errorf("ошибка инициализации интерфейса: %w", err)
+		return i18n.Errorf("ошибка инициализации интерфейса: %w", err)
 	}
 	defer t.g.Close()
 
@@ -74,9 +84,9 @@ func (t *TUI) Run() error {
 	t.g.SelBgColor = gocui.ColorBlack // Устанавливаем цвет фона выбранного элемента
 
 	// Добавляем тестовые логи сразу после инициализации
-	t.addLog("Инициализация системы")
-	t.addLog("TUI запущен")
-	t.addLog("Готов к работе")
+	t.addLog(i18n.T("Инициализация системы"))
+	t.addLog(i18n.T("TUI запущен"))
+	t.addLog(i18n.T("Готов к работе"))
 
 	// Настройка интерфейса
 	t.g.SetManagerFunc(t.layout)
@@ -103,7 +113,7 @@ func (t *TUI) Run() error {
 	// Запуск основного цикла с обработкой ошибок
 	if err := t.g.MainLoop(); err != nil && err != gocui.ErrQuit {
 		t.logger.WithError(err).Error("Ошибка в основном цикле TUI")
-		return fmt.Errorf("ошибка работы интерфейса: %w", err)
+		return i18n.Errorf("ошибка работы интерфейса: %w", err)
 	}
 
 	return nil
@@ -120,10 +130,10 @@ func (t *TUI) layout(g *gocui.Gui) error {
 			if err != gocui.ErrUnknownView {
 				return err
 			}
-			v.Title = "Ошибка размера"
-			fmt.Fprintln(v, "Размер терминала слишком мал.")
-			fmt.Fprintln(v, "Требуется минимум 80x24.")
-			fmt.Fprintln(v, "Увеличьте размер окна.")
+			v.Title = i18n.T("Ошибка размера")
+			fmt.Fprintln(v, i18n.T("Размер терминала слишком мал."))
+			fmt.Fprintln(v, i18n.T("Требуется минимум 80x24."))
+			fmt.Fprintln(v, i18n.T("Увеличьте размер окна."))
 			return nil
 		}
 		return nil
@@ -141,7 +151,7 @@ func (t *TUI) layout(g *gocui.Gui) error {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
-		hostsView.Title = "Хосты"
+		hostsView.Title = i18n.T("Хосты")
 		hostsView.Highlight = true
 		hostsView.SelBgColor = gocui.ColorGreen
 		hostsView.SelFgColor = gocui.ColorBlack
@@ -161,7 +171,7 @@ func (t *TUI) layout(g *gocui.Gui) error {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
-		containersView.Title = "Контейнеры"
+		containersView.Title = i18n.T("Контейнеры")
 		containersView.Highlight = true
 		containersView.SelBgColor = gocui.ColorGreen
 		containersView.SelFgColor = gocui.ColorBlack
@@ -170,7 +180,7 @@ func (t *TUI) layout(g *gocui.Gui) error {
 		if t.activeHost != nil {
 			t.renderContainers(containersView)
 		} else {
-			fmt.Fprintln(containersView, "Выберите хост")
+			fmt.Fprintln(containersView, i18n.T("Выберите хост"))
 		}
 	}
 
@@ -179,7 +189,7 @@ func (t *TUI) layout(g *gocui.Gui) error {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
-		vulnsView.Title = "Уязвимости"
+		vulnsView.Title = i18n.T("Уязвимости")
 		vulnsView.Wrap = true
 		vulnsView.Autoscroll = false
 		vulnsView.Editable = false // Отключаем режим редактирования
@@ -192,7 +202,7 @@ func (t *TUI) layout(g *gocui.Gui) error {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
-		logsView.Title = "Логи"
+		logsView.Title = i18n.T("Логи")
 		logsView.Wrap = true
 		logsView.Autoscroll = true
 		logsView.Editable = false // Отключаем режим редактирования
@@ -206,10 +216,10 @@ func (t *TUI) layout(g *gocui.Gui) error {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
-		statusView.Title = "Статус"
+		statusView.Title = i18n.T("Статус")
 		statusView.Wrap = true
 		statusView.Editable = false // Отключаем режим редактирования
-		fmt.Fprintln(statusView, "F1:Помощь | F2:Сканировать | F3:Экспорт | F4:Хуки | F5:Обновить | F6:Telegram | F10:Выход")
+		fmt.Fprintln(statusView, i18n.T("F1:Помощь | F2:Сканировать | F3:Экспорт | F4:Хуки | F5:Обновить | F6:Telegram | F7:Аудит | F10:Выход"))
 	}
 
 	// Проверяем, есть ли открытые модальные окна
@@ -296,6 +306,16 @@ func (t *TUI) setupKeybindings() error {
 		return err
 	}
 
+	if err := t.g.SetKeybinding("", gocui.KeyF7, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+		// Проверяем, есть ли открытые модальные окна
+		if len(t.modalWindows) > 0 {
+			return nil
+		}
+		return t.toggleAuditTab(g, v)
+	}); err != nil {
+		return err
+	}
+
 	if err := t.g.SetKeybinding("", gocui.KeyF10, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
 		// Всегда позволяем выйти
 		return t.quit(g, v)
@@ -406,26 +426,27 @@ func (t *TUI) toggleHelp(g *gocui.Gui, v *gocui.View) error {
 		return err
 	}
 
-	helpView.Title = "Помощь"
+	helpView.Title = i18n.T("Помощь")
 	helpView.Wrap = true
 	helpView.Editable = false
 	helpView.Clear()
 
-	fmt.Fprintln(helpView, "Горячие клавиши:")
-	fmt.Fprintln(helpView, "  F1: Показать/скрыть справку")
-	fmt.Fprintln(helpView, "  F2: Запустить сканирование выбранного контейнера")
-	fmt.Fprintln(helpView, "  F3: Экспортировать отчет о уязвимостях")
-	fmt.Fprintln(helpView, "  F4: Показать хуки и стратегии исправления")
-	fmt.Fprintln(helpView, "  F5: Обновить данные")
-	fmt.Fprintln(helpView, "  F6: Информация о Telegram-боте")
-	fmt.Fprintln(helpView, "  F10: Выход из TUI")
+	fmt.Fprintln(helpView, i18n.T("Горячие клавиши:"))
+	fmt.Fprintln(helpView, i18n.T("  F1: Показать/скрыть справку"))
+	fmt.Fprintln(helpView, i18n.T("  F2: Запустить сканирование выбранного контейнера"))
+	fmt.Fprintln(helpView, i18n.T("  F3: Экспортировать отчет о уязвимостях"))
+	fmt.Fprintln(helpView, i18n.T("  F4: Показать хуки и стратегии исправления"))
+	fmt.Fprintln(helpView, i18n.T("  F5: Обновить данные"))
+	fmt.Fprintln(helpView, i18n.T("  F6: Информация о Telegram-боте"))
+	fmt.Fprintln(helpView, i18n.T("  F7: Переключение вкладки уязвимости/аудит конфигурации"))
+	fmt.Fprintln(helpView, i18n.T("  F10: Выход из TUI"))
 	fmt.Fprintln(helpView, "")
-	fmt.Fprintln(helpView, "Навигация:")
-	fmt.Fprintln(helpView, "  Tab: Переключение между панелями")
-	fmt.Fprintln(helpView, "  Стрелки ↑/↓: Перемещение по списку/прокрутка")
-	fmt.Fprintln(helpView, "  PgUp/PgDn: Быстрая прокрутка уязвимостей и логов")
-	fmt.Fprintln(helpView, "  Enter: Выбор элемента")
-	fmt.Fprintln(helpView, "  Esc: Закрыть текущую панель диалога")
+	fmt.Fprintln(helpView, i18n.T("Навигация:"))
+	fmt.Fprintln(helpView, i18n.T("  Tab: Переключение между панелями"))
+	fmt.Fprintln(helpView, i18n.T("  Стрелки ↑/↓: Перемещение по списку/прокрутка"))
+	fmt.Fprintln(helpView, i18n.T("  PgUp/PgDn: Быстрая прокрутка уязвимостей и логов"))
+	fmt.Fprintln(helpView, i18n.T("  Enter: Выбор элемента"))
+	fmt.Fprintln(helpView, i18n.T("  Esc: Закрыть текущую панель диалога"))
 
 	// Сохраняем активную панель перед переключением на help
 	if g.CurrentView() != nil && g.CurrentView().Name() != "help" {
@@ -474,7 +495,7 @@ func (t *TUI) closeHelp(g *gocui.Gui, v *gocui.View) error {
 // startScan запускает сканирование
 func (t *TUI) startScan(g *gocui.Gui, v *gocui.View) error {
 	if t.activeHost == nil {
-		t.updateStatus("Ошибка: не выбран хост")
+		t.updateStatus(i18n.T("Ошибка: не выбран хост"))
 		return nil
 	}
 
@@ -488,13 +509,13 @@ func (t *TUI) startScan(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	if selectedContainer == nil {
-		t.updateStatus("Ошибка: не выбран контейнер")
+		t.updateStatus(i18n.T("Ошибка: не выбран контейнер"))
 		return nil
 	}
 
 	// Обновляем статус и логи
-	t.updateStatus(fmt.Sprintf("Запуск сканирования контейнера %s...", selectedContainer.Name))
-	t.addLog(fmt.Sprintf("Запуск сканирования контейнера %s на хосте %s", selectedContainer.Name, t.activeHost.Name))
+	t.updateStatus(i18n.Sprintf("Запуск сканирования контейнера %s...", selectedContainer.Name))
+	t.addLog(i18n.Sprintf("Запуск сканирования контейнера %s на хосте %s", selectedContainer.Name, t.activeHost.Name))
 
 	// Запускаем сканирование в отдельной горутине
 	go func() {
@@ -510,18 +531,18 @@ func (t *TUI) startScan(g *gocui.Gui, v *gocui.View) error {
 		jsonData, err := json.Marshal(scanReq)
 		if err != nil {
 			t.logger.WithError(err).Error("Ошибка сериализации запроса")
-			t.updateStatusAsync("Ошибка: не удалось подготовить запрос")
-			t.addLogAsync(fmt.Sprintf("Ошибка сериализации запроса: %v", err))
+			t.updateStatusAsync(i18n.T("Ошибка: не удалось подготовить запрос"))
+			t.addLogAsync(i18n.Sprintf("Ошибка сериализации запроса: %v", err))
 			return
 		}
 
 		// Выполнение POST запроса
-		t.addLogAsync("Отправка запроса на сканирование агенту...")
+		t.addLogAsync(i18n.T("Отправка запроса на сканирование агенту..."))
 		resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
 		if err != nil {
 			t.logger.WithError(err).Error("Ошибка запроса к агенту")
-			t.updateStatusAsync("Ошибка: не удалось подключиться к агенту")
-			t.addLogAsync(fmt.Sprintf("Ошибка запроса к агенту: %v", err))
+			t.updateStatusAsync(i18n.T("Ошибка: не удалось подключиться к агенту"))
+			t.addLogAsync(i18n.Sprintf("Ошибка запроса к агенту: %v", err))
 			return
 		}
 		defer resp.Body.Close()
@@ -529,8 +550,8 @@ func (t *TUI) startScan(g *gocui.Gui, v *gocui.View) error {
 		// Проверка статуса ответа
 		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 			t.logger.WithField("status_code", resp.StatusCode).Error("Агент вернул ошибку")
-			t.updateStatusAsync(fmt.Sprintf("Ошибка: агент вернул статус %d", resp.StatusCode))
-			t.addLogAsync(fmt.Sprintf("Агент вернул ошибку: статус %d", resp.StatusCode))
+			t.updateStatusAsync(i18n.Sprintf("Ошибка: агент вернул статус %d", resp.StatusCode))
+			t.addLogAsync(i18n.Sprintf("Агент вернул ошибку: статус %d", resp.StatusCode))
 			return
 		}
 
@@ -538,8 +559,8 @@ func (t *TUI) startScan(g *gocui.Gui, v *gocui.View) error {
 		var scanResp models.ScanResponse
 		if err := json.NewDecoder(resp.Body).Decode(&scanResp); err != nil {
 			t.logger.WithError(err).Error("Ошибка декодирования ответа агента")
-			t.updateStatusAsync("Ошибка: не удалось прочитать ответ агента")
-			t.addLogAsync(fmt.Sprintf("Ошибка декодирования ответа агента: %v", err))
+			t.updateStatusAsync(i18n.T("Ошибка: не удалось прочитать ответ агента"))
+			t.addLogAsync(i18n.Sprintf("Ошибка декодирования ответа агента: %v", err))
 			return
 		}
 
@@ -554,11 +575,11 @@ func (t *TUI) startScan(g *gocui.Gui, v *gocui.View) error {
 
 		if err := t.store.AddScan(scan); err != nil {
 			t.logger.WithError(err).Error("Ошибка сохранения информации о сканировании")
-			t.addLogAsync(fmt.Sprintf("Ошибка сохранения информации о сканировании: %v", err))
+			t.addLogAsync(i18n.Sprintf("Ошибка сохранения информации о сканировании: %v", err))
 		}
 
-		t.updateStatusAsync(fmt.Sprintf("Сканирование запущено: ID=%s", scanResp.ScanID))
-		t.addLogAsync(fmt.Sprintf("Сканирование успешно запущено: ID=%s", scanResp.ScanID))
+		t.updateStatusAsync(i18n.Sprintf("Сканирование запущено: ID=%s", scanResp.ScanID))
+		t.addLogAsync(i18n.Sprintf("Сканирование успешно запущено: ID=%s", scanResp.ScanID))
 
 		// Запускаем мониторинг статуса
 		t.monitorScanStatus(scanResp.ScanID)
@@ -573,21 +594,21 @@ func (t *TUI) monitorScanStatus(scanID string) {
 	scan, err := t.store.GetScan(scanID)
 	if err != nil {
 		t.logger.WithError(err).Error("Ошибка получения информации о сканировании")
-		t.addLogAsync(fmt.Sprintf("Ошибка получения информации о сканировании: %v", err))
+		t.addLogAsync(i18n.Sprintf("Ошибка получения информации о сканировании: %v", err))
 		return
 	}
 
 	host, err := t.store.GetHost(scan.HostID)
 	if err != nil {
 		t.logger.WithError(err).Error("Ошибка получения информации о хосте")
-		t.addLogAsync(fmt.Sprintf("Ошибка получения информации о хосте: %v", err))
+		t.addLogAsync(i18n.Sprintf("Ошибка получения информации о хосте: %v", err))
 		return
 	}
 
 	container, err := t.store.GetContainer(scan.ContainerID)
 	if err != nil {
 		t.logger.WithError(err).Error("Ошибка получения информации о контейнере")
-		t.addLogAsync(fmt.Sprintf("Ошибка получения информации о контейнере: %v", err))
+		t.addLogAsync(i18n.Sprintf("Ошибка получения информации о контейнере: %v", err))
 		return
 	}
 
@@ -604,18 +625,18 @@ func (t *TUI) monitorScanStatus(scanID string) {
 		select {
 		case <-statusCheckTicker.C:
 			// Выполнение GET запроса
-			t.addLogAsync("Проверка статуса сканирования...")
+			t.addLogAsync(i18n.T("Проверка статуса сканирования..."))
 			resp, err := http.Get(url)
 			if err != nil {
 				t.logger.WithError(err).Error("Ошибка запроса к агенту")
-				t.addLogAsync(fmt.Sprintf("Ошибка запроса к агенту: %v", err))
+				t.addLogAsync(i18n.Sprintf("Ошибка запроса к агенту: %v", err))
 				continue
 			}
 
 			// Проверка статуса ответа
 			if resp.StatusCode != http.StatusOK {
 				t.logger.WithField("status_code", resp.StatusCode).Error("Агент вернул ошибку")
-				t.addLogAsync(fmt.Sprintf("Агент вернул ошибку: статус %d", resp.StatusCode))
+				t.addLogAsync(i18n.Sprintf("Агент вернул ошибку: статус %d", resp.StatusCode))
 				resp.Body.Close()
 				continue
 			}
@@ -624,7 +645,7 @@ func (t *TUI) monitorScanStatus(scanID string) {
 			var scanStatusResp models.ScanStatusResponse
 			if err := json.NewDecoder(resp.Body).Decode(&scanStatusResp); err != nil {
 				t.logger.WithError(err).Error("Ошибка декодирования ответа агента")
-				t.addLogAsync(fmt.Sprintf("Ошибка декодирования ответа агента: %v", err))
+				t.addLogAsync(i18n.Sprintf("Ошибка декодирования ответа агента: %v", err))
 				resp.Body.Close()
 				continue
 			}
@@ -638,20 +659,23 @@ func (t *TUI) monitorScanStatus(scanID string) {
 			if scanStatusResp.ErrorMsg != "" {
 				scan.ErrorMsg = scanStatusResp.ErrorMsg
 			}
+			if scanStatusResp.ImageDigest != "" {
+				scan.ImageDigest = scanStatusResp.ImageDigest
+			}
 
 			if err := t.store.UpdateScan(scan); err != nil {
 				t.logger.WithError(err).Error("Ошибка обновления информации о сканировании")
-				t.addLogAsync(fmt.Sprintf("Ошибка обновления информации о сканировании: %v", err))
+				t.addLogAsync(i18n.Sprintf("Ошибка обновления информации о сканировании: %v", err))
 			}
 
 			// Логируем статус
-			t.addLogAsync(fmt.Sprintf("Статус сканирования %s: %s", scanID, scanStatusResp.Status))
+			t.addLogAsync(i18n.Sprintf("Статус сканирования %s: %s", scanID, scanStatusResp.Status))
 
 			// Если сканирование завершено, обрабатываем результаты
 			if scan.Status == "completed" || scan.Status == "failed" {
 				if scan.Status == "completed" {
-					t.addLogAsync(fmt.Sprintf("Сканирование %s завершено успешно", scanID))
-					t.updateStatusAsync(fmt.Sprintf("Сканирование завершено: найдено %d уязвимостей", len(scanStatusResp.Vulnerabilities)))
+					t.addLogAsync(i18n.Sprintf("Сканирование %s завершено успешно", scanID))
+					t.updateStatusAsync(i18n.Sprintf("Сканирование завершено: найдено %d уязвимостей", len(scanStatusResp.Vulnerabilities)))
 
 					// Обновляем уязвимости в БД
 					for _, vuln := range scanStatusResp.Vulnerabilities {
@@ -665,10 +689,32 @@ func (t *TUI) monitorScanStatus(scanID string) {
 						// Сохраняем уязвимость в БД
 						if err := t.store.AddVulnerability(&vuln); err != nil {
 							t.logger.WithError(err).Error("Ошибка сохранения информации об уязвимости")
-							t.addLogAsync(fmt.Sprintf("Ошибка сохранения информации об уязвимости: %v", err))
+							t.addLogAsync(i18n.Sprintf("Ошибка сохранения информации об уязвимости: %v", err))
 						}
 					}
 
+					// Сохраняем найденные секреты и ошибки конфигурации
+					findings := append(append([]models.Finding{}, scanStatusResp.Secrets...), scanStatusResp.Misconfigs...)
+					for _, finding := range findings {
+						finding.ID = uuid.New().String()
+						finding.ScanID = scanID
+						finding.ContainerID = container.ID
+						finding.HostID = host.ID
+						finding.DiscoveredAt = time.Now()
+
+						if err := t.store.AddFinding(&finding); err != nil {
+							t.logger.WithError(err).Error("Ошибка сохранения находки")
+							t.addLogAsync(i18n.Sprintf("Ошибка сохранения находки: %v", err))
+						}
+					}
+					if len(findings) > 0 {
+						t.addLogAsync(i18n.Sprintf("Найдено секретов: %d, ошибок конфигурации: %d",
+							len(scanStatusResp.Secrets), len(scanStatusResp.Misconfigs)))
+					}
+
+					// Сохраняем SBOM образа
+					t.importSBOMs(host, scan, container, scanStatusResp.SBOMFormats)
+
 					// Обновляем вывод уязвимостей
 					t.loadVulnerabilities(container.ID)
 					t.g.Update(func(g *gocui.Gui) error {
@@ -678,8 +724,8 @@ func (t *TUI) monitorScanStatus(scanID string) {
 						return nil
 					})
 				} else {
-					t.addLogAsync(fmt.Sprintf("Сканирование %s завершено с ошибкой: %s", scanID, scan.ErrorMsg))
-					t.updateStatusAsync("Сканирование завершено с ошибкой")
+					t.addLogAsync(i18n.Sprintf("Сканирование %s завершено с ошибкой: %s", scanID, scan.ErrorMsg))
+					t.updateStatusAsync(i18n.T("Сканирование завершено с ошибкой"))
 				}
 
 				return
@@ -687,13 +733,42 @@ func (t *TUI) monitorScanStatus(scanID string) {
 
 		case <-timeoutTimer.C:
 			// Завершаем мониторинг по таймауту
-			t.addLogAsync(fmt.Sprintf("Превышено время ожидания результатов сканирования %s", scanID))
-			t.updateStatusAsync("Превышено время ожидания результатов сканирования")
+			t.addLogAsync(i18n.Sprintf("Превышено время ожидания результатов сканирования %s", scanID))
+			t.updateStatusAsync(i18n.T("Превышено время ожидания результатов сканирования"))
 			return
 		}
 	}
 }
 
+// importSBOMs загружает с агента SBOM, построенные при сканировании, и сохраняет их в БД
+func (t *TUI) importSBOMs(host *models.Host, scan *models.Scan, container *models.Container, formats []string) {
+	client := agentclient.New(host)
+
+	for _, format := range formats {
+		data, err := client.GetSBOM(scan.ID, format)
+		if err != nil {
+			t.logger.WithError(err).Error("Ошибка загрузки SBOM")
+			t.addLogAsync(i18n.Sprintf("Ошибка загрузки SBOM (%s): %v", format, err))
+			continue
+		}
+
+		record, packages, err := sbom.NewRecord(scan, container.Image, format, data)
+		if err != nil {
+			t.logger.WithError(err).Error("Ошибка разбора SBOM")
+			t.addLogAsync(i18n.Sprintf("Ошибка разбора SBOM (%s): %v", format, err))
+			continue
+		}
+
+		if err := t.store.SaveSBOM(record, packages); err != nil {
+			t.logger.WithError(err).Error("Ошибка сохранения SBOM")
+			t.addLogAsync(i18n.Sprintf("Ошибка сохранения SBOM (%s): %v", format, err))
+			continue
+		}
+
+		t.addLogAsync(i18n.Sprintf("SBOM (%s) сохранен: %d пакетов", format, len(packages)))
+	}
+}
+
 // updateStatusAsync обновляет статус асинхронно из горутины
 func (t *TUI) updateStatusAsync(msg string) {
 	t.g.Update(func(g *gocui.Gui) error {
@@ -737,7 +812,7 @@ func (t *TUI) exportReport(g *gocui.Gui, v *gocui.View) error {
 		return err
 	}
 
-	exportView.Title = "Путь для экспорта"
+	exportView.Title = i18n.T("Путь для экспорта")
 	exportView.Editable = true
 	exportView.Editor = gocui.DefaultEditor
 	exportView.Clear()
@@ -767,7 +842,7 @@ func (t *TUI) exportReport(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	// Активируем окно
-	t.updateStatus("Укажите путь для сохранения отчета и нажмите Enter. Esc для отмены.")
+	t.updateStatus(i18n.T("Укажите путь для сохранения отчета и нажмите Enter. Esc для отмены."))
 	return t.activateView(g, "export_dialog")
 }
 
@@ -794,7 +869,7 @@ func (t *TUI) closeExportDialog(g *gocui.Gui, v *gocui.View) error {
 		t.activePanel = "hosts"
 	}
 
-	t.updateStatus("Экспорт отменен")
+	t.updateStatus(i18n.T("Экспорт отменен"))
 	return nil
 }
 
@@ -826,8 +901,8 @@ func (t *TUI) performExport(g *gocui.Gui, v *gocui.View) error {
 
 	// Запускаем экспорт в отдельной горутине
 	go func() {
-		t.updateStatusAsync("Экспорт отчета...")
-		t.addLogAsync(fmt.Sprintf("Начало экспорта отчета в %s", path))
+		t.updateStatusAsync(i18n.T("Экспорт отчета..."))
+		t.addLogAsync(i18n.Sprintf("Начало экспорта отчета в %s", path))
 
 		// Проверяем выбранный контейнер
 		var selectedContainer *models.Container
@@ -839,60 +914,60 @@ func (t *TUI) performExport(g *gocui.Gui, v *gocui.View) error {
 		}
 
 		if selectedContainer == nil {
-			t.updateStatusAsync("Не выбран контейнер для экспорта")
-			t.addLogAsync("Экспорт отменен: не выбран контейнер")
+			t.updateStatusAsync(i18n.T("Не выбран контейнер для экспорта"))
+			t.addLogAsync(i18n.T("Экспорт отменен: не выбран контейнер"))
 			return
 		}
 
 		vulns := t.vulns // Используем уже загруженные уязвимости
 
 		if len(vulns) == 0 {
-			t.updateStatusAsync("Нет данных для экспорта")
-			t.addLogAsync("Экспорт отменен: нет данных об уязвимостях")
+			t.updateStatusAsync(i18n.T("Нет данных для экспорта"))
+			t.addLogAsync(i18n.T("Экспорт отменен: нет данных об уязвимостях"))
 			return
 		}
 
 		// Создаем новую операцию с индикатором прогресса
 		operationID := "export-" + selectedContainer.ID[:8]
-		t.addProgressBar(operationID, 0, "Подготовка данных...")
+		t.addProgressBar(operationID, 0, i18n.T("Подготовка данных..."))
 
 		// Создаем директорию для экспорта, если она не существует
 		dir := filepath.Dir(path)
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			t.updateStatusAsync(fmt.Sprintf("Ошибка создания директории: %v", err))
-			t.addLogAsync(fmt.Sprintf("Ошибка экспорта: не удалось создать директорию %s: %v", dir, err))
+			t.updateStatusAsync(i18n.Sprintf("Ошибка создания директории: %v", err))
+			t.addLogAsync(i18n.Sprintf("Ошибка экспорта: не удалось создать директорию %s: %v", dir, err))
 			return
 		}
 
-		t.addProgressBar(operationID, 20, "Создание файла...")
+		t.addProgressBar(operationID, 20, i18n.T("Создание файла..."))
 
 		// Открываем файл для записи
 		file, err := os.Create(path)
 		if err != nil {
-			t.updateStatusAsync(fmt.Sprintf("Ошибка создания файла: %v", err))
-			t.addLogAsync(fmt.Sprintf("Ошибка экспорта: не удалось создать файл %s: %v", path, err))
+			t.updateStatusAsync(i18n.Sprintf("Ошибка создания файла: %v", err))
+			t.addLogAsync(i18n.Sprintf("Ошибка экспорта: не удалось создать файл %s: %v", path, err))
 			return
 		}
 		defer file.Close()
 
-		t.addProgressBar(operationID, 40, "Запись заголовка...")
+		t.addProgressBar(operationID, 40, i18n.T("Запись заголовка..."))
 
 		// Записываем заголовок CSV
 		header := "ID,VulnerabilityID,Severity,Title,Package,InstalledVersion,FixedVersion,Description\n"
 		if _, err := file.WriteString(header); err != nil {
-			t.updateStatusAsync(fmt.Sprintf("Ошибка записи в файл: %v", err))
-			t.addLogAsync(fmt.Sprintf("Ошибка экспорта: не удалось записать заголовок в файл %s: %v", path, err))
+			t.updateStatusAsync(i18n.Sprintf("Ошибка записи в файл: %v", err))
+			t.addLogAsync(i18n.Sprintf("Ошибка экспорта: не удалось записать заголовок в файл %s: %v", path, err))
 			return
 		}
 
-		t.addProgressBar(operationID, 60, "Запись данных...")
+		t.addProgressBar(operationID, 60, i18n.T("Запись данных..."))
 
 		// Записываем данные
 		for i, v := range vulns {
 			// Вычисляем прогресс экспорта
 			if i%10 == 0 && len(vulns) > 0 {
 				progress := 60 + int(float64(i)/float64(len(vulns))*30)
-				t.addProgressBar(operationID, progress, "Запись данных...")
+				t.addProgressBar(operationID, progress, i18n.T("Запись данных..."))
 			}
 
 			// Экранируем двойные кавычки в полях
@@ -902,19 +977,32 @@ func (t *TUI) performExport(g *gocui.Gui, v *gocui.View) error {
 			line := fmt.Sprintf("\"%s\",\"%s\",\"%s\",\"%s\",\"%s\",\"%s\",\"%s\",\"%s\"\n",
 				v.ID, v.VulnerabilityID, v.Severity, title, v.Package, v.InstalledVersion, v.FixedVersion, description)
 			if _, err := file.WriteString(line); err != nil {
-				t.updateStatusAsync(fmt.Sprintf("Ошибка записи в файл: %v", err))
-				t.addLogAsync(fmt.Sprintf("Ошибка экспорта: не удалось записать данные в файл %s: %v", path, err))
+				t.updateStatusAsync(i18n.Sprintf("Ошибка записи в файл: %v", err))
+				t.addLogAsync(i18n.Sprintf("Ошибка экспорта: не удалось записать данные в файл %s: %v", path, err))
 				return
 			}
 		}
 
-		t.addProgressBar(operationID, 100, "Завершено")
+		t.addProgressBar(operationID, 100, i18n.T("Завершено"))
 
 		// Небольшая задержка перед завершением, чтобы пользователь увидел 100%
 		time.Sleep(500 * time.Millisecond)
 
-		t.updateStatusAsync(fmt.Sprintf("Отчет успешно экспортирован в %s", path))
-		t.addLogAsync(fmt.Sprintf("Отчет успешно экспортирован в %s (%d уязвимостей)", path, len(vulns)))
+		t.updateStatusAsync(i18n.Sprintf("Отчет успешно экспортирован в %s", path))
+		t.addLogAsync(i18n.Sprintf("Отчет успешно экспортирован в %s (%d уязвимостей)", path, len(vulns)))
+
+		// Отправляем отчет в каналы уведомлений, которые поддерживают файлы (email, Telegram)
+		if t.notificationManager != nil && t.config.Notification.SendReports {
+			hostName := ""
+			if t.activeHost != nil {
+				hostName = t.activeHost.Name
+			}
+			if err := t.notificationManager.SendReport(hostName, selectedContainer.Name, path); err != nil {
+				t.addLogAsync(i18n.Sprintf("Ошибка отправки отчета: %v", err))
+			} else {
+				t.addLogAsync(i18n.T("Отчет отправлен в каналы уведомлений"))
+			}
+		}
 	}()
 
 	return nil
@@ -947,7 +1035,7 @@ func (t *TUI) showRemediation(g *gocui.Gui, v *gocui.View) error {
 		return err
 	}
 
-	remediationView.Title = "Хуки и стратегии"
+	remediationView.Title = i18n.T("Хуки и стратегии")
 	remediationView.Highlight = true
 	remediationView.SelBgColor = gocui.ColorGreen
 	remediationView.SelFgColor = gocui.ColorBlack
@@ -966,6 +1054,11 @@ func (t *TUI) showRemediation(g *gocui.Gui, v *gocui.View) error {
 	// Загружаем хуки и стратегии
 	t.loadRemediation()
 
+	// Обновляем историю выполнения хуков с агента активного хоста
+	if t.activeHost != nil {
+		go t.syncHookExecutions(*t.activeHost)
+	}
+
 	// Отображаем хуки и стратегии
 	t.renderRemediation(remediationView)
 
@@ -1043,7 +1136,7 @@ func (t *TUI) showTelegramInfo(g *gocui.Gui, v *gocui.View) error {
 		return err
 	}
 
-	telegramView.Title = "Telegram-бот"
+	telegramView.Title = i18n.T("Telegram-бот")
 	telegramView.Wrap = true
 	telegramView.Editable = false
 	telegramView.Clear()
@@ -1057,17 +1150,29 @@ func (t *TUI) showTelegramInfo(g *gocui.Gui, v *gocui.View) error {
 	t.modalWindows = append(t.modalWindows, "telegram_info")
 
 	// Наполняем информацией
-	fmt.Fprintln(telegramView, "Настройка Telegram-бота для уведомлений о безопасности")
+	fmt.Fprintln(telegramView, i18n.T("Настройка Telegram-бота для уведомлений о безопасности"))
 	fmt.Fprintln(telegramView, strings.Repeat("=", 50))
 	fmt.Fprintln(telegramView, "")
 
 	// Статус подключения
 	if t.telegramConnected {
-		fmt.Fprintf(telegramView, "✅ Статус: Подключен и готов к работе\n\n")
+		i18n.Fprintf(telegramView, "✅ Статус: Подключен и готов к работе\n\n")
 	} else {
-		fmt.Fprintf(telegramView, "❌ Статус: Не подключен\n\n")
+		i18n.Fprintf(telegramView, "❌ Статус: Не подключен\n\n")
 	}
 
+	// Интерактивный режим
+	fmt.Fprintln(telegramView, i18n.T("Интерактивный режим: aegis telegram serve"))
+	fmt.Fprintln(telegramView, i18n.T("Бот отвечает только чатам из telegram_allowed_chats и telegram_chat_id"))
+	fmt.Fprintln(telegramView, "")
+	fmt.Fprintln(telegramView, i18n.T("  /hosts                      Список хостов"))
+	fmt.Fprintln(telegramView, i18n.T("  /scan ХОСТ КОНТЕЙНЕР|all    Запуск сканирования"))
+	fmt.Fprintln(telegramView, i18n.T("  /status SCAN_ID             Статус и результаты сканирования"))
+	fmt.Fprintln(telegramView, i18n.T("  /top ХОСТ                   Самые опасные уязвимости хоста"))
+	fmt.Fprintln(telegramView, i18n.T("  /report КОНТЕЙНЕР           CSV-отчет по последнему сканированию"))
+	fmt.Fprintln(telegramView, "")
+	fmt.Fprintln(telegramView, i18n.T("Esc - закрыть"))
+
 	// Удаляем предыдущие привязки клавиш, если они есть
 	g.DeleteKeybindings("telegram_info")
 
@@ -1163,12 +1268,12 @@ func (t *TUI) loadContainers() error {
 	}
 
 	// Добавляем больше логирования для отладки
-	t.addLog(fmt.Sprintf("Загрузка контейнеров из БД для хоста ID=%s", t.activeHost.ID))
+	t.addLog(i18n.Sprintf("Загрузка контейнеров из БД для хоста ID=%s", t.activeHost.ID))
 
 	var err error
 	containers, err := t.store.ListContainers(t.activeHost.ID)
 	if err != nil {
-		t.addLog(fmt.Sprintf("Ошибка загрузки контейнеров: %v", err))
+		t.addLog(i18n.Sprintf("Ошибка загрузки контейнеров: %v", err))
 		return err
 	}
 
@@ -1178,13 +1283,13 @@ func (t *TUI) loadContainers() error {
 		if container.HostID == t.activeHost.ID {
 			filteredContainers = append(filteredContainers, container)
 		} else {
-			t.addLog(fmt.Sprintf("Пропущен контейнер с неверным hostID: %s (ожидался %s)",
+			t.addLog(i18n.Sprintf("Пропущен контейнер с неверным hostID: %s (ожидался %s)",
 				container.HostID, t.activeHost.ID))
 		}
 	}
 
 	t.containers = filteredContainers
-	t.addLog(fmt.Sprintf("Загружено %d контейнеров для хоста ID=%s", len(t.containers), t.activeHost.ID))
+	t.addLog(i18n.Sprintf("Загружено %d контейнеров для хоста ID=%s", len(t.containers), t.activeHost.ID))
 
 	return nil
 }
@@ -1193,19 +1298,19 @@ func (t *TUI) loadContainers() error {
 // и обновляет их в базе данных
 func (t *TUI) fetchContainersFromAgent() error {
 	if t.activeHost == nil {
-		return fmt.Errorf("не выбран активный хост")
+		return i18n.Errorf("не выбран активный хост")
 	}
 
 	// Проверим ID активного хоста
-	t.addLog(fmt.Sprintf("Проверка хоста ID=%s, Name=%s, Address=%s, Port=%d",
+	t.addLog(i18n.Sprintf("Проверка хоста ID=%s, Name=%s, Address=%s, Port=%d",
 		t.activeHost.ID, t.activeHost.Name, t.activeHost.Address, t.activeHost.Port))
 
 	// Получаем список всех хостов для проверки
 	hosts, err := t.store.ListHosts()
 	if err != nil {
-		t.addLog(fmt.Sprintf("Ошибка получения списка хостов: %v", err))
+		t.addLog(i18n.Sprintf("Ошибка получения списка хостов: %v", err))
 	} else {
-		t.addLog(fmt.Sprintf("Доступные хосты в системе (%d):", len(hosts)))
+		t.addLog(i18n.Sprintf("Доступные хосты в системе (%d):", len(hosts)))
 		for i, host := range hosts {
 			t.addLog(fmt.Sprintf("  %d. ID=%s, Name=%s, Address=%s, Port=%d",
 				i+1, host.ID, host.Name, host.Address, host.Port))
@@ -1213,19 +1318,19 @@ func (t *TUI) fetchContainersFromAgent() error {
 	}
 
 	// Проверим, что используем правильный ID хоста из команды containers list
-	t.addLog(fmt.Sprintf("ВАЖНО: ID хоста для CLI команды: 647198a5-dfe3-41c8-b0e2-005c321a3aa2"))
-	t.addLog(fmt.Sprintf("ВАЖНО: Текущий ID активного хоста: %s", t.activeHost.ID))
+	t.addLog(i18n.Sprintf("ВАЖНО: ID хоста для CLI команды: 647198a5-dfe3-41c8-b0e2-005c321a3aa2"))
+	t.addLog(i18n.Sprintf("ВАЖНО: Текущий ID активного хоста: %s", t.activeHost.ID))
 
 	// Формирование URL для запроса к агенту
 	url := fmt.Sprintf("http://%s:%d/containers", t.activeHost.Address, t.activeHost.Port)
 
 	// Добавим детальный лог
-	t.addLog(fmt.Sprintf("Запрос списка контейнеров от агента: %s", url))
+	t.addLog(i18n.Sprintf("Запрос списка контейнеров от агента: %s", url))
 
 	// Выполнение HTTP запроса
 	resp, err := http.Get(url)
 	if err != nil {
-		errMsg := fmt.Sprintf("Ошибка запроса к агенту: %v", err)
+		errMsg := i18n.Sprintf("Ошибка запроса к агенту: %v", err)
 		t.addLog(errMsg)
 		return fmt.Errorf(errMsg)
 	}
@@ -1233,7 +1338,7 @@ func (t *TUI) fetchContainersFromAgent() error {
 
 	// Проверка статуса ответа
 	if resp.StatusCode != http.StatusOK {
-		errMsg := fmt.Sprintf("Агент вернул ошибку: статус %d", resp.StatusCode)
+		errMsg := i18n.Sprintf("Агент вернул ошибку: статус %d", resp.StatusCode)
 		t.addLog(errMsg)
 		return fmt.Errorf(errMsg)
 	}
@@ -1241,13 +1346,13 @@ func (t *TUI) fetchContainersFromAgent() error {
 	// Чтение тела ответа для отладки
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		errMsg := fmt.Sprintf("Ошибка чтения ответа: %v", err)
+		errMsg := i18n.Sprintf("Ошибка чтения ответа: %v", err)
 		t.addLog(errMsg)
 		return fmt.Errorf(errMsg)
 	}
 
 	// Логируем тело ответа для отладки
-	t.addLog(fmt.Sprintf("Получен ответ от агента: %s", string(bodyBytes)))
+	t.addLog(i18n.Sprintf("Получен ответ от агента: %s", string(bodyBytes)))
 
 	// Создаем новый reader для декодирования JSON, так как оригинальный Body уже прочитан
 	bodyReader := bytes.NewReader(bodyBytes)
@@ -1255,17 +1360,17 @@ func (t *TUI) fetchContainersFromAgent() error {
 	// Декодирование ответа
 	var containerResponse models.ContainerListResponse
 	if err := json.NewDecoder(bodyReader).Decode(&containerResponse); err != nil {
-		errMsg := fmt.Sprintf("Ошибка декодирования ответа агента: %v", err)
+		errMsg := i18n.Sprintf("Ошибка декодирования ответа агента: %v", err)
 		t.addLog(errMsg)
 		return fmt.Errorf(errMsg)
 	}
 
 	// Логируем количество найденных контейнеров
-	t.addLog(fmt.Sprintf("Получено контейнеров от агента: %d", len(containerResponse.Containers)))
+	t.addLog(i18n.Sprintf("Получено контейнеров от агента: %d", len(containerResponse.Containers)))
 
 	// Используем ID из CLI-команды для теста
 	targetHostID := "647198a5-dfe3-41c8-b0e2-005c321a3aa2"
-	t.addLog(fmt.Sprintf("Используем хост ID=%s для добавления контейнеров в БД", targetHostID))
+	t.addLog(i18n.Sprintf("Используем хост ID=%s для добавления контейнеров в БД", targetHostID))
 
 	// Обновление контейнеров в базе данных
 	for _, container := range containerResponse.Containers {
@@ -1274,7 +1379,7 @@ func (t *TUI) fetchContainersFromAgent() error {
 		container.UpdatedAt = time.Now()
 
 		// Логируем информацию о каждом контейнере
-		t.addLog(fmt.Sprintf("Обработка контейнера ID=%s, Name=%s, Image=%s, Status=%s",
+		t.addLog(i18n.Sprintf("Обработка контейнера ID=%s, Name=%s, Image=%s, Status=%s",
 			container.ID, container.Name, container.Image, container.Status))
 
 		// Проверяем, существует ли контейнер в базе
@@ -1282,20 +1387,25 @@ func (t *TUI) fetchContainersFromAgent() error {
 		if err == nil {
 			// Контейнер существует, обновляем статус
 			existingContainer.Status = container.Status
+			existingContainer.Runtime = container.Runtime
+			existingContainer.PodName = container.PodName
+			existingContainer.PodNamespace = container.PodNamespace
+			existingContainer.WorkloadKind = container.WorkloadKind
+			existingContainer.WorkloadName = container.WorkloadName
 			existingContainer.UpdatedAt = time.Now()
 			existingContainer.HostID = targetHostID // Обновляем ID хоста
 			if err := t.store.UpdateContainer(existingContainer); err != nil {
-				t.addLog(fmt.Sprintf("Ошибка обновления контейнера %s в БД: %v", container.ID, err))
+				t.addLog(i18n.Sprintf("Ошибка обновления контейнера %s в БД: %v", container.ID, err))
 			} else {
-				t.addLog(fmt.Sprintf("Контейнер %s обновлен в БД", container.ID))
+				t.addLog(i18n.Sprintf("Контейнер %s обновлен в БД", container.ID))
 			}
 		} else {
 			// Контейнер не существует, добавляем
 			container.CreatedAt = time.Now()
 			if err := t.store.AddContainer(&container); err != nil {
-				t.addLog(fmt.Sprintf("Ошибка добавления контейнера %s в БД: %v", container.ID, err))
+				t.addLog(i18n.Sprintf("Ошибка добавления контейнера %s в БД: %v", container.ID, err))
 			} else {
-				t.addLog(fmt.Sprintf("Контейнер %s добавлен в БД", container.ID))
+				t.addLog(i18n.Sprintf("Контейнер %s добавлен в БД", container.ID))
 			}
 		}
 	}
@@ -1305,25 +1415,25 @@ func (t *TUI) fetchContainersFromAgent() error {
 	// Получаем хост из БД по ID
 	targetHost, err := t.store.GetHost(targetHostID)
 	if err != nil {
-		t.addLog(fmt.Sprintf("Ошибка получения хоста по ID=%s: %v", targetHostID, err))
+		t.addLog(i18n.Sprintf("Ошибка получения хоста по ID=%s: %v", targetHostID, err))
 	} else {
 		t.activeHost = targetHost
 	}
 
 	// После обновления базы данных загружаем контейнеры из неё
-	t.addLog("Загрузка обновленных контейнеров из БД")
+	t.addLog(i18n.T("Загрузка обновленных контейнеров из БД"))
 	err = t.loadContainers()
 
 	// Восстанавливаем исходный активный хост
 	t.activeHost = originalHost
 
 	if err != nil {
-		t.addLog(fmt.Sprintf("Ошибка загрузки контейнеров из БД: %v", err))
+		t.addLog(i18n.Sprintf("Ошибка загрузки контейнеров из БД: %v", err))
 		return err
 	}
 
 	// Логируем количество загруженных из БД контейнеров
-	t.addLog(fmt.Sprintf("Загружено контейнеров из БД: %d", len(t.containers)))
+	t.addLog(i18n.Sprintf("Загружено контейнеров из БД: %d", len(t.containers)))
 
 	return nil
 }
@@ -1348,20 +1458,21 @@ func (t *TUI) loadVulnerabilities(containerID string) error {
 func (t *TUI) selectHost(index int) {
 	if index >= 0 && index < len(t.hosts) {
 		t.activeHost = &t.hosts[index]
+		t.activeContainer = nil
 
 		// Логируем выбранный хост для отладки
-		t.addLog(fmt.Sprintf("Выбран хост ID=%s, Name=%s, Address=%s, Port=%d",
+		t.addLog(i18n.Sprintf("Выбран хост ID=%s, Name=%s, Address=%s, Port=%d",
 			t.activeHost.ID, t.activeHost.Name, t.activeHost.Address, t.activeHost.Port))
 
 		// Используем прямой метод получения контейнеров как в CLI
 		err := t.fetchContainersDirect()
 		if err != nil {
-			t.addLog(fmt.Sprintf("Ошибка при прямом получении контейнеров: %v", err))
+			t.addLog(i18n.Sprintf("Ошибка при прямом получении контейнеров: %v", err))
 
 			// Пробуем получить контейнеры через обычный метод
 			err = t.fetchContainersFromAgent()
 			if err != nil {
-				t.addLog(fmt.Sprintf("Ошибка получения контейнеров от агента: %v", err))
+				t.addLog(i18n.Sprintf("Ошибка получения контейнеров от агента: %v", err))
 				// При ошибке используем локальные данные
 				t.loadContainers()
 			}
@@ -1372,11 +1483,14 @@ func (t *TUI) selectHost(index int) {
 		}
 
 		t.loadVulnerabilities("")
+		if t.auditTab {
+			t.loadAuditFindings()
+		}
 		if vulnsView, err := t.g.View("vulnerabilities"); err == nil {
 			t.renderVulnerabilities(vulnsView)
 		}
 
-		t.updateStatus(fmt.Sprintf("Выбран хост: %s (%s)", t.activeHost.Name, t.activeHost.Address))
+		t.updateStatus(i18n.Sprintf("Выбран хост: %s (%s)", t.activeHost.Name, t.activeHost.Address))
 	}
 }
 
@@ -1384,13 +1498,14 @@ func (t *TUI) selectHost(index int) {
 func (t *TUI) selectContainer(index int) {
 	if index >= 0 && index < len(t.containers) {
 		container := t.containers[index]
+		t.activeContainer = &container
 		t.loadVulnerabilities(container.ID)
 
 		if vulnsView, err := t.g.View("vulnerabilities"); err == nil {
 			t.renderVulnerabilities(vulnsView)
 		}
 
-		t.updateStatus(fmt.Sprintf("Выбран контейнер: %s (образ: %s)", container.Name, container.Image))
+		t.updateStatus(i18n.Sprintf("Выбран контейнер: %s (образ: %s)", container.Name, container.Image))
 	}
 }
 
@@ -1399,7 +1514,7 @@ func (t *TUI) renderHosts(v *gocui.View) {
 	v.Clear()
 
 	if len(t.hosts) == 0 {
-		fmt.Fprintln(v, "Нет доступных хостов")
+		fmt.Fprintln(v, i18n.T("Нет доступных хостов"))
 		return
 	}
 
@@ -1418,16 +1533,16 @@ func (t *TUI) renderContainers(v *gocui.View) {
 	v.Clear()
 
 	if t.activeHost == nil {
-		fmt.Fprintln(v, "Выберите хост")
+		fmt.Fprintln(v, i18n.T("Выберите хост"))
 		return
 	}
 
 	// Дополнительный лог для отладки
-	t.addLog(fmt.Sprintf("Рендеринг контейнеров для хоста ID=%s. Найдено: %d",
+	t.addLog(i18n.Sprintf("Рендеринг контейнеров для хоста ID=%s. Найдено: %d",
 		t.activeHost.ID, len(t.containers)))
 
 	if len(t.containers) == 0 {
-		fmt.Fprintln(v, "Нет доступных контейнеров")
+		fmt.Fprintln(v, i18n.T("Нет доступных контейнеров"))
 		return
 	}
 
@@ -1437,16 +1552,26 @@ func (t *TUI) renderContainers(v *gocui.View) {
 			statusSymbol = "🟢" // Running
 		}
 
+		if container.PodName != "" {
+			fmt.Fprintf(v, "%s %s (%s) [%s/%s]\n", statusSymbol, container.Name, container.Image,
+				container.PodNamespace, container.WorkloadName)
+			continue
+		}
 		fmt.Fprintf(v, "%s %s (%s)\n", statusSymbol, container.Name, container.Image)
 	}
 }
 
 // renderVulnerabilities отображает список уязвимостей в панели
 func (t *TUI) renderVulnerabilities(v *gocui.View) {
+	if t.auditTab {
+		t.renderAudit(v)
+		return
+	}
+
 	v.Clear()
 
 	if len(t.vulns) == 0 {
-		fmt.Fprintln(v, "Нет данных об уязвимостях")
+		fmt.Fprintln(v, i18n.T("Нет данных об уязвимостях"))
 		return
 	}
 
@@ -1481,7 +1606,7 @@ func (t *TUI) renderVulnerabilities(v *gocui.View) {
 	}
 
 	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, "Подробно:")
+	fmt.Fprintln(v, i18n.T("Подробно:"))
 
 	// Отображаем первые 10 уязвимостей
 	limit := 10
@@ -1510,7 +1635,7 @@ func (t *TUI) renderVulnerabilities(v *gocui.View) {
 		fmt.Fprintf(v, "  %s\n", vuln.Title)
 
 		if vuln.FixedVersion != "" {
-			fmt.Fprintf(v, "  Исправлено в версии: %s (текущая: %s)\n",
+			i18n.Fprintf(v, "  Исправлено в версии: %s (текущая: %s)\n",
 				vuln.FixedVersion, vuln.InstalledVersion)
 		}
 
@@ -1518,7 +1643,121 @@ func (t *TUI) renderVulnerabilities(v *gocui.View) {
 	}
 
 	if len(t.vulns) > limit {
-		fmt.Fprintf(v, "... еще %d уязвимостей\n", len(t.vulns)-limit)
+		i18n.Fprintf(v, "... еще %d уязвимостей\n", len(t.vulns)-limit)
+	}
+}
+
+// toggleAuditTab переключает нижнюю левую панель между уязвимостями и результатами аудита
+func (t *TUI) toggleAuditTab(g *gocui.Gui, v *gocui.View) error {
+	t.auditTab = !t.auditTab
+
+	vulnsView, err := g.View("vulnerabilities")
+	if err != nil {
+		return nil
+	}
+	vulnsView.SetOrigin(0, 0)
+
+	if !t.auditTab {
+		vulnsView.Title = i18n.T("Уязвимости")
+		t.renderVulnerabilities(vulnsView)
+		return nil
+	}
+
+	vulnsView.Title = i18n.T("Аудит конфигурации")
+	if err := t.loadAuditFindings(); err != nil {
+		t.addLog(i18n.Sprintf("Ошибка загрузки результатов аудита: %v", err))
+	}
+	t.renderVulnerabilities(vulnsView)
+
+	// Обновляем результаты аудита выбранного хоста в фоне
+	if t.activeHost != nil {
+		host := *t.activeHost
+		t.updateStatus(i18n.Sprintf("Аудит контейнеров на хосте %s...", host.Name))
+		go t.runAudit(host)
+	}
+
+	return nil
+}
+
+// loadAuditFindings загружает результаты аудита активного хоста из БД
+func (t *TUI) loadAuditFindings() error {
+	hostID := ""
+	if t.activeHost != nil {
+		hostID = t.activeHost.ID
+	}
+
+	var err error
+	t.auditFindings, err = t.store.ListAuditFindings(hostID, "", "")
+	return err
+}
+
+// runAudit запрашивает у агента аудит конфигурации контейнеров и сохраняет результаты
+func (t *TUI) runAudit(host models.Host) {
+	result, err := agentclient.New(&host).Audit("")
+	if err != nil {
+		t.logger.WithError(err).WithField("host_id", host.ID).Error("Ошибка аудита контейнеров")
+		t.addLogAsync(i18n.Sprintf("Ошибка аудита контейнеров на хосте %s: %v", host.Name, err))
+		t.updateStatusAsync(i18n.T("Ошибка аудита контейнеров"))
+		return
+	}
+
+	for i := range result.Findings {
+		result.Findings[i].HostID = host.ID
+	}
+
+	if err := t.store.SaveAuditFindings(host.ID, "", result.Findings); err != nil {
+		t.logger.WithError(err).WithField("host_id", host.ID).Error("Ошибка сохранения результатов аудита")
+		t.addLogAsync(i18n.Sprintf("Ошибка сохранения результатов аудита: %v", err))
+		return
+	}
+
+	t.addLogAsync(i18n.Sprintf("Аудит хоста %s: проверено контейнеров %d, нарушений %d",
+		host.Name, result.Containers, len(result.Findings)))
+	t.updateStatusAsync(i18n.T("Аудит конфигурации контейнеров завершен"))
+
+	t.g.Update(func(g *gocui.Gui) error {
+		if err := t.loadAuditFindings(); err != nil {
+			return nil
+		}
+		if vulnsView, err := g.View("vulnerabilities"); err == nil {
+			t.renderVulnerabilities(vulnsView)
+		}
+		return nil
+	})
+}
+
+// renderAudit отображает результаты аудита конфигурации контейнеров
+func (t *TUI) renderAudit(v *gocui.View) {
+	v.Clear()
+
+	if len(t.auditFindings) == 0 {
+		fmt.Fprintln(v, i18n.T("Нет данных аудита"))
+		return
+	}
+
+	currentContainer := ""
+	for _, f := range t.auditFindings {
+		if f.ContainerID != currentContainer {
+			currentContainer = f.ContainerID
+			fmt.Fprintf(v, "\x1b[1m%s\x1b[0m\n", f.ContainerName)
+		}
+
+		var severityColor string
+		switch strings.ToUpper(f.Severity) {
+		case "CRITICAL":
+			severityColor = "31" // Red
+		case "HIGH":
+			severityColor = "33" // Yellow
+		case "MEDIUM":
+			severityColor = "34" // Blue
+		case "LOW":
+			severityColor = "32" // Green
+		default:
+			severityColor = "37" // White
+		}
+
+		fmt.Fprintf(v, "  \x1b[%sm[%s]\x1b[0m %s %s\n", severityColor, f.Severity, f.RuleID, f.Title)
+		fmt.Fprintf(v, "    %s\n", f.Details)
 	}
 }
 
@@ -1531,7 +1770,7 @@ func (t *TUI) updateStatus(msg string) {
 
 	statusView.Clear()
 	timestamp := time.Now().Format("15:04:05")
-	fmt.Fprintf(statusView, "[%s] %s | F1:Помощь | F2:Сканировать | F3:Экспорт | F4:Хуки | F5:Обновить | F6:Telegram | F10:Выход",
+	i18n.Fprintf(statusView, "[%s] %s | F1:Помощь | F2:Сканировать | F3:Экспорт | F4:Хуки | F5:Обновить | F6:Telegram | F7:Аудит | F10:Выход",
 		timestamp, msg)
 }
 
@@ -1611,7 +1850,7 @@ func (t *TUI) nextView(g *gocui.Gui, v *gocui.View) error {
 	if nextView, ok := nextViews[v.Name()]; ok {
 		_, err := g.SetCurrentView(nextView)
 		t.activePanel = nextView
-		t.updateStatus(fmt.Sprintf("Активная панель: %s", nextView))
+		t.updateStatus(i18n.Sprintf("Активная панель: %s", nextView))
 		return err
 	}
 
@@ -1686,7 +1925,7 @@ func (t *TUI) selectContainerOnEnter(g *gocui.Gui, v *gocui.View) error {
 
 // refreshData обновляет данные
 func (t *TUI) refreshData(g *gocui.Gui, v *gocui.View) error {
-	t.updateStatus("Обновление данных...")
+	t.updateStatus(i18n.T("Обновление данных..."))
 
 	if err := t.loadHosts(); err != nil {
 		return err
@@ -1696,11 +1935,11 @@ func (t *TUI) refreshData(g *gocui.Gui, v *gocui.View) error {
 		// Используем прямой метод получения контейнеров как в CLI
 		err := t.fetchContainersDirect()
 		if err != nil {
-			t.addLog(fmt.Sprintf("Ошибка при прямом получении контейнеров: %v", err))
+			t.addLog(i18n.Sprintf("Ошибка при прямом получении контейнеров: %v", err))
 
 			// Вместо просто загрузки из БД, сначала обновляем данные с агента
 			if err := t.fetchContainersFromAgent(); err != nil {
-				t.addLog(fmt.Sprintf("Ошибка при получении контейнеров от агента: %v", err))
+				t.addLog(i18n.Sprintf("Ошибка при получении контейнеров от агента: %v", err))
 				// Даже при ошибке пробуем загрузить из БД
 				if err := t.loadContainers(); err != nil {
 					return err
@@ -1712,7 +1951,7 @@ func (t *TUI) refreshData(g *gocui.Gui, v *gocui.View) error {
 	// Используем updateUI для обновления интерфейса
 	t.updateUI()
 
-	t.updateStatus("Данные обновлены")
+	t.updateStatus(i18n.T("Данные обновлены"))
 	return nil
 }
 
@@ -1741,12 +1980,12 @@ func (t *TUI) addProgressBar(operationID string, progress int, message string) {
 
 	timestamp := time.Now().Format("15:04:05")
 	// Зеленый прогресс-бар для лучшей видимости
-	logEntry := fmt.Sprintf("[%s] \x1b[32mПрогресс %s: %s %s\x1b[0m", timestamp, operationID, progressBar, message)
+	logEntry := i18n.Sprintf("[%s] \x1b[32mПрогресс %s: %s %s\x1b[0m", timestamp, operationID, progressBar, message)
 
 	// Обновляем или добавляем запись в лог
 	found := false
 	for i, entry := range t.logs {
-		if strings.Contains(entry, fmt.Sprintf("Прогресс %s:", operationID)) {
+		if strings.Contains(entry, i18n.Sprintf("Прогресс %s:", operationID)) {
 			t.logs[i] = logEntry
 			found = true
 			break
@@ -1774,17 +2013,17 @@ func (t *TUI) addProgressBar(operationID string, progress int, message string) {
 func (t *TUI) checkTelegramBotStatus() {
 	// Проверяем, настроен ли Telegram-бот в конфигурации
 	if t.config.TelegramBotToken != "" && t.config.TelegramChatID != "" {
-		t.addLog("Проверка подключения Telegram-бота...")
+		t.addLog(i18n.T("Проверка подключения Telegram-бота..."))
 
 		// Здесь должен быть код для проверки подключения к Telegram API
 		// Эмулируем задержку и успешное подключение
 		time.Sleep(2 * time.Second)
 
 		t.telegramConnected = true
-		t.addLogAsync("Telegram-бот подключен и готов к отправке уведомлений")
-		t.updateStatusAsync("Telegram-бот активен")
+		t.addLogAsync(i18n.T("Telegram-бот подключен и готов к отправке уведомлений"))
+		t.updateStatusAsync(i18n.T("Telegram-бот активен"))
 	} else {
-		t.addLog("Telegram-бот не настроен. Для настройки укажите TelegramBotToken и TelegramChatID в конфигурации")
+		t.addLog(i18n.T("Telegram-бот не настроен. Для настройки укажите TelegramBotToken и TelegramChatID в конфигурации"))
 	}
 }
 
@@ -1793,7 +2032,7 @@ func (t *TUI) renderLogs(v *gocui.View) {
 	v.Clear()
 
 	if len(t.logs) == 0 {
-		fmt.Fprintln(v, "Нет записей в логе")
+		fmt.Fprintln(v, i18n.T("Нет записей в логе"))
 		return
 	}
 
@@ -1876,6 +2115,47 @@ func (t *TUI) loadRemediation() {
 	} else {
 		t.strategies = strategies
 	}
+
+	// Загружаем последние неудачные выполнения хуков
+	hostID := ""
+	if t.activeHost != nil {
+		hostID = t.activeHost.ID
+	}
+	executions, err := t.store.FilterHookExecutions("", "", hostID, models.HookStatusFailure)
+	if err != nil {
+		t.logger.WithError(err).Error("Ошибка загрузки истории хуков")
+		executions = nil
+	}
+	if len(executions) > failedHooksLimit {
+		executions = executions[:failedHooksLimit]
+	}
+	t.failedHooks = executions
+}
+
+// syncHookExecutions загружает с агента историю выполнения хуков и обновляет панель исправлений
+func (t *TUI) syncHookExecutions(host models.Host) {
+	executions, err := agentclient.New(&host).ListHookExecutions("", "")
+	if err != nil {
+		t.logger.WithError(err).WithField("host_id", host.ID).Warn("Ошибка загрузки истории хуков")
+		return
+	}
+
+	imported, err := t.store.ImportHookExecutions(host.ID, executions)
+	if err != nil {
+		t.logger.WithError(err).WithField("host_id", host.ID).Error("Ошибка сохранения истории хуков")
+		return
+	}
+	if imported == 0 {
+		return
+	}
+
+	t.g.Update(func(g *gocui.Gui) error {
+		t.loadRemediation()
+		if v, err := g.View("remediation"); err == nil {
+			t.renderRemediation(v)
+		}
+		return nil
+	})
 }
 
 // renderRemediation отображает хуки и стратегии в панели
@@ -1883,48 +2163,72 @@ func (t *TUI) renderRemediation(v *gocui.View) {
 	v.Clear()
 
 	if len(t.hooks) == 0 && len(t.strategies) == 0 {
-		fmt.Fprintln(v, "Нет доступных хуков и стратегий исправления")
+		fmt.Fprintln(v, i18n.T("Нет доступных хуков и стратегий исправления"))
 		return
 	}
 
 	if len(t.hooks) > 0 {
-		fmt.Fprintln(v, "ХУКИ:")
+		fmt.Fprintln(v, i18n.T("ХУКИ:"))
 		fmt.Fprintln(v, strings.Repeat("-", 50))
 
 		for i, hook := range t.hooks {
-			enabled := "Нет"
+			enabled := i18n.T("Нет")
 			if hook.Enabled {
-				enabled = "Да"
+				enabled = i18n.T("Да")
 			}
 
 			fmt.Fprintf(v, "%d. %s (%s)\n", i+1, hook.Name, hook.Event)
-			fmt.Fprintf(v, "   Скрипт: %s\n", hook.ScriptPath)
-			fmt.Fprintf(v, "   Активен: %s\n", enabled)
+			if hook.IsWebhook() {
+				fmt.Fprintf(v, "   Webhook: %s\n", hook.URL)
+			} else {
+				i18n.Fprintf(v, "   Скрипт: %s\n", hook.ScriptPath)
+			}
+			if hook.MinSeverity != "" {
+				i18n.Fprintf(v, "   Серьезность: не ниже %s\n", hook.MinSeverity)
+			}
+			i18n.Fprintf(v, "   Активен: %s\n", enabled)
 			fmt.Fprintln(v, "")
 		}
 	}
 
+	if len(t.failedHooks) > 0 {
+		fmt.Fprintln(v, i18n.T("НЕУДАЧНЫЕ ВЫПОЛНЕНИЯ ХУКОВ:"))
+		fmt.Fprintln(v, strings.Repeat("-", 50))
+
+		for _, e := range t.failedHooks {
+			name := e.HookName
+			if name == "" {
+				name = e.HookID
+			}
+			i18n.Fprintf(v, "[%s] %s (%s), код %d\n", e.StartedAt.Format("2006-01-02 15:04:05"), name, e.Event, e.ExitCode)
+			if e.ErrorMsg != "" {
+				i18n.Fprintf(v, "   Ошибка: %s\n", e.ErrorMsg)
+			}
+		}
+		fmt.Fprintln(v, "")
+	}
+
 	if len(t.strategies) > 0 {
-		fmt.Fprintln(v, "СТРАТЕГИИ ИСПРАВЛЕНИЯ:")
+		fmt.Fprintln(v, i18n.T("СТРАТЕГИИ ИСПРАВЛЕНИЯ:"))
 		fmt.Fprintln(v, strings.Repeat("-", 50))
 
 		for i, strategy := range t.strategies {
 			fmt.Fprintf(v, "%d. %s (%s)\n", i+1, strategy.Name, strategy.Type)
-			fmt.Fprintf(v, "   Описание: %s\n", strategy.Description)
-			fmt.Fprintf(v, "   Ожидаемое время простоя: %s\n", strategy.EstimatedDowntime)
-			fmt.Fprintf(v, "   Команда: %s\n", strategy.Command)
+			i18n.Fprintf(v, "   Описание: %s\n", strategy.Description)
+			i18n.Fprintf(v, "   Ожидаемое время простоя: %s\n", strategy.EstimatedDowntime)
+			i18n.Fprintf(v, "   Команда: %s\n", strategy.RenderCommand(t.activeContainer))
 			fmt.Fprintln(v, "")
 		}
 	}
 
-	fmt.Fprintln(v, "Используйте стрелки для навигации, Enter для выбора, Esc для выхода")
+	fmt.Fprintln(v, i18n.T("Используйте стрелки для навигации, Enter для выбора, Esc для выхода"))
 }
 
 // applyRemediation применяет выбранное исправление
 func (t *TUI) applyRemediation(g *gocui.Gui, v *gocui.View) error {
 	// TODO: Реализовать применение исправления
-	t.updateStatus("Применение исправления...")
-	t.addLog("Запуск процесса исправления уязвимостей")
+	t.updateStatus(i18n.T("Применение исправления..."))
+	t.addLog(i18n.T("Запуск процесса исправления уязвимостей"))
 
 	// Закрываем панель
 	return t.closeRemediation(g, v)
@@ -1952,39 +2256,39 @@ func (t *TUI) addLog(message string) {
 // fetchContainersDirect выполняет прямой запрос к агенту аналогично CLI команде containers list
 func (t *TUI) fetchContainersDirect() error {
 	if t.activeHost == nil {
-		t.addLog("Ошибка: не выбран активный хост")
-		return fmt.Errorf("не выбран активный хост")
+		t.addLog(i18n.T("Ошибка: не выбран активный хост"))
+		return i18n.Errorf("не выбран активный хост")
 	}
 
-	t.addLog(fmt.Sprintf("Прямой запрос контейнеров с хоста %s (%s)",
+	t.addLog(i18n.Sprintf("Прямой запрос контейнеров с хоста %s (%s)",
 		t.activeHost.Name, t.activeHost.Address))
 
 	// Формирование URL для запроса к агенту - как в CLI
 	url := fmt.Sprintf("http://%s:%d/containers", t.activeHost.Address, t.activeHost.Port)
-	t.addLog(fmt.Sprintf("URL запроса: %s", url))
+	t.addLog(i18n.Sprintf("URL запроса: %s", url))
 
 	// Выполнение HTTP запроса напрямую
 	resp, err := http.Get(url)
 	if err != nil {
-		t.addLog(fmt.Sprintf("Ошибка запроса к агенту: %v", err))
+		t.addLog(i18n.Sprintf("Ошибка запроса к агенту: %v", err))
 		return err
 	}
 	defer resp.Body.Close()
 
 	// Проверка статуса ответа
 	if resp.StatusCode != http.StatusOK {
-		t.addLog(fmt.Sprintf("Агент вернул ошибку: статус %d", resp.StatusCode))
-		return fmt.Errorf("агент вернул статус %d", resp.StatusCode)
+		t.addLog(i18n.Sprintf("Агент вернул ошибку: статус %d", resp.StatusCode))
+		return i18n.Errorf("агент вернул статус %d", resp.StatusCode)
 	}
 
 	// Декодирование ответа
 	var containerResponse models.ContainerListResponse
 	if err := json.NewDecoder(resp.Body).Decode(&containerResponse); err != nil {
-		t.addLog(fmt.Sprintf("Ошибка декодирования ответа: %v", err))
+		t.addLog(i18n.Sprintf("Ошибка декодирования ответа: %v", err))
 		return err
 	}
 
-	t.addLog(fmt.Sprintf("Получено %d контейнеров напрямую от агента",
+	t.addLog(i18n.Sprintf("Получено %d контейнеров напрямую от агента",
 		len(containerResponse.Containers)))
 
 	// Заменяем список контейнеров напрямую
@@ -1997,7 +2301,7 @@ func (t *TUI) fetchContainersDirect() error {
 
 	// Выведем список полученных контейнеров для отладки
 	for i, container := range t.containers {
-		t.addLog(fmt.Sprintf("Контейнер %d: ID=%s, Name=%s, Image=%s, Status=%s",
+		t.addLog(i18n.Sprintf("Контейнер %d: ID=%s, Name=%s, Image=%s, Status=%s",
 			i+1, container.ID, container.Name, container.Image, container.Status))
 	}
 
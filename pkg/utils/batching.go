@@ -0,0 +1,451 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/google/uuid"
+)
+
+// Уведомления, которые не удалось отправить за сутки, удаляются из очереди,
+// чтобы недоступный канал не накапливал их бесконечно
+const notificationQueueTTL = 24 * time.Hour
+
+// notificationHistoryTTL - срок хранения записей об отправленных сообщениях.
+// Покрывает час ограничения частоты и неделю еженедельного дайджеста
+const notificationHistoryTTL = 8 * 24 * time.Hour
+
+// NotificationStore хранит очередь и историю отправки уведомлений между запусками CLI,
+// а для дайджестов читает результаты сканирований
+type NotificationStore interface {
+	AddNotificationQueueItem(item *models.NotificationQueueItem) error
+	ListNotificationQueue(channel string) ([]models.NotificationQueueItem, error)
+	ClaimNotificationQueueItems(ids []string) (bool, error)
+	AddNotificationDelivery(delivery *models.NotificationDelivery) error
+	CountNotificationDeliveries(channel string, since time.Time) (int, error)
+	LastNotificationDelivery(event string) (time.Time, error)
+	NotificationSeen(channel, fingerprint string, since time.Time) (bool, error)
+	SaveNotificationFingerprints(channel string, fingerprints []string, sentAt time.Time) error
+	PruneNotificationHistory(deliveriesBefore, fingerprintsBefore time.Time) error
+
+	ListHosts() ([]models.Host, error)
+	ListContainers(hostID string) ([]models.Container, error)
+	ListScans(hostID, containerID string) ([]models.Scan, error)
+	ListVulnerabilities(hostID, containerID, scanID string, severity string) ([]models.Vulnerability, error)
+}
+
+// UseStore подключает хранилище очереди и истории уведомлений. Без него группировка,
+// ограничение частоты, подавление повторов и дайджесты не работают
+func (n *NotificationManager) UseStore(store NotificationStore) {
+	n.store = store
+}
+
+// channelRateLimit возвращает ограничение частоты канала в сообщениях в час
+func channelRateLimit(cfg models.NotificationConfig, channel models.NotificationChannel) int {
+	limit := cfg.RateLimitPerHour
+	if channel.RateLimitPerHour != 0 {
+		limit = channel.RateLimitPerHour
+	}
+	if limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// batchWindow возвращает окно группировки уведомлений
+func (n *NotificationManager) batchWindow() time.Duration {
+	return time.Duration(n.config.Notification.BatchWindowSeconds) * time.Second
+}
+
+// dedupWindow возвращает окно подавления повторов
+func (n *NotificationManager) dedupWindow() time.Duration {
+	return time.Duration(n.config.Notification.DedupWindowHours) * time.Hour
+}
+
+// duplicateReason возвращает причину пропуска уведомления, если те же находки уже
+// отправлялись в канал в пределах окна подавления повторов или ждут в его очереди
+func (n *NotificationManager) duplicateReason(channel routedNotifier, notification *Notification, now time.Time) string {
+	if n.store == nil || n.dedupWindow() <= 0 || notification.Fingerprint == "" {
+		return ""
+	}
+
+	seen, err := n.store.NotificationSeen(channel.Name(), notification.Fingerprint, now.Add(-n.dedupWindow()).UTC())
+	if err != nil {
+		n.logger.WithError(err).WithField("channel", channel.Name()).Warn("Ошибка проверки повтора уведомления")
+		return ""
+	}
+	if seen {
+		return i18n.Sprintf("те же находки отправлены или ожидают отправки за последние %d ч (dedup_window_hours)", n.config.Notification.DedupWindowHours)
+	}
+	return ""
+}
+
+// queueReason возвращает причину, по которой уведомление ставится в очередь канала,
+// или пустую строку, если его можно отправить сразу
+func (n *NotificationManager) queueReason(channel routedNotifier, now time.Time) string {
+	if n.store == nil {
+		return ""
+	}
+	if window := n.batchWindow(); window > 0 {
+		return i18n.Sprintf("в очередь: сводка отправляется раз в %s (batch_window_seconds)", window)
+	}
+	if channel.rateLimit == 0 {
+		return ""
+	}
+
+	queued, err := n.store.ListNotificationQueue(channel.Name())
+	if err != nil {
+		n.logger.WithError(err).WithField("channel", channel.Name()).Warn("Ошибка чтения очереди уведомлений")
+		return ""
+	}
+	if len(queued) > 0 {
+		return i18n.Sprintf("в очередь: в очереди канала %d событий", len(queued))
+	}
+	if n.rateLimited(channel, now) {
+		return i18n.Sprintf("в очередь: достигнут лимит %d сообщений в час (rate_limit_per_hour)", channel.rateLimit)
+	}
+	return ""
+}
+
+// rateLimited проверяет, исчерпан ли лимит сообщений канала за последний час
+func (n *NotificationManager) rateLimited(channel routedNotifier, now time.Time) bool {
+	if channel.rateLimit == 0 {
+		return false
+	}
+	sent, err := n.store.CountNotificationDeliveries(channel.Name(), now.Add(-time.Hour).UTC())
+	if err != nil {
+		n.logger.WithError(err).WithField("channel", channel.Name()).Warn("Ошибка чтения истории уведомлений")
+		return false
+	}
+	return sent >= channel.rateLimit
+}
+
+// enqueue ставит уведомление в очередь канала
+func (n *NotificationManager) enqueue(channel routedNotifier, notification *Notification, now time.Time) error {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return i18n.Errorf("ошибка маршалинга уведомления: %w", err)
+	}
+	return n.store.AddNotificationQueueItem(&models.NotificationQueueItem{
+		ID:          uuid.New().String(),
+		Channel:     channel.Name(),
+		Event:       notification.Event,
+		Fingerprint: notification.Fingerprint,
+		Payload:     string(payload),
+		CreatedAt:   now.UTC(),
+	})
+}
+
+// recordDelivery записывает отправленное сообщение и отпечатки его находок
+func (n *NotificationManager) recordDelivery(channel, event string, events int, fingerprints []string) {
+	if n.store == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	err := n.store.AddNotificationDelivery(&models.NotificationDelivery{
+		ID:      uuid.New().String(),
+		Channel: channel,
+		Event:   event,
+		Events:  events,
+		SentAt:  now,
+	})
+	if err != nil {
+		n.logger.WithError(err).WithField("channel", channel).Warn("Ошибка сохранения истории уведомлений")
+	}
+
+	var saved []string
+	for _, fingerprint := range fingerprints {
+		if fingerprint != "" {
+			saved = append(saved, fingerprint)
+		}
+	}
+	if err := n.store.SaveNotificationFingerprints(channel, saved, now); err != nil {
+		n.logger.WithError(err).WithField("channel", channel).Warn("Ошибка сохранения отпечатков уведомлений")
+	}
+}
+
+// QueueStatus описывает очередь канала после Flush
+type QueueStatus struct {
+	Channel string
+	Sent    int    // Событий отправлено
+	Pending int    // Событий осталось в очереди
+	Reason  string // Почему события остались в очереди
+	Err     error
+}
+
+// Flush отправляет сводки из очередей каналов, окно группировки которых истекло,
+// а лимит частоты позволяет отправку. force отправляет очереди, не дожидаясь
+// окончания окна. Очереди каналов, которых больше нет в конфигурации, очищаются
+func (n *NotificationManager) Flush(force bool) ([]QueueStatus, error) {
+	if n.store == nil {
+		return nil, nil
+	}
+
+	items, err := n.store.ListNotificationQueue("")
+	if err != nil {
+		return nil, i18n.Errorf("ошибка чтения очереди уведомлений: %w", err)
+	}
+
+	queues := make(map[string][]models.NotificationQueueItem)
+	for _, item := range items {
+		queues[item.Channel] = append(queues[item.Channel], item)
+	}
+
+	now := time.Now()
+	var statuses []QueueStatus
+	for _, channel := range n.channels {
+		queue := queues[channel.Name()]
+		delete(queues, channel.Name())
+		if len(queue) == 0 {
+			continue
+		}
+		statuses = append(statuses, n.flushChannel(channel, queue, force, now))
+	}
+
+	for channel, queue := range queues {
+		n.logger.WithField("channel", channel).Warnf("Удалено %d уведомлений из очереди канала, которого нет в конфигурации", len(queue))
+		n.drop(queue)
+	}
+
+	if err := n.store.PruneNotificationHistory(now.Add(-notificationHistoryTTL).UTC(), now.Add(-n.dedupWindow()).UTC()); err != nil {
+		n.logger.WithError(err).Warn("Ошибка очистки истории уведомлений")
+	}
+
+	return statuses, nil
+}
+
+// flushChannel отправляет очередь канала одним сообщением: единственное событие -
+// как есть, несколько - сводкой. При ошибке отправки события возвращаются в очередь
+func (n *NotificationManager) flushChannel(channel routedNotifier, queue []models.NotificationQueueItem, force bool, now time.Time) QueueStatus {
+	status := QueueStatus{Channel: channel.Name(), Pending: len(queue)}
+
+	// Очередь упорядочена от старых событий к новым
+	var expired []models.NotificationQueueItem
+	for len(queue) > 0 && now.Sub(queue[0].CreatedAt) > notificationQueueTTL {
+		expired = append(expired, queue[0])
+		queue = queue[1:]
+	}
+	if len(expired) > 0 {
+		n.logger.WithField("channel", channel.Name()).Warnf("Удалено %d уведомлений, не отправленных за %s", len(expired), notificationQueueTTL)
+		n.drop(expired)
+		status.Pending = len(queue)
+		if len(queue) == 0 {
+			return status
+		}
+	}
+
+	if due := queue[0].CreatedAt.Add(n.batchWindow()); !force && now.Before(due) {
+		status.Reason = i18n.T("окно группировки до ") + due.Local().Format("15:04:05")
+		return status
+	}
+	if n.rateLimited(channel, now) {
+		status.Reason = i18n.Sprintf("достигнут лимит %d сообщений в час", channel.rateLimit)
+		return status
+	}
+
+	ids := make([]string, len(queue))
+	notifications := make([]*Notification, 0, len(queue))
+	fingerprints := make([]string, 0, len(queue))
+	for i, item := range queue {
+		ids[i] = item.ID
+		var notification Notification
+		if err := json.Unmarshal([]byte(item.Payload), &notification); err != nil {
+			n.logger.WithError(err).WithField("channel", channel.Name()).Warn("Пропущено поврежденное уведомление в очереди")
+			continue
+		}
+		notifications = append(notifications, &notification)
+		fingerprints = append(fingerprints, item.Fingerprint)
+	}
+
+	// Очередь могла забрать другая команда aegis, запущенная одновременно
+	claimed, err := n.store.ClaimNotificationQueueItems(ids)
+	if err != nil || !claimed {
+		status.Err = err
+		status.Reason = i18n.T("очередь обрабатывается другим процессом")
+		return status
+	}
+	if len(notifications) == 0 {
+		status.Pending = 0
+		return status
+	}
+
+	message := notifications[0]
+	if len(notifications) > 1 {
+		message = NewScanSummaryNotification(notifications)
+	}
+
+	if errs := n.deliver([]routedNotifier{channel}, message, len(notifications), fingerprints); len(errs) > 0 {
+		status.Err = errs[0]
+		for _, item := range queue {
+			if err := n.store.AddNotificationQueueItem(&item); err != nil {
+				n.logger.WithError(err).WithField("channel", channel.Name()).Error("Ошибка возврата уведомления в очередь")
+			}
+		}
+		return status
+	}
+
+	status.Sent = len(notifications)
+	status.Pending = 0
+	return status
+}
+
+// drop удаляет уведомления из очереди без отправки
+func (n *NotificationManager) drop(items []models.NotificationQueueItem) {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	if _, err := n.store.ClaimNotificationQueueItems(ids); err != nil {
+		n.logger.WithError(err).Warn("Ошибка очистки очереди уведомлений")
+	}
+}
+
+// NewScanSummaryNotification объединяет события очереди в сводку по хостам. Уязвимости
+// контейнера учитываются по его последнему сканированию в сводке
+func NewScanSummaryNotification(notifications []*Notification) *Notification {
+	summary := &Notification{
+		Event:             NotificationScanSummary,
+		Title:             i18n.N("Aegis: Сводка сканирований"),
+		Events:            len(notifications),
+		Timestamp:         time.Now(),
+		Vulnerabilities:   &models.SeverityCounts{},
+		Secrets:           &models.SeverityCounts{},
+		Misconfigurations: &models.SeverityCounts{},
+	}
+
+	hosts := make(map[string]*NotificationHostSummary)
+	var order []string
+	latest := make(map[string]*Notification) // Последнее сканирование контейнера: хост|контейнер
+	var containers []string
+	var findings []NotificationFinding
+
+	for _, notification := range notifications {
+		notification.normalize()
+		if summary.Since == nil || notification.Timestamp.Before(*summary.Since) {
+			since := notification.Timestamp
+			summary.Since = &since
+		}
+
+		key := notification.HostID
+		if key == "" {
+			key = notification.Host
+		}
+		host, ok := hosts[key]
+		if !ok {
+			host = &NotificationHostSummary{Host: notification.Host, HostID: notification.HostID, Vulnerabilities: &models.SeverityCounts{}}
+			hosts[key] = host
+			order = append(order, key)
+		}
+
+		container := key + "|" + notification.Container
+		switch notification.Event {
+		case NotificationScanCompleted:
+			host.Scans++
+			host.NewVulnerabilities += notification.NewVulnerabilities
+			host.FixedVulnerabilities += notification.FixedVulnerabilities
+			summary.NewVulnerabilities += notification.NewVulnerabilities
+			summary.FixedVulnerabilities += notification.FixedVulnerabilities
+			findings = append(findings, notification.TopFindings...)
+
+			if previous, ok := latest[container]; !ok || !notification.Timestamp.Before(previous.Timestamp) {
+				if !ok {
+					containers = append(containers, container)
+				}
+				latest[container] = notification
+			}
+		case NotificationScanError:
+			host.Errors++
+			host.Failed = append(host.Failed, notification.Container)
+		}
+	}
+
+	for _, container := range containers {
+		notification := latest[container]
+		key, _, _ := strings.Cut(container, "|")
+		hosts[key].Containers++
+		hosts[key].Vulnerabilities.Merge(notification.Vulnerabilities)
+		summary.Vulnerabilities.Merge(notification.Vulnerabilities)
+		summary.Secrets.Merge(notification.Secrets)
+		summary.Misconfigurations.Merge(notification.Misconfigurations)
+	}
+
+	for _, key := range order {
+		summary.Hosts = append(summary.Hosts, *hosts[key])
+	}
+	summary.TopFindings = mergeFindings(findings)
+	return summary
+}
+
+// mergeFindings объединяет находки нескольких событий без повторов и оставляет
+// наиболее опасные
+func mergeFindings(findings []NotificationFinding) []NotificationFinding {
+	seen := make(map[string]bool, len(findings))
+	merged := make([]NotificationFinding, 0, len(findings))
+	for _, f := range findings {
+		key := f.Kind + "|" + f.ID + "|" + f.Package + "|" + f.Target
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, f)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return severityRank(merged[i].Severity) > severityRank(merged[j].Severity)
+	})
+	if len(merged) > notificationTopFindings {
+		merged = merged[:notificationTopFindings]
+	}
+	return merged
+}
+
+// scanFingerprint вычисляет отпечаток всех находок сканирования контейнера
+func scanFingerprint(notification *Notification, vulns []models.Vulnerability, findings []models.Finding) string {
+	keys := make([]string, 0, len(vulns)+len(findings))
+	for _, v := range vulns {
+		keys = append(keys, "vulnerability|"+vulnerabilityKey(v))
+	}
+	for _, f := range findings {
+		keys = append(keys, f.Kind+"|"+f.RuleID+"|"+f.Target)
+	}
+	return fingerprint(notification, keys)
+}
+
+// defaultFingerprint вычисляет отпечаток уведомления без полного списка находок,
+// например прочитанного из файла события, по статистике и наиболее опасным находкам
+func (n *Notification) defaultFingerprint() string {
+	keys := []string{n.Error}
+	for _, counts := range []*models.SeverityCounts{n.Vulnerabilities, n.Secrets, n.Misconfigurations} {
+		if counts != nil {
+			keys = append(keys, fmt.Sprintf("%d/%d/%d/%d", counts.Critical, counts.High, counts.Medium, counts.Low))
+		}
+	}
+	for _, f := range n.TopFindings {
+		keys = append(keys, f.Kind+"|"+f.ID+"|"+f.Package+"|"+f.Target)
+	}
+	return fingerprint(n, keys)
+}
+
+// fingerprint хэширует событие, контейнер и отсортированные ключи находок
+func fingerprint(n *Notification, keys []string) string {
+	sort.Strings(keys)
+
+	host := n.HostID
+	if host == "" {
+		host = n.Host
+	}
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n%s\n%s\n", n.Event, host, n.Container, n.Image)
+	for _, key := range keys {
+		fmt.Fprintln(hash, key)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:32]
+}
@@ -0,0 +1,329 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+)
+
+// Расписания дайджеста
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// digestDefaultTime - время отправки дайджеста, если оно не задано
+const digestDefaultTime = "09:00"
+
+// validateDigest проверяет расписание дайджеста
+func validateDigest(digest models.NotificationDigest) error {
+	switch digest.Schedule {
+	case "", DigestDaily, DigestWeekly:
+	default:
+		return i18n.Errorf("недопустимое расписание digest.schedule: %s (допустимо: %s, %s)", digest.Schedule, DigestDaily, DigestWeekly)
+	}
+	if digest.Time != "" {
+		if _, err := time.Parse("15:04", digest.Time); err != nil {
+			return i18n.Errorf("некорректное время digest.time: %s (ожидается ЧЧ:ММ)", digest.Time)
+		}
+	}
+	if digest.Weekday != "" {
+		if _, err := parseWeekday(digest.Weekday); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseWeekday разбирает английское название дня недели
+func parseWeekday(value string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), value) {
+			return day, nil
+		}
+	}
+	return 0, i18n.Errorf("некорректный день недели digest.weekday: %s (ожидается monday, tuesday и т.д.)", value)
+}
+
+// digestPeriod возвращает длину периода дайджеста
+func digestPeriod(digest models.NotificationDigest) time.Duration {
+	if digest.Schedule == DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// digestSlot возвращает время последней отправки дайджеста по расписанию, не позже now.
+// Расписание должно быть проверено validateDigest
+func digestSlot(digest models.NotificationDigest, now time.Time) time.Time {
+	at := digest.Time
+	if at == "" {
+		at = digestDefaultTime
+	}
+	clock, _ := time.Parse("15:04", at)
+
+	now = now.Local()
+	slot := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+
+	if digest.Schedule == DigestWeekly {
+		weekday := time.Monday
+		if digest.Weekday != "" {
+			weekday, _ = parseWeekday(digest.Weekday)
+		}
+		slot = slot.AddDate(0, 0, -((int(now.Weekday()) - int(weekday) + 7) % 7))
+	}
+	if slot.After(now) {
+		slot = slot.Add(-digestPeriod(digest))
+	}
+	return slot
+}
+
+// DigestStatus описывает состояние расписания дайджеста
+type DigestStatus struct {
+	Enabled  bool
+	Due      bool      // Дайджест по расписанию еще не отправлен
+	LastSent time.Time // Нулевое время - дайджест не отправлялся
+	Next     time.Time // Время следующей отправки по расписанию
+}
+
+// DigestStatus проверяет, пора ли отправлять дайджест по расписанию
+func (n *NotificationManager) DigestStatus() (DigestStatus, error) {
+	digest := n.config.Notification.Digest
+	if digest.Schedule == "" || n.digestErr != nil || n.store == nil {
+		return DigestStatus{}, n.digestErr
+	}
+
+	last, err := n.store.LastNotificationDelivery(NotificationDigest)
+	if err != nil {
+		return DigestStatus{}, i18n.Errorf("ошибка чтения истории уведомлений: %w", err)
+	}
+
+	slot := digestSlot(digest, time.Now())
+	return DigestStatus{
+		Enabled:  true,
+		Due:      last.Before(slot),
+		LastSent: last,
+		Next:     slot.Add(digestPeriod(digest)),
+	}, nil
+}
+
+// SendDigest отправляет дайджест в каналы notification.digest.channels (по умолчанию
+// во все), если он положен по расписанию. force отправляет дайджест вне расписания.
+// Возвращает отправленный дайджест или nil, если отправка не требуется
+func (n *NotificationManager) SendDigest(force bool) (*Notification, error) {
+	if n.store == nil {
+		return nil, i18n.New("для дайджеста необходима база данных")
+	}
+	if n.digestErr != nil {
+		return nil, n.digestErr
+	}
+
+	status, err := n.DigestStatus()
+	if err != nil {
+		return nil, err
+	}
+	if !force && !status.Due {
+		return nil, nil
+	}
+
+	// Дайджест вне расписания охватывает полный период, а не время с последней отправки
+	since := status.LastSent
+	if force {
+		since = time.Time{}
+	}
+	digest, err := n.BuildDigest(since)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := n.digestChannels()
+	if err != nil {
+		return nil, err
+	}
+	return digest, errors.Join(n.deliver(channels, digest, digest.Events, nil)...)
+}
+
+// digestChannels возвращает каналы, в которые отправляется дайджест
+func (n *NotificationManager) digestChannels() ([]routedNotifier, error) {
+	names := n.config.Notification.Digest.Channels
+	if len(names) == 0 {
+		if len(n.channels) == 0 {
+			return nil, i18n.New("каналы уведомлений не настроены")
+		}
+		return n.channels, nil
+	}
+
+	var channels []routedNotifier
+	for _, name := range names {
+		found := false
+		for _, channel := range n.channels {
+			if channel.Name() == name {
+				channels = append(channels, channel)
+				found = true
+				break
+			}
+		}
+		if !found {
+			n.logger.WithField("channel", name).Warn("Канал дайджеста не найден среди настроенных каналов")
+		}
+	}
+	if len(channels) == 0 {
+		return nil, i18n.Errorf("не найдены каналы дайджеста: %s", strings.Join(names, ", "))
+	}
+	return channels, nil
+}
+
+// BuildDigest формирует дайджест по хостам с момента последней отправки lastSent.
+// При нулевом lastSent или давней отправке берется период расписания до текущего момента.
+// Новые и исправленные уязвимости определяются сравнением последнего завершенного
+// сканирования каждого контейнера на конец и на начало периода
+func (n *NotificationManager) BuildDigest(lastSent time.Time) (*Notification, error) {
+	now := time.Now()
+	period := digestPeriod(n.config.Notification.Digest)
+	since := now.Add(-period)
+	if !lastSent.IsZero() && lastSent.After(now.Add(-2*period)) {
+		since = lastSent.Local()
+	}
+
+	title := i18n.N("Aegis: Ежедневная сводка")
+	if n.config.Notification.Digest.Schedule == DigestWeekly {
+		title = i18n.N("Aegis: Еженедельная сводка")
+	}
+	digest := &Notification{
+		Event:             NotificationDigest,
+		Title:             title,
+		Timestamp:         now,
+		Since:             &since,
+		Vulnerabilities:   &models.SeverityCounts{},
+		Secrets:           &models.SeverityCounts{},
+		Misconfigurations: &models.SeverityCounts{},
+	}
+
+	hosts, err := n.store.ListHosts()
+	if err != nil {
+		return nil, i18n.Errorf("ошибка получения списка хостов: %w", err)
+	}
+
+	var newVulns []models.Vulnerability
+	for _, host := range hosts {
+		summary, added, err := n.digestHost(host, since, now)
+		if err != nil {
+			return nil, err
+		}
+		if summary == nil {
+			continue
+		}
+		digest.Hosts = append(digest.Hosts, *summary)
+		digest.Events += summary.Scans + summary.Errors
+		digest.NewVulnerabilities += summary.NewVulnerabilities
+		digest.FixedVulnerabilities += summary.FixedVulnerabilities
+		digest.Vulnerabilities.Merge(summary.Vulnerabilities)
+		newVulns = append(newVulns, added...)
+	}
+
+	digest.TopFindings = topFindings(newVulns, nil)
+	return digest, nil
+}
+
+// digestHost подводит итоги периода по хосту. Возвращает nil для хоста без завершенных
+// сканирований и новые уязвимости хоста за период
+func (n *NotificationManager) digestHost(host models.Host, since, until time.Time) (*NotificationHostSummary, []models.Vulnerability, error) {
+	containers, err := n.store.ListContainers(host.ID)
+	if err != nil {
+		return nil, nil, i18n.Errorf("ошибка получения контейнеров хоста %s: %w", host.Name, err)
+	}
+	names := make(map[string]string, len(containers))
+	for _, c := range containers {
+		names[c.ID] = c.Name
+	}
+
+	scans, err := n.store.ListScans(host.ID, "")
+	if err != nil {
+		return nil, nil, i18n.Errorf("ошибка получения сканирований хоста %s: %w", host.Name, err)
+	}
+
+	// Сканирования отсортированы от новых к старым: первое подходящее - последнее
+	current := make(map[string]*models.Scan)
+	previous := make(map[string]*models.Scan)
+	summary := &NotificationHostSummary{Host: host.Name, HostID: host.ID, Vulnerabilities: &models.SeverityCounts{}}
+	var order []string
+	for i := range scans {
+		scan := &scans[i]
+		inPeriod := scan.StartedAt.After(since) && !scan.StartedAt.After(until)
+		switch {
+		case scan.Status == "failed" && inPeriod:
+			summary.Errors++
+			name := names[scan.ContainerID]
+			if name == "" {
+				name = scan.ContainerID
+			}
+			summary.Failed = append(summary.Failed, name)
+		case scan.Status != "completed" || scan.FinishedAt.After(until):
+		case current[scan.ContainerID] == nil:
+			current[scan.ContainerID] = scan
+			order = append(order, scan.ContainerID)
+			if inPeriod {
+				summary.Scans++
+			}
+		case !scan.FinishedAt.After(since) && previous[scan.ContainerID] == nil:
+			previous[scan.ContainerID] = scan
+		case inPeriod:
+			summary.Scans++
+		}
+	}
+	if len(current) == 0 && summary.Errors == 0 {
+		return nil, nil, nil
+	}
+
+	var added []models.Vulnerability
+	for _, containerID := range order {
+		cur := current[containerID]
+		vulns, err := n.store.ListVulnerabilities("", "", cur.ID, "")
+		if err != nil {
+			return nil, nil, i18n.Errorf("ошибка загрузки уязвимостей сканирования %s: %w", cur.ID, err)
+		}
+		summary.Containers++
+		for _, v := range vulns {
+			summary.Vulnerabilities.Add(v.Severity)
+		}
+
+		// Контейнер не сканировался в течение периода: изменений нет
+		if !cur.FinishedAt.After(since) {
+			continue
+		}
+
+		before := make(map[string]bool)
+		if prev := previous[containerID]; prev != nil {
+			prevVulns, err := n.store.ListVulnerabilities("", "", prev.ID, "")
+			if err != nil {
+				return nil, nil, i18n.Errorf("ошибка загрузки уязвимостей сканирования %s: %w", prev.ID, err)
+			}
+			for _, v := range prevVulns {
+				before[vulnerabilityKey(v)] = true
+			}
+		}
+
+		after := make(map[string]bool, len(vulns))
+		for _, v := range vulns {
+			key := vulnerabilityKey(v)
+			if after[key] {
+				continue
+			}
+			after[key] = true
+			if !before[key] {
+				summary.NewVulnerabilities++
+				added = append(added, v)
+			}
+		}
+		for key := range before {
+			if !after[key] {
+				summary.FixedVulnerabilities++
+			}
+		}
+	}
+
+	return summary, added, nil
+}
@@ -1,266 +1,559 @@
 package utils
 
 import (
-	"bytes"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"os"
-	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aegis/aegis-cli/pkg/config"
+	"github.com/aegis/aegis-cli/pkg/i18n"
 	"github.com/aegis/aegis-cli/pkg/models"
-	"github.com/gen2brain/beeep"
 	"github.com/sirupsen/logrus"
 )
 
-// NotificationManager управляет отправкой уведомлений
+// События уведомлений
+const (
+	NotificationScanCompleted = "scan_completed"
+	NotificationScanError     = "scan_error"
+	NotificationReport        = "report"
+	NotificationScanSummary   = "scan_summary" // Сводка событий, накопленных в очереди канала
+	NotificationDigest        = "digest"       // Дайджест за сутки или неделю по расписанию
+)
+
+// Notification представляет событие, о котором уведомляются все каналы
+type Notification struct {
+	Event     string        `json:"event"`
+	Title     string        `json:"title"` // Исходная строка без перевода, см. DisplayTitle
+	Host      string        `json:"host"`
+	HostID    string        `json:"host_id,omitempty"`
+	Container string        `json:"container"`
+	Image     string        `json:"image,omitempty"`
+	ScanID    string        `json:"scan_id,omitempty"`
+	Duration  time.Duration `json:"-"`
+	Error     string        `json:"error,omitempty"`
+	Timestamp time.Time     `json:"timestamp"`
+
+	// Статистика находок, только для NotificationScanCompleted
+	Vulnerabilities   *models.SeverityCounts `json:"vulnerabilities,omitempty"`
+	Secrets           *models.SeverityCounts `json:"secrets,omitempty"`
+	Misconfigurations *models.SeverityCounts `json:"misconfigurations,omitempty"`
+
+	// Сравнение с предыдущим сканированием контейнера. Без previous_scan_id сканирование считается первым
+	PreviousScanID       string `json:"previous_scan_id,omitempty"`
+	NewVulnerabilities   int    `json:"new_vulnerabilities,omitempty"`
+	FixedVulnerabilities int    `json:"fixed_vulnerabilities,omitempty"`
+
+	// Наиболее опасные находки сканирования, не более notificationTopFindings.
+	// В дайджесте - наиболее опасные из новых уязвимостей
+	TopFindings []NotificationFinding `json:"top_findings,omitempty"`
+
+	// Fingerprint идентифицирует набор находок контейнера для подавления повторов
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// Итоги по хостам, только для NotificationScanSummary и NotificationDigest
+	Hosts  []NotificationHostSummary `json:"hosts,omitempty"`
+	Events int                       `json:"events,omitempty"` // Число объединенных событий или сканирований за период
+	Since  *time.Time                `json:"since,omitempty"`  // Время первого события сводки или начало периода дайджеста
+}
+
+// DisplayTitle возвращает заголовок уведомления на языке пользователя. В JSON webhook
+// заголовок передается без перевода, чтобы не зависеть от языка CLI
+func (n *Notification) DisplayTitle() string {
+	return i18n.T(n.Title)
+}
+
+// NotificationHostSummary содержит итоги сканирований одного хоста в сводке или дайджесте
+type NotificationHostSummary struct {
+	Host                 string                 `json:"host"`
+	HostID               string                 `json:"host_id,omitempty"`
+	Containers           int                    `json:"containers"` // Просканированных контейнеров
+	Scans                int                    `json:"scans"`      // Завершенных сканирований
+	Errors               int                    `json:"errors,omitempty"`
+	Failed               []string               `json:"failed,omitempty"` // Контейнеры, сканирование которых завершилось ошибкой
+	NewVulnerabilities   int                    `json:"new_vulnerabilities"`
+	FixedVulnerabilities int                    `json:"fixed_vulnerabilities"`
+	Vulnerabilities      *models.SeverityCounts `json:"vulnerabilities"` // Уязвимости по последним сканированиям контейнеров
+}
+
+// notificationTopFindings ограничивает число находок, передаваемых в уведомлении
+const notificationTopFindings = 10
+
+// NotificationFinding представляет уязвимость, секрет или ошибку конфигурации в уведомлении
+type NotificationFinding struct {
+	Kind             string `json:"kind"` // vulnerability, secret, misconfig
+	ID               string `json:"id"`   // CVE-ID или ID правила
+	Severity         string `json:"severity"`
+	Title            string `json:"title,omitempty"`
+	Package          string `json:"package,omitempty"`
+	InstalledVersion string `json:"installed_version,omitempty"`
+	FixedVersion     string `json:"fixed_version,omitempty"`
+	Target           string `json:"target,omitempty"` // Файл, в котором найден секрет или ошибка конфигурации
+}
+
+// normalize заполняет время и статистику, которых нет в уведомлении, например
+// прочитанном из файла события
+func (n *Notification) normalize() {
+	if n.Timestamp.IsZero() {
+		n.Timestamp = time.Now()
+	}
+	if n.Fingerprint == "" && (n.Event == NotificationScanCompleted || n.Event == NotificationScanError) {
+		n.Fingerprint = n.defaultFingerprint()
+	}
+	if n.Event != NotificationScanCompleted && n.Event != NotificationScanSummary && n.Event != NotificationDigest {
+		return
+	}
+	for _, counts := range []**models.SeverityCounts{&n.Vulnerabilities, &n.Secrets, &n.Misconfigurations} {
+		if *counts == nil {
+			*counts = &models.SeverityCounts{}
+		}
+	}
+}
+
+// Changed проверяет, изменились ли уязвимости по сравнению с предыдущим сканированием
+func (n *Notification) Changed() bool {
+	return n.PreviousScanID == "" || n.NewVulnerabilities > 0 || n.FixedVulnerabilities > 0
+}
+
+// AtLeast возвращает количество находок сканирования с серьезностью не ниже указанной
+func (n *Notification) AtLeast(severity string) int {
+	total := 0
+	for _, counts := range []*models.SeverityCounts{n.Vulnerabilities, n.Secrets, n.Misconfigurations} {
+		if counts != nil {
+			total += counts.AtLeast(severity)
+		}
+	}
+	return total
+}
+
+// Notifier отправляет уведомления в один канал
+type Notifier interface {
+	// Name возвращает имя канала для журнала
+	Name() string
+	// Notify отправляет уведомление о событии
+	Notify(notification *Notification) error
+}
+
+// ReportSender реализуют каналы, которые могут передать файл отчета
+type ReportSender interface {
+	// SendReport отправляет файл отчета с уведомлением NotificationReport
+	SendReport(notification *Notification, filePath string) error
+}
+
+// Previewer реализуют каналы, которые могут показать сообщение без отправки
+type Previewer interface {
+	// Preview возвращает сообщение в том виде, в котором оно будет отправлено
+	Preview(notification *Notification) (string, error)
+}
+
+// NotifierFactory создает канал уведомлений из его конфигурации. Каналы с текстом
+// формируют сообщения по шаблонам из templates
+type NotifierFactory func(channel models.NotificationChannel, templates *Templates) (Notifier, error)
+
+// notifierFactories содержит зарегистрированные типы каналов
+var notifierFactories = map[string]NotifierFactory{}
+
+// RegisterNotifier регистрирует тип канала уведомлений. Каналы регистрируются в init
+// своих файлов, поэтому новый канал не требует изменений в NotificationManager
+func RegisterNotifier(channelType string, factory NotifierFactory) {
+	notifierFactories[channelType] = factory
+}
+
+// NotificationManager управляет отправкой уведомлений во все настроенные каналы
 type NotificationManager struct {
-	config *config.CliConfig
-	logger *logrus.Logger
+	config    *config.CliConfig
+	logger    *logrus.Logger
+	templates *Templates
+	channels  []routedNotifier
+	broken    []RouteDecision   // Каналы, пропущенные из-за ошибок в конфигурации
+	routeErr  error             // Ошибка в общих правилах notification.route
+	store     NotificationStore // nil - уведомления отправляются сразу, без очереди и истории
+	digestErr error             // Ошибка в расписании notification.digest
 }
 
-// TelegramMessage представляет сообщение для Telegram API
-type TelegramMessage struct {
-	ChatID    string `json:"chat_id"`
-	Text      string `json:"text"`
-	ParseMode string `json:"parse_mode,omitempty"`
+// routedNotifier связывает канал с его правилами маршрутизации
+type routedNotifier struct {
+	Notifier
+	route     models.NotificationRoute
+	rateLimit int // Сообщений в час, 0 - без ограничения
 }
 
-// NewNotificationManager создает новый менеджер уведомлений
+// NewNotificationManager создает новый менеджер уведомлений. Каналы с ошибками
+// в конфигурации пропускаются с записью в журнал
 func NewNotificationManager(cfg *config.CliConfig, logger *logrus.Logger) *NotificationManager {
-	return &NotificationManager{
-		config: cfg,
-		logger: logger,
+	n := &NotificationManager{
+		config:    cfg,
+		logger:    logger,
+		templates: NewTemplates(cfg.Notification.TemplatesDir, logger),
 	}
+
+	if err := validateRoute(cfg.Notification.Route); err != nil {
+		logger.WithError(err).Error("Ошибка в правилах маршрутизации уведомлений")
+		n.routeErr = err
+	}
+	if err := validateDigest(cfg.Notification.Digest); err != nil {
+		logger.WithError(err).Error("Ошибка в расписании дайджеста уведомлений")
+		n.digestErr = err
+	}
+
+	for _, channel := range notificationChannels(cfg.Notification) {
+		if channel.Disabled {
+			continue
+		}
+		// Каналы Telegram без своего адреса используют общий адрес Bot API
+		if channel.Type == models.NotificationChannelTelegram && channel.URL == "" {
+			channel.URL = cfg.TelegramAPIURL
+		}
+
+		notifier, err := newNotifier(channel, n.templates)
+		if err != nil {
+			logger.WithError(err).WithField("channel", channel.DisplayName()).Error("Ошибка настройки канала уведомлений")
+			n.broken = append(n.broken, RouteDecision{Channel: channel.DisplayName(), Reason: i18n.T("ошибка настройки: ") + err.Error()})
+			continue
+		}
+		n.channels = append(n.channels, routedNotifier{
+			Notifier:  notifier,
+			route:     channel.Route,
+			rateLimit: channelRateLimit(cfg.Notification, channel),
+		})
+	}
+
+	return n
 }
 
-// SendScanCompletedNotification отправляет уведомление о завершении сканирования
-func (n *NotificationManager) SendScanCompletedNotification(
-	hostName, containerName string, 
-	vulns []models.Vulnerability, 
-	scanDuration time.Duration) error {
-	
-	if !n.config.Notification.Enabled {
-		return nil
+// newNotifier создает канал зарегистрированного типа и проверяет его правила маршрутизации
+func newNotifier(channel models.NotificationChannel, templates *Templates) (Notifier, error) {
+	factory, ok := notifierFactories[channel.Type]
+	if !ok {
+		return nil, i18n.Errorf("неизвестный тип канала уведомлений: %s", channel.Type)
+	}
+	if err := validateRoute(channel.Route); err != nil {
+		return nil, err
 	}
+	return factory(channel, templates)
+}
 
-	// Подготавливаем сообщение
-	title := "Aegis: Сканирование завершено"
-	message := fmt.Sprintf("Хост: %s\nКонтейнер: %s\nНайдено уязвимостей: %d\nВремя сканирования: %s", 
-		hostName, containerName, len(vulns), scanDuration.String())
-	
-	// Отправляем системное уведомление
-	if err := beeep.Notify(title, message, ""); err != nil {
-		n.logger.WithError(err).Error("Ошибка отправки системного уведомления")
+// notificationChannels возвращает каналы из конфигурации. Без списка каналов используются
+// системные уведомления и Telegram, как в предыдущих версиях
+func notificationChannels(cfg models.NotificationConfig) []models.NotificationChannel {
+	if len(cfg.Channels) > 0 {
+		return cfg.Channels
 	}
-	
-	// Отправляем уведомление в Telegram, если настроено
-	if n.config.Notification.TelegramBot && 
-		n.config.Notification.TelegramToken != "" && 
-		n.config.Notification.TelegramChatID != "" {
-		
-		// Формируем более подробное сообщение для Telegram
-		var telegramMsg string
-		telegramMsg = fmt.Sprintf("*%s*\n\n", title)
-		telegramMsg += fmt.Sprintf("🖥 *Хост:* %s\n", hostName)
-		telegramMsg += fmt.Sprintf("🐳 *Контейнер:* %s\n", containerName)
-		telegramMsg += fmt.Sprintf("⏱ *Время сканирования:* %s\n\n", scanDuration.String())
-		
-		// Добавляем статистику по уязвимостям
-		var criticalCount, highCount, mediumCount, lowCount int
-		for _, v := range vulns {
-			switch v.Severity {
-			case "CRITICAL":
-				criticalCount++
-			case "HIGH":
-				highCount++
-			case "MEDIUM":
-				mediumCount++
-			case "LOW":
-				lowCount++
-			}
+
+	channels := []models.NotificationChannel{{Type: models.NotificationChannelDesktop}}
+	if cfg.TelegramBot && cfg.TelegramToken != "" && cfg.TelegramChatID != "" {
+		channels = append(channels, models.NotificationChannel{
+			Type:   models.NotificationChannelTelegram,
+			Token:  cfg.TelegramToken,
+			ChatID: cfg.TelegramChatID,
+		})
+	}
+	return channels
+}
+
+// Route определяет, в какие каналы будет отправлено уведомление, ничего не отправляя.
+// Каналы с ошибками в конфигурации возвращаются в конце списка
+func (n *NotificationManager) Route(notification *Notification) []RouteDecision {
+	notification.normalize()
+
+	now := time.Now()
+	decisions := make([]RouteDecision, 0, len(n.channels)+len(n.broken))
+	for _, channel := range n.channels {
+		reason := n.skipReason(channel, notification)
+		if reason != "" {
+			decisions = append(decisions, RouteDecision{Channel: channel.Name(), Reason: reason})
+			continue
 		}
-		
-		telegramMsg += "*Найденные уязвимости:*\n"
-		telegramMsg += fmt.Sprintf("🔴 Критических: %d\n", criticalCount)
-		telegramMsg += fmt.Sprintf("🟠 Высоких: %d\n", highCount)
-		telegramMsg += fmt.Sprintf("🟡 Средних: %d\n", mediumCount)
-		telegramMsg += fmt.Sprintf("🟢 Низких: %d\n", lowCount)
-		telegramMsg += fmt.Sprintf("*Всего:* %d\n", len(vulns))
-		
-		// Отправляем сообщение в Telegram
-		if err := n.sendTelegramMessage(telegramMsg); err != nil {
-			n.logger.WithError(err).Error("Ошибка отправки уведомления в Telegram")
+		if reason = n.duplicateReason(channel, notification, now); reason != "" {
+			decisions = append(decisions, RouteDecision{Channel: channel.Name(), Reason: reason})
+			continue
 		}
+		decisions = append(decisions, RouteDecision{Channel: channel.Name(), Deliver: true, Reason: n.queueReason(channel, now)})
 	}
-	
-	return nil
+	return append(decisions, n.broken...)
 }
 
-// SendScanErrorNotification отправляет уведомление об ошибке сканирования
-func (n *NotificationManager) SendScanErrorNotification(
-	hostName, containerName string, 
-	errorMsg string) error {
-	
-	if !n.config.Notification.Enabled {
-		return nil
+// skipReason возвращает причину, по которой уведомление не отправляется в канал,
+// или пустую строку. Общие правила проверяются раньше правил канала
+func (n *NotificationManager) skipReason(channel routedNotifier, notification *Notification) string {
+	switch {
+	case !n.config.Notification.Enabled:
+		return i18n.T("уведомления отключены (notification.enabled)")
+	case n.routeErr != nil:
+		return i18n.T("ошибка в notification.route: ") + n.routeErr.Error()
+	}
+	if reason := checkRoute(n.config.Notification.Route, notification); reason != "" {
+		return reason
 	}
+	return checkRoute(channel.route, notification)
+}
+
+// Dispatch отправляет уведомление параллельно во все каналы, правила которых
+// его пропускают. Если подключено хранилище, повторы тех же находок подавляются,
+// а в каналах с окном группировки или ограничением частоты уведомление ставится
+// в очередь и отправляется сводкой. Ошибки каналов записываются в журнал и возвращаются вместе
+func (n *NotificationManager) Dispatch(notification *Notification) error {
+	notification.normalize()
+
+	now := time.Now()
+	var (
+		direct []routedNotifier
+		errs   []error
+	)
+	for _, channel := range n.channels {
+		reason := n.skipReason(channel, notification)
+		if reason == "" {
+			reason = n.duplicateReason(channel, notification, now)
+		}
+		if reason != "" {
+			n.logger.WithFields(logrus.Fields{
+				"channel": channel.Name(),
+				"event":   notification.Event,
+			}).Debugf("Уведомление не отправлено: %s", reason)
+			continue
+		}
 
-	// Подготавливаем сообщение
-	title := "Aegis: Ошибка сканирования"
-	message := fmt.Sprintf("Хост: %s\nКонтейнер: %s\nОшибка: %s", 
-		hostName, containerName, errorMsg)
-	
-	// Отправляем системное уведомление
-	if err := beeep.Notify(title, message, ""); err != nil {
-		n.logger.WithError(err).Error("Ошибка отправки системного уведомления")
+		if n.queueReason(channel, now) == "" {
+			direct = append(direct, channel)
+			continue
+		}
+		if err := n.enqueue(channel, notification, now); err != nil {
+			// Уведомление, которое не удалось поставить в очередь, отправляется сразу
+			n.logger.WithError(err).WithField("channel", channel.Name()).Error("Ошибка постановки уведомления в очередь")
+			direct = append(direct, channel)
+		}
 	}
-	
-	// Отправляем уведомление в Telegram, если настроено
-	if n.config.Notification.TelegramBot && 
-		n.config.Notification.TelegramToken != "" && 
-		n.config.Notification.TelegramChatID != "" {
-		
-		// Формируем более подробное сообщение для Telegram
-		var telegramMsg string
-		telegramMsg = fmt.Sprintf("*%s*\n\n", title)
-		telegramMsg += fmt.Sprintf("🖥 *Хост:* %s\n", hostName)
-		telegramMsg += fmt.Sprintf("🐳 *Контейнер:* %s\n", containerName)
-		telegramMsg += fmt.Sprintf("❌ *Ошибка:* %s\n", errorMsg)
-		
-		// Отправляем сообщение в Telegram
-		if err := n.sendTelegramMessage(telegramMsg); err != nil {
-			n.logger.WithError(err).Error("Ошибка отправки уведомления в Telegram")
+
+	errs = append(errs, n.deliver(direct, notification, 1, []string{notification.Fingerprint})...)
+
+	// Отправка сводок, окно или лимит которых истекли
+	if n.store != nil {
+		if _, err := n.Flush(false); err != nil {
+			errs = append(errs, err)
 		}
 	}
-	
-	return nil
+
+	return errors.Join(errs...)
 }
 
-// SendTelegramReport отправляет файл отчета в Telegram
-func (n *NotificationManager) SendTelegramReport(
-	hostName, containerName string, 
-	filePath string) error {
-	
-	if !n.config.Notification.Enabled || 
-		!n.config.Notification.TelegramBot || 
-		n.config.Notification.TelegramToken == "" || 
-		n.config.Notification.TelegramChatID == "" {
-		return nil
-	}
-	
-	// Формируем заголовок для сообщения
-	caption := fmt.Sprintf("*Отчет о сканировании*\n\n🖥 *Хост:* %s\n🐳 *Контейнер:* %s", 
-		hostName, containerName)
-	
-	// Получаем тип MIME файла
-	fileType := "application/json"
-	if filepath.Ext(filePath) == ".csv" {
-		fileType = "text/csv"
+// deliver отправляет сообщение параллельно в каналы и записывает успешные отправки
+// в историю. events - число событий в сообщении, fingerprints - отпечатки их находок
+func (n *NotificationManager) deliver(channels []routedNotifier, notification *Notification, events int, fingerprints []string) []error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, channel := range channels {
+		wg.Add(1)
+		go func(notifier Notifier) {
+			defer wg.Done()
+			if err := notifier.Notify(notification); err != nil {
+				n.logger.WithError(err).WithField("channel", notifier.Name()).Error("Ошибка отправки уведомления")
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", notifier.Name(), err))
+				mu.Unlock()
+				return
+			}
+			n.recordDelivery(notifier.Name(), notification.Event, events, fingerprints)
+		}(channel.Notifier)
 	}
-	
-	// Отправляем файл в Telegram
-	if err := n.sendTelegramFile(filePath, fileType, caption); err != nil {
-		n.logger.WithError(err).Error("Ошибка отправки отчета в Telegram")
-		return err
+	wg.Wait()
+
+	return errs
+}
+
+// MessagePreview содержит сообщение канала, сформированное без отправки
+type MessagePreview struct {
+	Channel string
+	Message string
+	Err     error
+}
+
+// Preview формирует сообщения всех каналов, которые поддерживают предпросмотр,
+// независимо от правил маршрутизации
+func (n *NotificationManager) Preview(notification *Notification) []MessagePreview {
+	notification.normalize()
+
+	var previews []MessagePreview
+	for _, channel := range n.channels {
+		previewer, ok := channel.Notifier.(Previewer)
+		if !ok {
+			continue
+		}
+		message, err := previewer.Preview(notification)
+		previews = append(previews, MessagePreview{Channel: channel.Name(), Message: message, Err: err})
 	}
-	
-	return nil
+	return previews
 }
 
-// sendTelegramMessage отправляет текстовое сообщение в Telegram
-func (n *NotificationManager) sendTelegramMessage(message string) error {
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", 
-		n.config.Notification.TelegramToken)
-	
-	telegramMsg := TelegramMessage{
-		ChatID:    n.config.Notification.TelegramChatID,
-		Text:      message,
-		ParseMode: "Markdown",
+// ScanResult содержит данные завершенного сканирования для уведомления
+type ScanResult struct {
+	Host            models.Host
+	Container       models.Container
+	Scan            models.Scan
+	Vulnerabilities []models.Vulnerability
+	Findings        []models.Finding
+
+	// Предыдущее завершенное сканирование контейнера, nil - сканирование первое
+	PreviousScan            *models.Scan
+	PreviousVulnerabilities []models.Vulnerability
+}
+
+// SendScanCompletedNotification отправляет уведомление о завершении сканирования
+func (n *NotificationManager) SendScanCompletedNotification(result ScanResult) error {
+	return n.Dispatch(NewScanCompletedNotification(result))
+}
+
+// NewScanCompletedNotification формирует уведомление о завершении сканирования
+// и сравнивает его уязвимости с предыдущим сканированием
+func NewScanCompletedNotification(result ScanResult) *Notification {
+	containerName := result.Container.Name
+	if containerName == "" {
+		containerName = result.Scan.ContainerID
 	}
-	
-	jsonData, err := json.Marshal(telegramMsg)
-	if err != nil {
-		return fmt.Errorf("ошибка маршалинга JSON: %w", err)
+
+	notification := &Notification{
+		Event:             NotificationScanCompleted,
+		Title:             i18n.N("Aegis: Сканирование завершено"),
+		Host:              result.Host.Name,
+		HostID:            result.Host.ID,
+		Container:         containerName,
+		Image:             result.Container.Image,
+		ScanID:            result.Scan.ID,
+		Duration:          result.Scan.FinishedAt.Sub(result.Scan.StartedAt),
+		Vulnerabilities:   &models.SeverityCounts{},
+		Secrets:           countFindings(result.Findings, models.FindingKindSecret),
+		Misconfigurations: countFindings(result.Findings, models.FindingKindMisconfig),
 	}
-	
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("ошибка отправки HTTP запроса: %w", err)
+
+	current := make(map[string]bool, len(result.Vulnerabilities))
+	for _, v := range result.Vulnerabilities {
+		notification.Vulnerabilities.Add(v.Severity)
+		current[vulnerabilityKey(v)] = true
 	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("ошибка API Telegram: %s", string(body))
+
+	notification.TopFindings = topFindings(result.Vulnerabilities, result.Findings)
+	notification.Fingerprint = scanFingerprint(notification, result.Vulnerabilities, result.Findings)
+
+	if result.PreviousScan != nil {
+		notification.PreviousScanID = result.PreviousScan.ID
+		previous := make(map[string]bool, len(result.PreviousVulnerabilities))
+		for _, v := range result.PreviousVulnerabilities {
+			previous[vulnerabilityKey(v)] = true
+			if !current[vulnerabilityKey(v)] {
+				notification.FixedVulnerabilities++
+			}
+		}
+		for key := range current {
+			if !previous[key] {
+				notification.NewVulnerabilities++
+			}
+		}
 	}
-	
-	return nil
+
+	return notification
 }
 
-// sendTelegramFile отправляет файл в Telegram
-func (n *NotificationManager) sendTelegramFile(filePath, fileType, caption string) error {
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendDocument", 
-		n.config.Notification.TelegramToken)
-	
-	// Открываем файл
-	file, err := os.Open(filePath)
-	if err != nil {
-		return fmt.Errorf("ошибка открытия файла: %w", err)
+// topFindings возвращает наиболее опасные уязвимости и находки сканирования
+func topFindings(vulns []models.Vulnerability, findings []models.Finding) []NotificationFinding {
+	top := make([]NotificationFinding, 0, len(vulns)+len(findings))
+	for _, v := range vulns {
+		top = append(top, NotificationFinding{
+			Kind:             "vulnerability",
+			ID:               v.VulnerabilityID,
+			Severity:         strings.ToUpper(v.Severity),
+			Title:            v.Title,
+			Package:          v.Package,
+			InstalledVersion: v.InstalledVersion,
+			FixedVersion:     v.FixedVersion,
+		})
 	}
-	defer file.Close()
-	
-	// Создаем буфер для формирования multipart/form-data запроса
-	var requestBody bytes.Buffer
-	
-	// Добавляем chat_id
-	requestBody.WriteString("--boundary\r\n")
-	requestBody.WriteString("Content-Disposition: form-data; name=\"chat_id\"\r\n\r\n")
-	requestBody.WriteString(n.config.Notification.TelegramChatID + "\r\n")
-	
-	// Добавляем caption с Markdown
-	requestBody.WriteString("--boundary\r\n")
-	requestBody.WriteString("Content-Disposition: form-data; name=\"caption\"\r\n\r\n")
-	requestBody.WriteString(caption + "\r\n")
-	requestBody.WriteString("--boundary\r\n")
-	requestBody.WriteString("Content-Disposition: form-data; name=\"parse_mode\"\r\n\r\n")
-	requestBody.WriteString("Markdown\r\n")
-	
-	// Добавляем файл
-	requestBody.WriteString("--boundary\r\n")
-	requestBody.WriteString(fmt.Sprintf("Content-Disposition: form-data; name=\"document\"; filename=\"%s\"\r\n", 
-		filepath.Base(filePath)))
-	requestBody.WriteString(fmt.Sprintf("Content-Type: %s\r\n\r\n", fileType))
-	
-	// Копируем содержимое файла в буфер
-	if _, err := io.Copy(&requestBody, file); err != nil {
-		return fmt.Errorf("ошибка копирования файла в буфер: %w", err)
+	for _, f := range findings {
+		top = append(top, NotificationFinding{
+			Kind:     f.Kind,
+			ID:       f.RuleID,
+			Severity: strings.ToUpper(f.Severity),
+			Title:    f.Title,
+			Target:   f.Target,
+		})
 	}
-	
-	requestBody.WriteString("\r\n--boundary--\r\n")
-	
-	// Отправляем запрос
-	req, err := http.NewRequest("POST", url, &requestBody)
-	if err != nil {
-		return fmt.Errorf("ошибка создания HTTP запроса: %w", err)
+
+	sort.SliceStable(top, func(i, j int) bool {
+		return severityRank(top[i].Severity) > severityRank(top[j].Severity)
+	})
+	if len(top) > notificationTopFindings {
+		top = top[:notificationTopFindings]
+	}
+	return top
+}
+
+// severityRank возвращает вес уровня серьезности: чем опаснее, тем больше
+func severityRank(severity string) int {
+	for i, s := range routeSeverities {
+		if strings.EqualFold(s, severity) {
+			return len(routeSeverities) - i
+		}
+	}
+	return 0
+}
+
+// vulnerabilityKey идентифицирует уязвимость пакета независимо от сканирования
+func vulnerabilityKey(v models.Vulnerability) string {
+	return v.VulnerabilityID + "|" + v.Package
+}
+
+// SendScanErrorNotification отправляет уведомление об ошибке сканирования
+func (n *NotificationManager) SendScanErrorNotification(
+	hostName, containerName string,
+	errorMsg string) error {
+
+	return n.Dispatch(&Notification{
+		Event:     NotificationScanError,
+		Title:     i18n.N("Aegis: Ошибка сканирования"),
+		Host:      hostName,
+		Container: containerName,
+		Error:     errorMsg,
+	})
+}
+
+// SendReport отправляет файл отчета во все каналы, которые поддерживают файлы
+func (n *NotificationManager) SendReport(
+	hostName, containerName string,
+	filePath string) error {
+
+	notification := &Notification{
+		Event:     NotificationReport,
+		Title:     i18n.N("Aegis: Отчет о сканировании"),
+		Host:      hostName,
+		Container: containerName,
+		Timestamp: time.Now(),
 	}
-	
-	req.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
-	
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("ошибка отправки HTTP запроса: %w", err)
+
+	var errs []error
+	for _, channel := range n.channels {
+		sender, ok := channel.Notifier.(ReportSender)
+		if !ok || n.skipReason(channel, notification) != "" {
+			continue
+		}
+		if err := sender.SendReport(notification, filePath); err != nil {
+			n.logger.WithError(err).WithField("channel", channel.Name()).Error("Ошибка отправки отчета")
+			errs = append(errs, fmt.Errorf("%s: %w", channel.Name(), err))
+		}
 	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("ошибка API Telegram: %s", string(body))
+
+	return errors.Join(errs...)
+}
+
+// countFindings подсчитывает находки указанного вида по уровням серьезности
+func countFindings(findings []models.Finding, kind string) *models.SeverityCounts {
+	counts := &models.SeverityCounts{}
+	for _, f := range findings {
+		if f.Kind == kind {
+			counts.Add(f.Severity)
+		}
 	}
-	
-	return nil
-} 
+	return counts
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/gen2brain/beeep"
+)
+
+func init() {
+	RegisterNotifier(models.NotificationChannelDesktop, func(channel models.NotificationChannel, templates *Templates) (Notifier, error) {
+		return &desktopNotifier{name: channel.DisplayName(), text: templates.Channel(channel, formatText)}, nil
+	})
+}
+
+// desktopNotifier показывает системные уведомления
+type desktopNotifier struct {
+	name string
+	text *MessageTemplate
+}
+
+func (d *desktopNotifier) Name() string {
+	return d.name
+}
+
+func (d *desktopNotifier) Notify(notification *Notification) error {
+	message, err := d.text.Render(notification)
+	if err != nil {
+		return err
+	}
+	return beeep.Notify(notification.DisplayTitle(), message, "")
+}
+
+func (d *desktopNotifier) Preview(notification *Notification) (string, error) {
+	return d.text.Render(notification)
+}
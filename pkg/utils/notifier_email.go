@@ -0,0 +1,289 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+)
+
+// Режимы шифрования соединения с SMTP-сервером
+const (
+	emailTLSStartTLS = "starttls"
+	emailTLSImplicit = "implicit"
+	emailTLSNone     = "none"
+)
+
+// emailTimeout ограничивает время всего SMTP-сеанса
+const emailTimeout = 30 * time.Second
+
+func init() {
+	RegisterNotifier(models.NotificationChannelEmail, newEmailNotifier)
+}
+
+// emailNotifier отправляет уведомления письмом через SMTP
+type emailNotifier struct {
+	channel models.NotificationChannel
+	tlsMode string
+	addr    string
+	text    *MessageTemplate
+	html    *MessageTemplate
+}
+
+// newEmailNotifier проверяет параметры SMTP и создает канал email
+func newEmailNotifier(channel models.NotificationChannel, templates *Templates) (Notifier, error) {
+	if channel.Host == "" || channel.From == "" || len(channel.To) == 0 {
+		return nil, i18n.Errorf("для канала email необходимо указать host, from и to")
+	}
+	if _, err := mail.ParseAddress(channel.From); err != nil {
+		return nil, i18n.Errorf("некорректный адрес отправителя %q: %w", channel.From, err)
+	}
+	for _, to := range channel.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return nil, i18n.Errorf("некорректный адрес получателя %q: %w", to, err)
+		}
+	}
+
+	tlsMode := channel.TLS
+	if tlsMode == "" {
+		tlsMode = emailTLSStartTLS
+		if channel.Port == 465 {
+			tlsMode = emailTLSImplicit
+		}
+	}
+
+	port := channel.Port
+	switch tlsMode {
+	case emailTLSStartTLS:
+		if port == 0 {
+			port = 587
+		}
+	case emailTLSImplicit:
+		if port == 0 {
+			port = 465
+		}
+	case emailTLSNone:
+		if port == 0 {
+			port = 25
+		}
+	default:
+		return nil, i18n.Errorf("недопустимый режим tls: %s (допустимо: %s, %s, %s)",
+			tlsMode, emailTLSStartTLS, emailTLSImplicit, emailTLSNone)
+	}
+
+	return &emailNotifier{
+		channel: channel,
+		tlsMode: tlsMode,
+		addr:    net.JoinHostPort(channel.Host, strconv.Itoa(port)),
+		text:    templates.Channel(channel, formatText),
+		html:    templates.Channel(channel, formatHTML),
+	}, nil
+}
+
+func (e *emailNotifier) Name() string {
+	return e.channel.DisplayName()
+}
+
+func (e *emailNotifier) Notify(notification *Notification) error {
+	return e.send(notification, "")
+}
+
+func (e *emailNotifier) SendReport(notification *Notification, filePath string) error {
+	return e.send(notification, filePath)
+}
+
+func (e *emailNotifier) Preview(notification *Notification) (string, error) {
+	return e.text.Render(notification)
+}
+
+// send формирует письмо с текстовой и HTML-версией уведомления и отправляет его
+func (e *emailNotifier) send(notification *Notification, attachment string) error {
+	plain, err := e.text.Render(notification)
+	if err != nil {
+		return err
+	}
+	htmlBody, err := e.html.Render(notification)
+	if err != nil {
+		return err
+	}
+
+	message, err := buildEmail(e.channel.From, e.channel.To, notification.DisplayTitle(),
+		plain, htmlBody, attachment)
+	if err != nil {
+		return err
+	}
+
+	return e.deliver(message)
+}
+
+// deliver передает письмо SMTP-серверу с учетом режима шифрования
+func (e *emailNotifier) deliver(message []byte) error {
+	tlsConfig := &tls.Config{ServerName: e.channel.Host}
+	dialer := &net.Dialer{Timeout: emailTimeout}
+
+	var conn net.Conn
+	var err error
+	if e.tlsMode == emailTLSImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", e.addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", e.addr)
+	}
+	if err != nil {
+		return i18n.Errorf("ошибка подключения к SMTP-серверу %s: %w", e.addr, err)
+	}
+	conn.SetDeadline(time.Now().Add(emailTimeout))
+
+	client, err := smtp.NewClient(conn, e.channel.Host)
+	if err != nil {
+		conn.Close()
+		return i18n.Errorf("ошибка SMTP-сеанса: %w", err)
+	}
+	defer client.Close()
+
+	if e.tlsMode == emailTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return i18n.Errorf("SMTP-сервер %s не поддерживает STARTTLS", e.addr)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return i18n.Errorf("ошибка STARTTLS: %w", err)
+		}
+	}
+
+	if e.channel.Username != "" {
+		auth := smtp.PlainAuth("", e.channel.Username, e.channel.Password, e.channel.Host)
+		if err := client.Auth(auth); err != nil {
+			return i18n.Errorf("ошибка аутентификации SMTP: %w", err)
+		}
+	}
+
+	from, _ := mail.ParseAddress(e.channel.From)
+	if err := client.Mail(from.Address); err != nil {
+		return i18n.Errorf("сервер отклонил отправителя: %w", err)
+	}
+	for _, to := range e.channel.To {
+		rcpt, _ := mail.ParseAddress(to)
+		if err := client.Rcpt(rcpt.Address); err != nil {
+			return i18n.Errorf("сервер отклонил получателя %s: %w", rcpt.Address, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return i18n.Errorf("ошибка SMTP DATA: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return i18n.Errorf("ошибка передачи письма: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return i18n.Errorf("сервер не принял письмо: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// buildEmail формирует MIME-письмо: multipart/alternative с текстом и HTML и,
+// если указан файл, multipart/mixed с отчетом во вложении
+func buildEmail(from string, to []string, subject, plain, htmlBody, attachment string) ([]byte, error) {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+
+	mixed := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixed.Boundary())
+
+	// Текстовая и HTML-версии письма
+	var alternative bytes.Buffer
+	alt := multipart.NewWriter(&alternative)
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", plain},
+		{"text/html; charset=utf-8", htmlBody},
+	} {
+		w, err := alt.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := alt.Close(); err != nil {
+		return nil, err
+	}
+
+	w, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + alt.Boundary()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(alternative.Bytes()); err != nil {
+		return nil, err
+	}
+
+	if attachment != "" {
+		data, err := os.ReadFile(attachment)
+		if err != nil {
+			return nil, i18n.Errorf("ошибка чтения отчета: %w", err)
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(attachment))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		w, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(attachment)})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64Lines(w, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+// writeBase64Lines записывает данные в base64 строками по 76 символов (RFC 2045)
+func writeBase64Lines(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := min(76, len(encoded))
+		if _, err := fmt.Fprintf(w, "%s\r\n", encoded[:n]); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
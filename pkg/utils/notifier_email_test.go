@@ -0,0 +1,261 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// smtpSession - команды и письмо, полученные SMTP-заглушкой за один сеанс
+type smtpSession struct {
+	auth []string // Команды AUTH
+	from string
+	rcpt []string
+	data []byte
+}
+
+// smtpStandIn запускает SMTP-сервер без шифрования, который принимает один сеанс
+// и передает его в канал после QUIT
+func smtpStandIn(t *testing.T) (host string, port int, sessions <-chan smtpSession) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	done := make(chan smtpSession, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tp := textproto.NewConn(conn)
+		var session smtpSession
+		tp.PrintfLine("220 aegis-test ready")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(command, "EHLO"):
+				tp.PrintfLine("250-aegis-test")
+				tp.PrintfLine("250 AUTH PLAIN")
+			case strings.HasPrefix(command, "AUTH"):
+				session.auth = append(session.auth, line)
+				tp.PrintfLine("235 2.7.0 Authentication successful")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				session.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+				tp.PrintfLine("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				session.rcpt = append(session.rcpt, strings.Trim(line[len("RCPT TO:"):], "<> "))
+				tp.PrintfLine("250 OK")
+			case command == "DATA":
+				tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+				session.data, err = tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				tp.PrintfLine("250 OK")
+			case command == "QUIT":
+				tp.PrintfLine("221 Bye")
+				done <- session
+				return
+			default:
+				tp.PrintfLine("502 Command not implemented")
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, done
+}
+
+// readPart возвращает тело части письма. Quoted-printable декодирует multipart.Reader,
+// base64 декодируется здесь
+func readPart(t *testing.T, part *multipart.Part) string {
+	t.Helper()
+
+	var r io.Reader = part
+	if part.Header.Get("Content-Transfer-Encoding") == "base64" {
+		r = base64.NewDecoder(base64.StdEncoding, part)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("чтение части письма: %v", err)
+	}
+	return string(data)
+}
+
+func TestEmailNotifierSendReport(t *testing.T) {
+	host, port, sessions := smtpStandIn(t)
+
+	report := filepath.Join(t.TempDir(), "report.csv")
+	reportData := "id,severity\nCVE-2024-0001,CRITICAL\n"
+	if err := os.WriteFile(report, []byte(reportData), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	notifier, err := newEmailNotifier(models.NotificationChannel{
+		Type:     models.NotificationChannelEmail,
+		Host:     host,
+		Port:     port,
+		TLS:      emailTLSNone,
+		Username: "aegis",
+		Password: "s3cr3t",
+		From:     "Aegis <aegis@example.com>",
+		To:       []string{"ops@example.com", "Security <sec@example.com>"},
+	}, NewTemplates("", logger))
+	if err != nil {
+		t.Fatalf("newEmailNotifier: %v", err)
+	}
+
+	notification := &Notification{
+		Event:     NotificationReport,
+		Title:     "Aegis: Отчет о сканировании",
+		Host:      "node-1",
+		Container: "api",
+	}
+	if err := notifier.(ReportSender).SendReport(notification, report); err != nil {
+		t.Fatalf("SendReport: %v", err)
+	}
+	session := <-sessions
+
+	// Аутентификация PLAIN: \x00пользователь\x00пароль
+	if len(session.auth) != 1 {
+		t.Fatalf("команды AUTH: %q", session.auth)
+	}
+	fields := strings.Fields(session.auth[0])
+	if len(fields) != 3 || !strings.EqualFold(fields[1], "PLAIN") {
+		t.Fatalf("команда AUTH: %q", session.auth[0])
+	}
+	credentials, err := base64.StdEncoding.DecodeString(fields[2])
+	if err != nil || string(credentials) != "\x00aegis\x00s3cr3t" {
+		t.Errorf("данные AUTH PLAIN: %q", credentials)
+	}
+
+	if session.from != "aegis@example.com" {
+		t.Errorf("MAIL FROM: %q", session.from)
+	}
+	if strings.Join(session.rcpt, ",") != "ops@example.com,sec@example.com" {
+		t.Errorf("RCPT TO: %q", session.rcpt)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(session.data))
+	if err != nil {
+		t.Fatalf("разбор письма: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != notification.DisplayTitle() {
+		t.Errorf("Subject = %q, ожидается %q", subject, notification.DisplayTitle())
+	}
+	if to := msg.Header.Get("To"); to != "ops@example.com, Security <sec@example.com>" {
+		t.Errorf("To = %q", to)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type письма: %q", msg.Header.Get("Content-Type"))
+	}
+	mixed := multipart.NewReader(msg.Body, params["boundary"])
+
+	// Первая часть - текстовая и HTML-версии
+	alternative, err := mixed.NextPart()
+	if err != nil {
+		t.Fatalf("часть multipart/alternative: %v", err)
+	}
+	mediaType, params, _ = mime.ParseMediaType(alternative.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("первая часть: %q", mediaType)
+	}
+	versions := multipart.NewReader(alternative, params["boundary"])
+	bodies := make(map[string]string)
+	for {
+		part, err := versions.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("версия письма: %v", err)
+		}
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		bodies[mediaType] = readPart(t, part)
+	}
+	if plain := bodies["text/plain"]; !strings.Contains(plain, "node-1") || strings.Contains(plain, "<") {
+		t.Errorf("текстовая версия: %q", plain)
+	}
+	if html := bodies["text/html"]; !strings.Contains(html, "node-1") || !strings.Contains(html, "<h2") {
+		t.Errorf("HTML-версия: %q", html)
+	}
+
+	// Вторая часть - отчет во вложении
+	attachment, err := mixed.NextPart()
+	if err != nil {
+		t.Fatalf("вложение: %v", err)
+	}
+	if name := attachment.FileName(); name != "report.csv" {
+		t.Errorf("имя вложения %q", name)
+	}
+	if data := readPart(t, attachment); data != reportData {
+		t.Errorf("содержимое вложения %q, ожидается %q", data, reportData)
+	}
+	if _, err := mixed.NextPart(); err != io.EOF {
+		t.Errorf("лишние части письма: %v", err)
+	}
+}
+
+func TestEmailNotifierDefaultPorts(t *testing.T) {
+	for _, tc := range []struct {
+		tls  string
+		port int
+		want string
+	}{
+		{"", 0, "587"},
+		{"", 465, "465"},
+		{emailTLSImplicit, 0, "465"},
+		{emailTLSNone, 0, "25"},
+		{emailTLSStartTLS, 2525, "2525"},
+	} {
+		notifier, err := newEmailNotifier(models.NotificationChannel{
+			Type: models.NotificationChannelEmail,
+			Host: "smtp.example.com",
+			Port: tc.port,
+			TLS:  tc.tls,
+			From: "aegis@example.com",
+			To:   []string{"ops@example.com"},
+		}, NewTemplates("", logrus.New()))
+		if err != nil {
+			t.Fatalf("newEmailNotifier(tls=%q, port=%d): %v", tc.tls, tc.port, err)
+		}
+		if _, port, _ := net.SplitHostPort(notifier.(*emailNotifier).addr); port != tc.want {
+			t.Errorf("tls=%q, port=%d: порт %s, ожидается %s", tc.tls, tc.port, port, tc.want)
+		}
+	}
+
+	if _, err := newEmailNotifier(models.NotificationChannel{
+		Type: models.NotificationChannelEmail,
+		Host: "smtp.example.com",
+		From: "aegis@example.com",
+	}, NewTemplates("", logrus.New())); err == nil {
+		t.Error("канал без получателей принят")
+	}
+}
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+)
+
+func init() {
+	RegisterNotifier(models.NotificationChannelTelegram, func(channel models.NotificationChannel, templates *Templates) (Notifier, error) {
+		if channel.Token == "" || channel.ChatID == "" {
+			return nil, i18n.Errorf("для канала telegram необходимо указать token и chat_id")
+		}
+		apiURL := strings.TrimRight(channel.URL, "/")
+		if apiURL == "" {
+			apiURL = "https://api.telegram.org"
+		}
+		return &telegramNotifier{
+			name:     channel.DisplayName(),
+			apiURL:   apiURL,
+			token:    channel.Token,
+			chatID:   channel.ChatID,
+			template: templates.Channel(channel, formatTelegram),
+		}, nil
+	})
+}
+
+// TelegramMessage представляет сообщение для Telegram API
+type TelegramMessage struct {
+	ChatID    string `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode,omitempty"`
+}
+
+// telegramNotifier отправляет уведомления через Telegram-бота
+type telegramNotifier struct {
+	name     string
+	apiURL   string // Адрес Bot API
+	token    string
+	chatID   string
+	template *MessageTemplate
+}
+
+func (t *telegramNotifier) Name() string {
+	return t.name
+}
+
+func (t *telegramNotifier) Notify(notification *Notification) error {
+	message, err := t.template.Render(notification)
+	if err != nil {
+		return err
+	}
+	return t.sendMessage(message)
+}
+
+func (t *telegramNotifier) SendReport(notification *Notification, filePath string) error {
+	caption, err := t.template.Render(notification)
+	if err != nil {
+		return err
+	}
+	return t.sendFile(filePath, caption)
+}
+
+func (t *telegramNotifier) Preview(notification *Notification) (string, error) {
+	return t.template.Render(notification)
+}
+
+// sendMessage отправляет текстовое сообщение в Telegram
+func (t *telegramNotifier) sendMessage(message string) error {
+	url := fmt.Sprintf("%s/bot%s/sendMessage", t.apiURL, t.token)
+
+	telegramMsg := TelegramMessage{
+		ChatID:    t.chatID,
+		Text:      message,
+		ParseMode: "HTML",
+	}
+
+	jsonData, err := json.Marshal(telegramMsg)
+	if err != nil {
+		return i18n.Errorf("ошибка маршалинга JSON: %w", err)
+	}
+
+	resp, err := notificationClient.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return i18n.Errorf("ошибка отправки HTTP запроса: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return i18n.Errorf("ошибка API Telegram: %s", string(body))
+	}
+
+	return nil
+}
+
+// sendFile отправляет файл в Telegram
+func (t *telegramNotifier) sendFile(filePath, caption string) error {
+	url := fmt.Sprintf("%s/bot%s/sendDocument", t.apiURL, t.token)
+
+	// Получаем тип MIME файла
+	fileType := "application/json"
+	if filepath.Ext(filePath) == ".csv" {
+		fileType = "text/csv"
+	}
+
+	// Открываем файл
+	file, err := os.Open(filePath)
+	if err != nil {
+		return i18n.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer file.Close()
+
+	// Создаем буфер для формирования multipart/form-data запроса
+	var requestBody bytes.Buffer
+
+	// Добавляем chat_id
+	requestBody.WriteString("--boundary\r\n")
+	requestBody.WriteString("Content-Disposition: form-data; name=\"chat_id\"\r\n\r\n")
+	requestBody.WriteString(t.chatID + "\r\n")
+
+	// Добавляем caption с HTML-разметкой
+	requestBody.WriteString("--boundary\r\n")
+	requestBody.WriteString("Content-Disposition: form-data; name=\"caption\"\r\n\r\n")
+	requestBody.WriteString(caption + "\r\n")
+	requestBody.WriteString("--boundary\r\n")
+	requestBody.WriteString("Content-Disposition: form-data; name=\"parse_mode\"\r\n\r\n")
+	requestBody.WriteString("HTML\r\n")
+
+	// Добавляем файл
+	requestBody.WriteString("--boundary\r\n")
+	requestBody.WriteString(fmt.Sprintf("Content-Disposition: form-data; name=\"document\"; filename=\"%s\"\r\n",
+		filepath.Base(filePath)))
+	requestBody.WriteString(fmt.Sprintf("Content-Type: %s\r\n\r\n", fileType))
+
+	// Копируем содержимое файла в буфер
+	if _, err := io.Copy(&requestBody, file); err != nil {
+		return i18n.Errorf("ошибка копирования файла в буфер: %w", err)
+	}
+
+	requestBody.WriteString("\r\n--boundary--\r\n")
+
+	// Отправляем запрос
+	req, err := http.NewRequest("POST", url, &requestBody)
+	if err != nil {
+		return i18n.Errorf("ошибка создания HTTP запроса: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
+
+	resp, err := notificationClient.Do(req)
+	if err != nil {
+		return i18n.Errorf("ошибка отправки HTTP запроса: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return i18n.Errorf("ошибка API Telegram: %s", string(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+)
+
+// notificationClient используется всеми HTTP-каналами уведомлений
+var notificationClient = &http.Client{Timeout: 15 * time.Second}
+
+func init() {
+	for channelType, payload := range map[string]payloadBuilder{
+		models.NotificationChannelSlack:      {formatSlack, slackPayload},
+		models.NotificationChannelMattermost: {formatMarkdown, mattermostPayload},
+		models.NotificationChannelTeams:      {formatMarkdown, teamsPayload},
+		models.NotificationChannelWebhook:    {"", webhookPayload},
+	} {
+		RegisterNotifier(channelType, newWebhookNotifier(payload))
+	}
+}
+
+// payloadBuilder описывает JSON, который получает входящий webhook
+type payloadBuilder struct {
+	format string // Формат текста сообщения, пустой - уведомление передается без шаблона
+	build  func(channel models.NotificationChannel, text string, notification *Notification) interface{}
+}
+
+// webhookNotifier отправляет уведомление POST-запросом с JSON, который формирует payload
+type webhookNotifier struct {
+	channel  models.NotificationChannel
+	payload  payloadBuilder
+	template *MessageTemplate
+}
+
+// newWebhookNotifier возвращает фабрику канала, доставляющего уведомления на входящий webhook
+func newWebhookNotifier(payload payloadBuilder) NotifierFactory {
+	return func(channel models.NotificationChannel, templates *Templates) (Notifier, error) {
+		if !strings.HasPrefix(channel.URL, "http://") && !strings.HasPrefix(channel.URL, "https://") {
+			return nil, i18n.Errorf("для канала %s необходимо указать url (http:// или https://)", channel.Type)
+		}
+		notifier := &webhookNotifier{channel: channel, payload: payload}
+		if payload.format != "" {
+			notifier.template = templates.Channel(channel, payload.format)
+		}
+		return notifier, nil
+	}
+}
+
+func (w *webhookNotifier) Name() string {
+	return w.channel.DisplayName()
+}
+
+// body формирует JSON запроса
+func (w *webhookNotifier) body(notification *Notification) ([]byte, error) {
+	var text string
+	if w.template != nil {
+		var err error
+		if text, err = w.template.Render(notification); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := json.Marshal(w.payload.build(w.channel, text, notification))
+	if err != nil {
+		return nil, i18n.Errorf("ошибка маршалинга JSON: %w", err)
+	}
+	return body, nil
+}
+
+func (w *webhookNotifier) Preview(notification *Notification) (string, error) {
+	body, err := w.body(notification)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, body, "", "  "); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func (w *webhookNotifier) Notify(notification *Notification) error {
+	body, err := w.body(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.channel.URL, bytes.NewReader(body))
+	if err != nil {
+		return i18n.Errorf("ошибка создания HTTP запроса: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.channel.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := notificationClient.Do(req)
+	if err != nil {
+		return i18n.Errorf("ошибка отправки HTTP запроса: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return i18n.Errorf("ответ %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
+
+// chatPayload представляет сообщение входящего webhook Slack и Mattermost
+type chatPayload struct {
+	Text     string `json:"text"`
+	Channel  string `json:"channel,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// slackPayload формирует сообщение для входящего webhook Slack
+func slackPayload(channel models.NotificationChannel, text string, _ *Notification) interface{} {
+	return chatPayload{Text: text, Channel: channel.Channel, Username: channel.Username}
+}
+
+// mattermostPayload формирует сообщение для входящего webhook Mattermost
+func mattermostPayload(channel models.NotificationChannel, text string, _ *Notification) interface{} {
+	return chatPayload{Text: text, Channel: channel.Channel, Username: channel.Username}
+}
+
+// teamsPayload формирует карточку для входящего webhook Microsoft Teams.
+// Teams объединяет строки, разделенные одиночным переводом строки
+func teamsPayload(_ models.NotificationChannel, text string, notification *Notification) interface{} {
+	themeColor := "2EB886"
+	if notification.Event == NotificationScanError ||
+		(notification.Vulnerabilities != nil && notification.Vulnerabilities.Critical > 0) {
+		themeColor = "D63333"
+	}
+	return map[string]string{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    notification.DisplayTitle(),
+		"themeColor": themeColor,
+		"text":       strings.ReplaceAll(text, "\n", "\n\n"),
+	}
+}
+
+// webhookPayload передает уведомление в исходном виде для произвольного получателя
+func webhookPayload(_ models.NotificationChannel, _ string, notification *Notification) interface{} {
+	return struct {
+		*Notification
+		DurationMS int64 `json:"duration_ms,omitempty"`
+	}{notification, notification.Duration.Milliseconds()}
+}
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+)
+
+// routeSeverities содержит допустимые значения min_severity
+var routeSeverities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
+
+// RouteDecision описывает, будет ли уведомление отправлено в канал
+type RouteDecision struct {
+	Channel string
+	Deliver bool
+	Reason  string // Причина, по которой уведомление не отправляется
+}
+
+// validateRoute проверяет правила маршрутизации
+func validateRoute(route models.NotificationRoute) error {
+	if route.MinSeverity != "" {
+		valid := false
+		for _, severity := range routeSeverities {
+			if strings.EqualFold(route.MinSeverity, severity) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return i18n.Errorf("недопустимая серьезность min_severity: %s (допустимо: %s)",
+				route.MinSeverity, strings.Join(routeSeverities, ", "))
+		}
+	}
+
+	for _, patterns := range [][]string{route.Hosts, route.Images, route.Tags} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return i18n.Errorf("некорректный шаблон: %s", pattern)
+			}
+		}
+	}
+
+	if route.QuietHours != "" {
+		if _, _, err := parseQuietHours(route.QuietHours); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkRoute возвращает причину, по которой правила не пропускают уведомление,
+// или пустую строку. Правила должны быть проверены validateRoute
+func checkRoute(route models.NotificationRoute, n *Notification) string {
+	if len(route.Hosts) > 0 && !matchAny(route.Hosts, n.Host, n.HostID) {
+		return i18n.Sprintf("хост %s не соответствует hosts", n.Host)
+	}
+
+	if len(route.Images) > 0 && (n.Image == "" || !matchAny(route.Images, models.ImageRepository(n.Image))) {
+		return i18n.Sprintf("образ %q не соответствует images", n.Image)
+	}
+
+	if len(route.Tags) > 0 {
+		tag := models.ImageTag(n.Image)
+		if n.Image == "" || !matchAny(route.Tags, tag) {
+			return i18n.Sprintf("тег образа %q не соответствует tags", tag)
+		}
+	}
+
+	// Серьезность и изменения оцениваются только для результатов сканирования
+	if n.Event == NotificationScanCompleted {
+		if route.MinSeverity != "" && n.AtLeast(route.MinSeverity) == 0 {
+			return i18n.Sprintf("нет находок не ниже %s", strings.ToUpper(route.MinSeverity))
+		}
+		if route.OnlyOnChange && !n.Changed() {
+			return i18n.Sprintf("уязвимости не изменились с предыдущего сканирования %s", n.PreviousScanID)
+		}
+	}
+
+	if route.QuietHours != "" {
+		start, end, _ := parseQuietHours(route.QuietHours)
+		if inQuietHours(n.Timestamp.Local(), start, end) {
+			return i18n.Sprintf("тихие часы %s", route.QuietHours)
+		}
+	}
+
+	return ""
+}
+
+// matchAny проверяет, соответствует ли хотя бы одно значение хотя бы одному шаблону
+func matchAny(patterns []string, values ...string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			if ok, _ := path.Match(pattern, value); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseQuietHours разбирает интервал ЧЧ:ММ-ЧЧ:ММ и возвращает его границы
+// в минутах от начала суток. Интервал может переходить через полночь
+func parseQuietHours(value string) (start, end int, err error) {
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, 0, i18n.Errorf("некорректный интервал quiet_hours: %s (ожидается ЧЧ:ММ-ЧЧ:ММ)", value)
+	}
+
+	parse := func(s string) (int, error) {
+		t, err := time.Parse("15:04", strings.TrimSpace(s))
+		if err != nil {
+			return 0, i18n.Errorf("некорректное время в quiet_hours: %s", s)
+		}
+		return t.Hour()*60 + t.Minute(), nil
+	}
+
+	if start, err = parse(from); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parse(to); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// inQuietHours проверяет, попадает ли время в интервал [start, end)
+func inQuietHours(t time.Time, start, end int) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
@@ -0,0 +1,324 @@
+package utils
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/aegis/aegis-cli/pkg/i18n"
+	"github.com/aegis/aegis-cli/pkg/models"
+	"github.com/sirupsen/logrus"
+)
+
+// builtinTemplates содержит шаблоны сообщений по умолчанию: на русском языке в templates/
+// и переводы в templates/<язык>/
+//
+//go:embed templates/*.tmpl templates/en/*.tmpl
+var builtinTemplates embed.FS
+
+// Форматы сообщений каналов
+const (
+	formatText     = "text"     // Текст без разметки: системные уведомления и текстовая часть писем
+	formatSlack    = "slack"    // Slack mrkdwn
+	formatMarkdown = "markdown" // Markdown Mattermost и Teams
+	formatTelegram = "telegram" // HTML Telegram
+	formatHTML     = "html"     // HTML-версия писем
+)
+
+// templateFormat описывает встроенный шаблон формата и правила экранирования
+type templateFormat struct {
+	file string // Файл встроенного шаблона
+	html bool   // Шаблон выполняется html/template с автоматическим экранированием
+	ext  string // Расширение файлов, переопределяющих шаблон
+	bold func(string) interface{}
+	esc  func(string) string
+}
+
+var templateFormats = map[string]templateFormat{
+	formatText: {
+		file: "text.tmpl", ext: ".tmpl",
+		bold: func(s string) interface{} { return s },
+		esc:  func(s string) string { return s },
+	},
+	formatSlack: {
+		file: "markdown.tmpl", ext: ".tmpl",
+		bold: func(s string) interface{} { return "*" + s + "*" },
+		esc:  strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace,
+	},
+	formatMarkdown: {
+		file: "markdown.tmpl", ext: ".tmpl",
+		bold: func(s string) interface{} { return "**" + s + "**" },
+		esc:  escapeMarkdown,
+	},
+	formatTelegram: {
+		file: "telegram.tmpl", html: true, ext: ".tmpl",
+		bold: htmlBold,
+		esc:  func(s string) string { return s },
+	},
+	formatHTML: {
+		file: "email.html.tmpl", html: true, ext: ".html.tmpl",
+		bold: htmlBold,
+		esc:  func(s string) string { return s },
+	},
+}
+
+// templateEvents содержит события, для которых можно переопределить шаблон
+var templateEvents = []string{NotificationScanCompleted, NotificationScanError, NotificationReport, NotificationScanSummary, NotificationDigest}
+
+// executor выполняет именованный шаблон text/template или html/template
+type executor interface {
+	ExecuteTemplate(w io.Writer, name string, data interface{}) error
+}
+
+// Templates загружает шаблоны сообщений: встроенные и переопределенные пользователем
+// в каталоге шаблонов. Файл <каталог>/<канал>/<событие>.tmpl заменяет шаблон события
+// для канала, где <канал> - имя канала из конфигурации или его тип
+type Templates struct {
+	dir    string
+	logger *logrus.Logger
+}
+
+// NewTemplates создает загрузчик шаблонов. Пустой dir означает только встроенные шаблоны
+func NewTemplates(dir string, logger *logrus.Logger) *Templates {
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, rest)
+		}
+	}
+	return &Templates{dir: dir, logger: logger}
+}
+
+// MessageTemplate формирует сообщения одного канала в его формате
+type MessageTemplate struct {
+	channel string
+	builtin executor
+	custom  executor        // Встроенные шаблоны с переопределенными событиями
+	events  map[string]bool // События, шаблон которых переопределен
+	logger  *logrus.Logger
+}
+
+// Channel готовит шаблоны канала в указанном формате. Файлы переопределения, которые
+// не удалось разобрать, записываются в журнал и заменяются встроенными шаблонами
+func (t *Templates) Channel(channel models.NotificationChannel, format string) *MessageTemplate {
+	builtin, err := parseTemplates(format, nil)
+	if err != nil {
+		// Встроенные шаблоны проверяются при сборке, ошибка здесь - ошибка программы
+		panic(err)
+	}
+
+	m := &MessageTemplate{
+		channel: channel.DisplayName(),
+		builtin: builtin,
+		events:  make(map[string]bool),
+		logger:  t.logger,
+	}
+
+	overrides := make(map[string]string)
+	for _, event := range templateEvents {
+		path, text, err := t.lookup(channel, format, event)
+		if err != nil {
+			t.logger.WithError(err).WithField("channel", m.channel).Error("Ошибка чтения шаблона уведомления")
+			continue
+		}
+		if path == "" {
+			continue
+		}
+
+		// Каждый файл проверяется отдельно, чтобы ошибка в одном не отключала остальные
+		if _, err := parseTemplates(format, map[string]string{event: text}); err != nil {
+			t.logger.WithError(err).WithFields(logrus.Fields{
+				"channel":  m.channel,
+				"template": path,
+			}).Error("Ошибка в шаблоне уведомления, используется встроенный шаблон")
+			continue
+		}
+		overrides[event] = text
+		m.events[event] = true
+	}
+
+	if len(overrides) > 0 {
+		m.custom, _ = parseTemplates(format, overrides)
+	}
+	return m
+}
+
+// lookup ищет файл шаблона события сначала в каталоге с именем канала, затем с его типом
+func (t *Templates) lookup(channel models.NotificationChannel, format, event string) (string, string, error) {
+	if t.dir == "" {
+		return "", "", nil
+	}
+
+	dirs := []string{channel.Type}
+	if channel.Name != "" && channel.Name != channel.Type {
+		dirs = []string{channel.Name, channel.Type}
+	}
+
+	for _, dir := range dirs {
+		path := filepath.Join(t.dir, dir, event+templateFormats[format].ext)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", "", err
+		}
+		return path, string(data), nil
+	}
+	return "", "", nil
+}
+
+// Render формирует текст уведомления с заголовком на языке пользователя. Если
+// переопределенный шаблон завершился ошибкой, используется встроенный
+func (m *MessageTemplate) Render(notification *Notification) (string, error) {
+	translated := *notification
+	translated.Title = notification.DisplayTitle()
+	notification = &translated
+
+	if m.events[notification.Event] {
+		var buf bytes.Buffer
+		err := m.custom.ExecuteTemplate(&buf, notification.Event, notification)
+		if err == nil {
+			return strings.TrimSpace(buf.String()), nil
+		}
+		m.logger.WithError(err).WithFields(logrus.Fields{
+			"channel": m.channel,
+			"event":   notification.Event,
+		}).Error("Ошибка выполнения шаблона уведомления, используется встроенный шаблон")
+	}
+
+	var buf bytes.Buffer
+	if err := m.builtin.ExecuteTemplate(&buf, notification.Event, notification); err != nil {
+		return "", i18n.Errorf("ошибка формирования сообщения: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// parseTemplates разбирает встроенный шаблон формата и переопределения событий
+func parseTemplates(format string, overrides map[string]string) (executor, error) {
+	f, ok := templateFormats[format]
+	if !ok {
+		return nil, i18n.Errorf("неизвестный формат сообщений: %s", format)
+	}
+
+	builtin, err := builtinTemplates.ReadFile("templates/" + i18n.Language() + "/" + f.file)
+	if err != nil {
+		// Для языка исходных сообщений и языков без перевода шаблонов
+		builtin, err = builtinTemplates.ReadFile("templates/" + f.file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	funcs := templateFuncs(f)
+	if f.html {
+		set, err := htmltemplate.New(format).Funcs(funcs).Parse(string(builtin))
+		if err != nil {
+			return nil, err
+		}
+		for event, text := range overrides {
+			if _, err := set.New(event).Parse(text); err != nil {
+				return nil, err
+			}
+		}
+		return set, nil
+	}
+
+	set, err := template.New(format).Funcs(funcs).Parse(string(builtin))
+	if err != nil {
+		return nil, err
+	}
+	for event, text := range overrides {
+		if _, err := set.New(event).Parse(text); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// severityLine - строка статистики находок одного уровня серьезности
+type severityLine struct {
+	Severity string
+	Icon     string
+	Label    string
+	Count    int
+}
+
+// templateFuncs возвращает функции, доступные в шаблонах сообщений:
+//
+//	bold, escape         - выделение и экранирование текста по правилам формата
+//	severities COUNTS    - строки статистики от критических до низких
+//	counts LABEL COUNTS  - статистика с подписью для вложенного шаблона
+//	top N FINDINGS       - первые N наиболее опасных находок
+//	icon SEVERITY        - значок уровня серьезности
+//	upper, lower, join   - функции strings
+func templateFuncs(f templateFormat) map[string]interface{} {
+	return map[string]interface{}{
+		"bold":   f.bold,
+		"escape": f.esc,
+		"severities": func(c *models.SeverityCounts) []severityLine {
+			if c == nil {
+				c = &models.SeverityCounts{}
+			}
+			return []severityLine{
+				{"CRITICAL", severityIcon("CRITICAL"), i18n.T("Критических"), c.Critical},
+				{"HIGH", severityIcon("HIGH"), i18n.T("Высоких"), c.High},
+				{"MEDIUM", severityIcon("MEDIUM"), i18n.T("Средних"), c.Medium},
+				{"LOW", severityIcon("LOW"), i18n.T("Низких"), c.Low},
+			}
+		},
+		"counts": func(label string, c *models.SeverityCounts) interface{} {
+			return struct {
+				Label  string
+				Counts *models.SeverityCounts
+			}{label, c}
+		},
+		"top": func(n int, findings []NotificationFinding) []NotificationFinding {
+			if n < len(findings) {
+				return findings[:n]
+			}
+			return findings
+		},
+		"icon":  severityIcon,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"join":  strings.Join,
+	}
+}
+
+// severityIcon возвращает значок уровня серьезности
+func severityIcon(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return "🔴"
+	case "HIGH":
+		return "🟠"
+	case "MEDIUM":
+		return "🟡"
+	case "LOW":
+		return "🟢"
+	default:
+		return "⚪"
+	}
+}
+
+// htmlBold выделяет текст в HTML, экранируя его
+func htmlBold(s string) interface{} {
+	return htmltemplate.HTML("<b>" + htmltemplate.HTMLEscapeString(s) + "</b>")
+}
+
+// escapeMarkdown экранирует символы, меняющие начертание текста в Markdown
+func escapeMarkdown(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune("\\`*_[]~", r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -9,7 +9,9 @@ CREATE TABLE IF NOT EXISTS hosts (
     status TEXT NOT NULL,
     last_seen TIMESTAMP,
     created_at TIMESTAMP NOT NULL,
-    description TEXT
+    description TEXT,
+    posture TEXT NOT NULL DEFAULT '',
+    agent_info TEXT NOT NULL DEFAULT ''
 );
 
 -- Таблица контейнеров
@@ -21,6 +23,11 @@ CREATE TABLE IF NOT EXISTS containers (
     status TEXT NOT NULL,
     created_at TIMESTAMP NOT NULL,
     updated_at TIMESTAMP NOT NULL,
+    runtime TEXT NOT NULL DEFAULT '',
+    pod_name TEXT NOT NULL DEFAULT '',
+    pod_namespace TEXT NOT NULL DEFAULT '',
+    workload_kind TEXT NOT NULL DEFAULT '',
+    workload_name TEXT NOT NULL DEFAULT '',
     FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE
 );
 
@@ -34,6 +41,7 @@ CREATE TABLE IF NOT EXISTS scans (
     finished_at TIMESTAMP,
     result_path TEXT,
     error_msg TEXT,
+    image_digest TEXT NOT NULL DEFAULT '',
     FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE,
     FOREIGN KEY (container_id) REFERENCES containers(id) ON DELETE CASCADE
 );
@@ -58,6 +66,67 @@ CREATE TABLE IF NOT EXISTS vulnerabilities (
     FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE
 );
 
+-- Таблица находок (секреты и ошибки конфигурации)
+CREATE TABLE IF NOT EXISTS findings (
+    id TEXT PRIMARY KEY,
+    scan_id TEXT NOT NULL,
+    container_id TEXT NOT NULL,
+    host_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    rule_id TEXT NOT NULL,
+    severity TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    target TEXT NOT NULL DEFAULT '',
+    location TEXT NOT NULL DEFAULT '',
+    start_line INTEGER NOT NULL DEFAULT 0,
+    end_line INTEGER NOT NULL DEFAULT 0,
+    resolution TEXT NOT NULL DEFAULT '',
+    discovered_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (scan_id) REFERENCES scans(id) ON DELETE CASCADE,
+    FOREIGN KEY (container_id) REFERENCES containers(id) ON DELETE CASCADE,
+    FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE
+);
+
+-- Таблица результатов аудита конфигурации контейнеров
+CREATE TABLE IF NOT EXISTS audit_findings (
+    id TEXT PRIMARY KEY,
+    host_id TEXT NOT NULL,
+    container_id TEXT NOT NULL,
+    container_name TEXT NOT NULL,
+    rule_id TEXT NOT NULL,
+    severity TEXT NOT NULL,
+    title TEXT NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    remediation TEXT NOT NULL DEFAULT '',
+    audited_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE
+);
+
+-- Таблица SBOM (по одному документу на образ и формат)
+CREATE TABLE IF NOT EXISTS sboms (
+    id TEXT PRIMARY KEY,
+    image_digest TEXT NOT NULL,
+    image TEXT NOT NULL,
+    format TEXT NOT NULL,
+    scan_id TEXT NOT NULL,
+    host_id TEXT NOT NULL,
+    container_id TEXT NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (image_digest, format)
+);
+
+-- Таблица пакетов из SBOM
+CREATE TABLE IF NOT EXISTS sbom_packages (
+    id TEXT PRIMARY KEY,
+    image_digest TEXT NOT NULL,
+    name TEXT NOT NULL,
+    version TEXT NOT NULL,
+    type TEXT NOT NULL,
+    purl TEXT NOT NULL
+);
+
 -- Таблица хуков
 CREATE TABLE IF NOT EXISTS hooks (
     id TEXT PRIMARY KEY,
@@ -67,7 +136,24 @@ CREATE TABLE IF NOT EXISTS hooks (
     timeout_seconds INTEGER NOT NULL,
     enabled BOOLEAN NOT NULL,
     created_at TIMESTAMP NOT NULL,
-    updated_at TIMESTAMP NOT NULL
+    updated_at TIMESTAMP NOT NULL,
+    payload TEXT NOT NULL DEFAULT '',
+    type TEXT NOT NULL DEFAULT '',
+    url TEXT NOT NULL DEFAULT '',
+    headers TEXT NOT NULL DEFAULT '',
+    payload_template TEXT NOT NULL DEFAULT '',
+    secret TEXT NOT NULL DEFAULT '',
+    host_filter TEXT NOT NULL DEFAULT '',
+    image_pattern TEXT NOT NULL DEFAULT '',
+    min_severity TEXT NOT NULL DEFAULT '',
+    threshold INTEGER NOT NULL DEFAULT 0,
+    run_as_user TEXT NOT NULL DEFAULT '',
+    run_as_group TEXT NOT NULL DEFAULT '',
+    work_dir TEXT NOT NULL DEFAULT '',
+    env_allowlist TEXT NOT NULL DEFAULT '',
+    max_output_bytes INTEGER NOT NULL DEFAULT 0,
+    cpu_limit_seconds INTEGER NOT NULL DEFAULT 0,
+    memory_limit_mb INTEGER NOT NULL DEFAULT 0
 );
 
 -- Таблица выполнений хуков
@@ -80,8 +166,53 @@ CREATE TABLE IF NOT EXISTS hook_executions (
     error_msg TEXT,
     started_at TIMESTAMP NOT NULL,
     finished_at TIMESTAMP NOT NULL,
-    FOREIGN KEY (hook_id) REFERENCES hooks(id) ON DELETE CASCADE,
-    FOREIGN KEY (scan_id) REFERENCES scans(id) ON DELETE CASCADE
+    hook_name TEXT NOT NULL DEFAULT '',
+    event TEXT NOT NULL DEFAULT '',
+    host_id TEXT NOT NULL DEFAULT '',
+    exit_code INTEGER NOT NULL DEFAULT 0,
+    duration_ms INTEGER NOT NULL DEFAULT 0
+);
+
+-- Таблица недоставленных событий webhook
+CREATE TABLE IF NOT EXISTS hook_dead_letters (
+    id TEXT PRIMARY KEY,
+    hook_id TEXT NOT NULL,
+    hook_name TEXT NOT NULL,
+    event TEXT NOT NULL,
+    scan_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    status_code INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- Очередь уведомлений, ожидающих отправки сводкой (по строке на канал и событие)
+CREATE TABLE IF NOT EXISTS notification_queue (
+    id TEXT PRIMARY KEY,
+    channel TEXT NOT NULL,
+    event TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- Отправленные сообщения каналов для ограничения частоты и расписания дайджестов
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id TEXT PRIMARY KEY,
+    channel TEXT NOT NULL,
+    event TEXT NOT NULL,
+    events INTEGER NOT NULL,
+    sent_at TIMESTAMP NOT NULL
+);
+
+-- Отпечатки отправленных находок для подавления повторов
+CREATE TABLE IF NOT EXISTS notification_fingerprints (
+    channel TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    sent_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (channel, fingerprint)
 );
 
 -- Таблица стратегий восстановления
@@ -100,7 +231,7 @@ INSERT INTO remediation_strategies (id, name, type, estimated_downtime, command,
 VALUES 
     ('strategy-1', 'Горячее обновление', 'hot-patch', 'Нет простоя', 'apt-get update && apt-get upgrade -y {{package}}', 'Обновление пакета без перезапуска контейнера', CURRENT_TIMESTAMP),
     ('strategy-2', 'Перезапуск', 'restart', '10-30 секунд', 'docker restart {{container_id}}', 'Перезапуск контейнера после обновления образа', CURRENT_TIMESTAMP),
-    ('strategy-3', 'Постепенное обновление', 'rolling-update', '1-5 минут на узел', 'kubectl rollout restart deployment/{{deployment_name}}', 'Постепенное обновление контейнеров в Kubernetes', CURRENT_TIMESTAMP);
+    ('strategy-3', 'Постепенное обновление', 'rolling-update', '1-5 минут на узел', 'kubectl rollout restart deployment/{{deployment_name}} -n {{namespace}}', 'Постепенное обновление контейнеров в Kubernetes', CURRENT_TIMESTAMP);
 
 -- Индексы для ускорения запросов
 CREATE INDEX IF NOT EXISTS idx_containers_host_id ON containers(host_id);
@@ -111,5 +242,12 @@ CREATE INDEX IF NOT EXISTS idx_vulnerabilities_container_id ON vulnerabilities(c
 CREATE INDEX IF NOT EXISTS idx_vulnerabilities_host_id ON vulnerabilities(host_id);
 CREATE INDEX IF NOT EXISTS idx_vulnerabilities_severity ON vulnerabilities(severity);
 CREATE INDEX IF NOT EXISTS idx_hook_executions_hook_id ON hook_executions(hook_id);
-CREATE INDEX IF NOT EXISTS idx_hook_executions_scan_id ON hook_executions(scan_id); 
- 
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_hook_executions_scan_id ON hook_executions(scan_id);
+CREATE INDEX IF NOT EXISTS idx_hook_executions_started_at ON hook_executions(started_at);
+CREATE INDEX IF NOT EXISTS idx_sbom_packages_name ON sbom_packages(name);
+CREATE INDEX IF NOT EXISTS idx_sbom_packages_image_digest ON sbom_packages(image_digest);
+CREATE INDEX IF NOT EXISTS idx_findings_scan_id ON findings(scan_id);
+CREATE INDEX IF NOT EXISTS idx_findings_kind ON findings(kind);
+CREATE INDEX IF NOT EXISTS idx_audit_findings_host_id ON audit_findings(host_id);
+CREATE INDEX IF NOT EXISTS idx_notification_queue_channel ON notification_queue(channel);
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_sent_at ON notification_deliveries(sent_at);